@@ -0,0 +1,55 @@
+package apidocs
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// Swagger UI files loaded by the docs page
+var docsAssets = []string{"swagger-ui.css", "swagger-ui-bundle.js"}
+
+// Docs page which renders the OpenAPI spec served by the same service
+//
+// Swagger UI assets are vendored through the swaggo/files module and served by the service itself,
+// So the page doesn't load any script from a CDN. URLs are relative, So the page works behind the API gateway prefix as well
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>%s</title>
+  <link rel="stylesheet" href="assets/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="assets/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: "../openapi.json", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+
+// Serve the OpenAPI spec at /openapi.json along with its docs page at /docs/
+func Routes(engine *gin.Engine, spec []byte, title string) {
+	page := []byte(fmt.Sprintf(docsPage, html.EscapeString(title)))
+
+	engine.GET("/openapi.json", func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+	})
+
+	engine.GET("/docs/", func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	})
+
+	assets := http.FS(swaggerFiles.FS)
+	for _, name := range docsAssets {
+		name := name
+		engine.GET("/docs/assets/"+name, func(ctx *gin.Context) {
+			ctx.FileFromFS(name, assets)
+		})
+	}
+}
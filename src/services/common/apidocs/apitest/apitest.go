@@ -0,0 +1,217 @@
+// Helpers for testing the REST APIs against their OpenAPI spec
+package apitest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/gin-gonic/gin"
+)
+
+// Routes which are served outside of the OpenAPI spec
+var undocumentedRoutes = []string{"/health-check/", "/openapi.json", "/docs/", "/docs/assets/"}
+
+// XML responses like the RSS feeds are validated as plain strings
+func init() {
+	openapi3filter.RegisterBodyDecoder("application/rss+xml", openapi3filter.FileBodyDecoder)
+}
+
+// OpenAPI spec along with the router which finds its operations
+type Spec struct {
+	Doc    *openapi3.T
+	router routers.Router
+
+	// Operations which were served with a successful response by the validator
+	mu        sync.Mutex
+	succeeded map[string]bool
+}
+
+// Load the given OpenAPI spec, Fails the test if the spec is not valid
+func LoadSpec(t testing.TB, data []byte) *Spec {
+	t.Helper()
+
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		t.Fatalf("loading OpenAPI spec: %v", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Fatalf("invalid OpenAPI spec: %v", err)
+	}
+
+	// Operations are matched by their paths only, Services are served behind the API gateway prefix
+	routerDoc := *doc
+	routerDoc.Servers = nil
+
+	router, err := legacy.NewRouter(&routerDoc)
+	if err != nil {
+		t.Fatalf("building OpenAPI router: %v", err)
+	}
+	return &Spec{Doc: doc, router: router, succeeded: make(map[string]bool)}
+}
+
+// Convert a gin route path into the OpenAPI path template, Like /api/v1/:id/ into /api/v1/{id}/
+func specPath(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for idx, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			segments[idx] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isUndocumented(path string) bool {
+	for _, route := range undocumentedRoutes {
+		if path == route || (strings.HasSuffix(route, "/") && strings.HasPrefix(path, route)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check that every route of the engine is an operation in the spec and every operation is served by the engine,
+// Extra routes which are not documented can be passed as well.
+func (s *Spec) CheckRoutes(t testing.TB, routes gin.RoutesInfo, undocumented ...string) {
+	t.Helper()
+
+	served := make(map[string]bool)
+	for _, route := range routes {
+		path := specPath(route.Path)
+		if isUndocumented(path) || slices.Contains(undocumented, path) {
+			continue
+		}
+
+		served[route.Method+" "+path] = true
+		if s.Doc.Paths.Find(path) == nil || s.Doc.Paths.Find(path).GetOperation(route.Method) == nil {
+			t.Errorf("route %s %s is not documented in the OpenAPI spec", route.Method, route.Path)
+		}
+	}
+
+	for path, item := range s.Doc.Paths.Map() {
+		for method := range item.Operations() {
+			if !served[method+" "+path] {
+				t.Errorf("operation %s %s of the OpenAPI spec is not served", method, path)
+			}
+		}
+	}
+}
+
+// Response writer which keeps a copy of the response body for validating it
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+// Middleware which validates the requests and responses of the documented routes against the spec
+//
+// Every response should be documented for its operation and status code and match its schema.
+// Requests which don't match the spec are allowed only if they are rejected by the handler, So the validation
+// errors are reported only for the successful responses.
+func (s *Spec) Validator(t testing.TB) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		path := specPath(ctx.FullPath())
+		if ctx.FullPath() == "" || isUndocumented(path) {
+			ctx.Next()
+			return
+		}
+
+		route, pathParams, err := s.router.FindRoute(ctx.Request)
+		if err != nil {
+			t.Errorf("%s %s: route is not found in the OpenAPI spec: %v", ctx.Request.Method, ctx.Request.URL.Path, err)
+			ctx.Next()
+			return
+		}
+
+		var reqBody []byte
+		if ctx.Request.Body != nil {
+			reqBody, _ = io.ReadAll(ctx.Request.Body)
+			ctx.Request.Body = io.NopCloser(bytes.NewReader(reqBody))
+		}
+
+		// Request is validated on a copy, So the handler reads the body as it was sent
+		validationReq := ctx.Request.Clone(ctx.Request.Context())
+		validationReq.Body = io.NopCloser(bytes.NewReader(reqBody))
+
+		reqInput := &openapi3filter.RequestValidationInput{
+			Request:    validationReq,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				MultiError:         true,
+			},
+		}
+		reqErr := openapi3filter.ValidateRequest(ctx.Request.Context(), reqInput)
+
+		writer := &recordingWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+		ctx.Next()
+
+		status := writer.Status()
+		if status >= http.StatusOK && status < http.StatusMultipleChoices {
+			s.mu.Lock()
+			s.succeeded[ctx.Request.Method+" "+route.Path] = true
+			s.mu.Unlock()
+		}
+
+		if reqErr != nil && status < http.StatusBadRequest {
+			t.Errorf("%s %s: request doesn't match the OpenAPI spec but got status %d: %v", ctx.Request.Method, ctx.Request.URL.Path, status, reqErr)
+		}
+
+		resInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: reqInput,
+			Status:                 status,
+			Header:                 writer.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(writer.body.Bytes())),
+			Options: &openapi3filter.Options{
+				IncludeResponseStatus: true,
+				MultiError:            true,
+			},
+		}
+		if err := openapi3filter.ValidateResponse(ctx.Request.Context(), resInput); err != nil {
+			t.Errorf("%s %s: response with status %d doesn't match the OpenAPI spec: %v\n%s", ctx.Request.Method, ctx.Request.URL.Path, status, err, writer.body.String())
+		}
+	}
+}
+
+// Check that every operation documenting a successful response was served with one through the validator,
+// So that the response schemas of the operations are validated at least once.
+func (s *Spec) CheckSucceeded(t testing.TB) {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for path, item := range s.Doc.Paths.Map() {
+		for method, operation := range item.Operations() {
+			documented := false
+			for status := range operation.Responses.Map() {
+				documented = documented || strings.HasPrefix(status, "2")
+			}
+
+			if documented && !s.succeeded[method+" "+path] {
+				t.Errorf("operation %s %s is not served with a successful response", method, path)
+			}
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Machine readable error code, Sent along with the message so the clients don't have to parse the messages
+type Code string
+
+const (
+	CodeInvalidArgument    Code = "invalid_argument"
+	CodeUnauthenticated    Code = "unauthenticated"
+	CodePermissionDenied   Code = "permission_denied"
+	CodeNotFound           Code = "not_found"
+	CodeAlreadyExists      Code = "already_exists"
+	CodeFailedPrecondition Code = "failed_precondition"
+	CodeExpired            Code = "expired"
+	CodeInternal           Code = "internal"
+	CodeUnavailable        Code = "unavailable"
+)
+
+// Postgres error codes of the constraint violations
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+var httpStatuses = map[Code]int{
+	CodeInvalidArgument:    http.StatusBadRequest,
+	CodeUnauthenticated:    http.StatusUnauthorized,
+	CodePermissionDenied:   http.StatusForbidden,
+	CodeNotFound:           http.StatusNotFound,
+	CodeAlreadyExists:      http.StatusConflict,
+	CodeFailedPrecondition: http.StatusConflict,
+	CodeExpired:            http.StatusGone,
+	CodeInternal:           http.StatusInternalServerError,
+	CodeUnavailable:        http.StatusServiceUnavailable,
+}
+
+var grpcCodes = map[Code]codes.Code{
+	CodeInvalidArgument:    codes.InvalidArgument,
+	CodeUnauthenticated:    codes.Unauthenticated,
+	CodePermissionDenied:   codes.PermissionDenied,
+	CodeNotFound:           codes.NotFound,
+	CodeAlreadyExists:      codes.AlreadyExists,
+	CodeFailedPrecondition: codes.FailedPrecondition,
+	CodeExpired:            codes.FailedPrecondition,
+	CodeInternal:           codes.Internal,
+	CodeUnavailable:        codes.Unavailable,
+}
+
+// HTTP status code of the error code, Unknown codes are treated as internal errors
+func (c Code) HTTPStatus() int {
+	if httpStatus, exists := httpStatuses[c]; exists {
+		return httpStatus
+	}
+	return http.StatusInternalServerError
+}
+
+// gRPC status code of the error code, Unknown codes are treated as internal errors
+func (c Code) GRPCCode() codes.Code {
+	if grpcCode, exists := grpcCodes[c]; exists {
+		return grpcCode
+	}
+	return codes.Internal
+}
+
+// Validation error of a single request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error returned by the REST & gRPC APIs
+//
+// Message is shown to the clients as it is, While the wrapped error is only logged.
+type Error struct {
+	Code    Code
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Add a validation error of the given request field
+func (e *Error) WithField(field, message string) *Error {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+	return e
+}
+
+// Convert the error into a gRPC status, Used by the gRPC server for errors returned by the handlers
+func (e *Error) GRPCStatus() *status.Status {
+	st := status.New(e.Code.GRPCCode(), e.Message)
+	if len(e.Fields) == 0 {
+		return st
+	}
+
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       field.Field,
+			Description: field.Message,
+		})
+	}
+
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		return detailed
+	}
+	return st
+}
+
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Wrap the given error with a code & message which can be shown to the clients
+func Wrap(err error, code Code, message string) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+func InvalidArgument(message string) *Error {
+	return New(CodeInvalidArgument, message)
+}
+
+func Unauthenticated(message string) *Error {
+	return New(CodeUnauthenticated, message)
+}
+
+func PermissionDenied(message string) *Error {
+	return New(CodePermissionDenied, message)
+}
+
+func NotFound(message string) *Error {
+	return New(CodeNotFound, message)
+}
+
+func AlreadyExists(message string) *Error {
+	return New(CodeAlreadyExists, message)
+}
+
+func FailedPrecondition(message string) *Error {
+	return New(CodeFailedPrecondition, message)
+}
+
+func Expired(message string) *Error {
+	return New(CodeExpired, message)
+}
+
+// Internal error which hides the details of the given error from the clients
+func Internal(err error) *Error {
+	return Wrap(err, CodeInternal, "Something went wrong")
+}
+
+// Convert any error into an API error
+//
+// DB errors are mapped by their cause: Missing rows & references are not found and unique constraint violations already exist,
+// Rest of the errors are internal.
+func From(err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Wrap(err, CodeNotFound, "Not found")
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case foreignKeyViolation:
+			return Wrap(err, CodeNotFound, "Not found")
+		case uniqueViolation:
+			return Wrap(err, CodeAlreadyExists, "Already exists")
+		}
+	}
+
+	return Internal(err)
+}
+
+// Convert a DB error using the given messages for the not found & already exists errors
+func FromDB(err error, notFoundMessage, alreadyExistsMessage string) *Error {
+	apiErr := From(err)
+
+	switch {
+	case apiErr.Code == CodeNotFound && notFoundMessage != "":
+		return Wrap(err, CodeNotFound, notFoundMessage)
+	case apiErr.Code == CodeAlreadyExists && alreadyExistsMessage != "":
+		return Wrap(err, CodeAlreadyExists, alreadyExistsMessage)
+	}
+	return apiErr
+}
@@ -0,0 +1,384 @@
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	log.SetOutput(io.Discard)
+	m.Run()
+}
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		code       Code
+		message    string
+		httpStatus int
+		grpcCode   codes.Code
+	}{
+		{
+			name:       "no rows",
+			err:        pgx.ErrNoRows,
+			code:       CodeNotFound,
+			message:    "Not found",
+			httpStatus: http.StatusNotFound,
+			grpcCode:   codes.NotFound,
+		},
+		{
+			name:       "wrapped no rows",
+			err:        fmt.Errorf("fetching content: %w", pgx.ErrNoRows),
+			code:       CodeNotFound,
+			message:    "Not found",
+			httpStatus: http.StatusNotFound,
+			grpcCode:   codes.NotFound,
+		},
+		{
+			name:       "unique violation",
+			err:        &pgconn.PgError{Code: uniqueViolation},
+			code:       CodeAlreadyExists,
+			message:    "Already exists",
+			httpStatus: http.StatusConflict,
+			grpcCode:   codes.AlreadyExists,
+		},
+		{
+			name:       "foreign key violation",
+			err:        &pgconn.PgError{Code: foreignKeyViolation},
+			code:       CodeNotFound,
+			message:    "Not found",
+			httpStatus: http.StatusNotFound,
+			grpcCode:   codes.NotFound,
+		},
+		{
+			name:       "other DB error",
+			err:        &pgconn.PgError{Code: "42P01"},
+			code:       CodeInternal,
+			message:    "Something went wrong",
+			httpStatus: http.StatusInternalServerError,
+			grpcCode:   codes.Internal,
+		},
+		{
+			name:       "unknown error",
+			err:        errors.New("connection refused"),
+			code:       CodeInternal,
+			message:    "Something went wrong",
+			httpStatus: http.StatusInternalServerError,
+			grpcCode:   codes.Internal,
+		},
+		{
+			name:       "API error",
+			err:        PermissionDenied("Not allowed"),
+			code:       CodePermissionDenied,
+			message:    "Not allowed",
+			httpStatus: http.StatusForbidden,
+			grpcCode:   codes.PermissionDenied,
+		},
+		{
+			name:       "wrapped API error",
+			err:        fmt.Errorf("updating profile: %w", Expired("Link has expired")),
+			code:       CodeExpired,
+			message:    "Link has expired",
+			httpStatus: http.StatusGone,
+			grpcCode:   codes.FailedPrecondition,
+		},
+		{
+			name:       "failed precondition",
+			err:        FailedPrecondition("Upload is already completed"),
+			code:       CodeFailedPrecondition,
+			message:    "Upload is already completed",
+			httpStatus: http.StatusConflict,
+			grpcCode:   codes.FailedPrecondition,
+		},
+		{
+			name:       "unknown code",
+			err:        New(Code("teapot"), "Short and stout"),
+			code:       Code("teapot"),
+			message:    "Short and stout",
+			httpStatus: http.StatusInternalServerError,
+			grpcCode:   codes.Internal,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			apiErr := From(tc.err)
+
+			if apiErr.Code != tc.code || apiErr.Message != tc.message {
+				t.Errorf("expected %s %q, got %s %q", tc.code, tc.message, apiErr.Code, apiErr.Message)
+			}
+			if status := apiErr.Code.HTTPStatus(); status != tc.httpStatus {
+				t.Errorf("expected HTTP status %d, got %d", tc.httpStatus, status)
+			}
+			if code := apiErr.GRPCStatus().Code(); code != tc.grpcCode {
+				t.Errorf("expected gRPC code %s, got %s", tc.grpcCode, code)
+			}
+		})
+	}
+}
+
+func TestFromDB(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    Code
+		message string
+	}{
+		{name: "no rows", err: pgx.ErrNoRows, code: CodeNotFound, message: "Content not found"},
+		{name: "unique violation", err: &pgconn.PgError{Code: uniqueViolation}, code: CodeAlreadyExists, message: "Email already exists"},
+		{name: "API error", err: InvalidArgument("Invalid ID"), code: CodeInvalidArgument, message: "Invalid ID"},
+		{name: "unknown error", err: errors.New("timeout"), code: CodeInternal, message: "Something went wrong"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			apiErr := FromDB(tc.err, "Content not found", "Email already exists")
+			if apiErr.Code != tc.code || apiErr.Message != tc.message {
+				t.Errorf("expected %s %q, got %s %q", tc.code, tc.message, apiErr.Code, apiErr.Message)
+			}
+		})
+	}
+
+	// Default messages are used when no messages are given
+	if apiErr := FromDB(pgx.ErrNoRows, "", ""); apiErr.Message != "Not found" {
+		t.Errorf("expected the default message, got %q", apiErr.Message)
+	}
+}
+
+func TestFromBinding(t *testing.T) {
+	type Parameters struct {
+		Name  string `json:"name" binding:"required"`
+		Email string `json:"email" binding:"required,email"`
+		Age   int    `json:"age" binding:"min=13"`
+		Role  string `json:"role" binding:"omitempty,oneof=admin user"`
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		fields map[string]string
+	}{
+		{
+			name: "missing fields",
+			body: `{"age":20}`,
+			fields: map[string]string{
+				"name":  "This field is required",
+				"email": "This field is required",
+			},
+		},
+		{
+			name: "invalid values",
+			body: `{"name":"Name","email":"invalid","age":10,"role":"owner"}`,
+			fields: map[string]string{
+				"email": "Invalid email address",
+				"age":   "Should be at least 13",
+				"role":  "Should be one of: admin user",
+			},
+		},
+		{
+			name:   "invalid type",
+			body:   `{"name":"Name","email":"user@example.com","age":"twenty"}`,
+			fields: map[string]string{"age": "Should be of type int"},
+		},
+		{
+			name:   "malformed JSON",
+			body:   `{"name":`,
+			fields: map[string]string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			var params Parameters
+			err := ctx.ShouldBindJSON(&params)
+			if err == nil {
+				t.Fatal("expected a binding error")
+			}
+
+			apiErr := FromBinding(err)
+			if apiErr.Code != CodeInvalidArgument || apiErr.Code.HTTPStatus() != http.StatusBadRequest {
+				t.Errorf("expected invalid argument, got %s", apiErr.Code)
+			}
+
+			fields := make(map[string]string)
+			for _, field := range apiErr.Fields {
+				fields[field.Field] = field.Message
+			}
+			if len(fields) != len(tc.fields) {
+				t.Errorf("expected fields %v, got %v", tc.fields, fields)
+			}
+			for field, message := range tc.fields {
+				if fields[field] != message {
+					t.Errorf("field %s: expected %q, got %q", field, message, fields[field])
+				}
+			}
+		})
+	}
+}
+
+func TestRespond(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   Code
+	}{
+		{name: "not found", err: pgx.ErrNoRows, status: http.StatusNotFound, code: CodeNotFound},
+		{name: "conflict", err: &pgconn.PgError{Code: uniqueViolation}, status: http.StatusConflict, code: CodeAlreadyExists},
+		{name: "validation", err: InvalidArgument("Invalid request data").WithField("name", "This field is required"), status: http.StatusBadRequest, code: CodeInvalidArgument},
+		{name: "internal", err: errors.New("secret details"), status: http.StatusInternalServerError, code: CodeInternal},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(RequestID())
+			engine.GET("/", func(ctx *gin.Context) {
+				Respond(ctx, tc.err)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(RequestIDHeader, "request-1")
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, rec.Code)
+			}
+
+			// SecureJSON prefixes the arrays only, Error bodies are objects
+			var body struct {
+				Message   string       `json:"message"`
+				Code      Code         `json:"code"`
+				RequestID string       `json:"request_id"`
+				Details   []FieldError `json:"details"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+
+			if body.Code != tc.code || body.RequestID != "request-1" || rec.Header().Get(RequestIDHeader) != "request-1" {
+				t.Errorf("unexpected response: %s", rec.Body.String())
+			}
+			if strings.Contains(rec.Body.String(), "secret details") {
+				t.Errorf("internal error details are sent to the client: %s", rec.Body.String())
+			}
+			if apiErr, ok := tc.err.(*Error); ok && len(body.Details) != len(apiErr.Fields) {
+				t.Errorf("expected %d field details, got %d", len(apiErr.Fields), len(body.Details))
+			}
+		})
+	}
+}
+
+func TestRequestIDIsGenerated(t *testing.T) {
+	for _, requestID := range []string{"", "has spaces", strings.Repeat("a", maxRequestIDLength+1)} {
+		engine := gin.New()
+		engine.Use(RequestID())
+		engine.GET("/", func(ctx *gin.Context) {
+			Respond(ctx, NotFound("Not found"))
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, requestID)
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+
+		if generated := rec.Header().Get(RequestIDHeader); generated == "" || generated == requestID {
+			t.Errorf("%q: request ID is not generated, got %q", requestID, generated)
+		}
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/content.ContentService/UserDeleted"}
+
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		fields int
+
+		// Statuses created by the handlers are sent as they are
+		passthrough bool
+	}{
+		{name: "no rows", err: pgx.ErrNoRows, code: codes.NotFound},
+		{name: "unique violation", err: &pgconn.PgError{Code: uniqueViolation}, code: codes.AlreadyExists},
+		{name: "validation", err: InvalidArgument("Invalid user ID").WithField("user_id", "Invalid ID"), code: codes.InvalidArgument, fields: 1},
+		{name: "unknown error", err: errors.New("secret details"), code: codes.Internal},
+		{name: "status", err: status.Error(codes.Unavailable, "Try again"), code: codes.Unavailable, passthrough: true},
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestIDMetadataKey, "request-1"))
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := UnaryServerInterceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+				return nil, tc.err
+			})
+
+			st, ok := status.FromError(err)
+			if !ok {
+				t.Fatalf("expected a gRPC status, got %v", err)
+			}
+			if st.Code() != tc.code {
+				t.Errorf("expected code %s, got %s", tc.code, st.Code())
+			}
+			if strings.Contains(st.Message(), "secret details") {
+				t.Errorf("internal error details are sent to the client: %s", st.Message())
+			}
+
+			if tc.passthrough {
+				if len(st.Details()) != 0 {
+					t.Errorf("status of the handler is modified: %v", st.Details())
+				}
+				return
+			}
+
+			requestID, fields := "", 0
+			for _, detail := range st.Details() {
+				switch detail := detail.(type) {
+				case *errdetails.RequestInfo:
+					requestID = detail.RequestId
+				case *errdetails.BadRequest:
+					fields = len(detail.FieldViolations)
+				}
+			}
+			if requestID != "request-1" {
+				t.Errorf("expected request ID in the details, got %q", requestID)
+			}
+			if fields != tc.fields {
+				t.Errorf("expected %d field violations, got %d", tc.fields, fields)
+			}
+		})
+	}
+
+	resp, err := UnaryServerInterceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	})
+	if err != nil || resp != "ok" {
+		t.Errorf("expected the response of the handler, got %v, %v", resp, err)
+	}
+}
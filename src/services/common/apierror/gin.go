@@ -0,0 +1,139 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Header which carries the request ID, Set by the API gateway or generated by the service
+	RequestIDHeader = "X-Request-ID"
+
+	requestIDKey       = "request_id"
+	maxRequestIDLength = 64
+)
+
+func init() {
+	// Report the validation errors using the JSON field names instead of the struct field names
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// Check weather or not the request ID given by the client can be used, So it does not break the logs
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for _, char := range requestID {
+		if char < '!' || char > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// Middleware which assigns an ID to every request, Included in the error responses for tracing the failures in logs
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		ctx.Set(requestIDKey, requestID)
+		ctx.Header(RequestIDHeader, requestID)
+		ctx.Next()
+	}
+}
+
+// Return the ID of current request, Empty if the request ID middleware is not used
+func GetRequestID(ctx *gin.Context) string {
+	return ctx.GetString(requestIDKey)
+}
+
+// Send the error response and stop the pending handlers
+//
+// Errors which are not API errors are converted using From, So the DB errors get their relevant status codes.
+func Respond(ctx *gin.Context, err error) {
+	apiErr := From(err)
+	requestID := GetRequestID(ctx)
+
+	if apiErr.Code == CodeInternal {
+		log.Errorln("request ", requestID, " failed with internal error: ", apiErr.Err)
+	}
+
+	body := gin.H{
+		"message":    apiErr.Message,
+		"code":       apiErr.Code,
+		"request_id": requestID,
+	}
+	if len(apiErr.Fields) != 0 {
+		body["details"] = apiErr.Fields
+	}
+
+	ctx.Abort()
+	ctx.SecureJSON(apiErr.Code.HTTPStatus(), body)
+}
+
+func validationMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "This field is required"
+	case "email":
+		return "Invalid email address"
+	case "url", "http_url":
+		return "Invalid URL"
+	case "uuid", "uuid4":
+		return "Invalid ID"
+	case "oneof":
+		return "Should be one of: " + fieldErr.Param()
+	case "min", "gte":
+		return "Should be at least " + fieldErr.Param()
+	case "max", "lte":
+		return "Should be at most " + fieldErr.Param()
+	}
+	return "Invalid value"
+}
+
+// Convert the error caught while parsing the request data, Adds the details of the invalid fields if they are known
+func FromBinding(err error) *Error {
+	apiErr := Wrap(err, CodeInvalidArgument, "Invalid request data")
+
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &validationErrs):
+		for _, fieldErr := range validationErrs {
+			// Namespace includes the parameters struct name, Which is not known to the clients
+			field := fieldErr.Namespace()
+			if _, nested, found := strings.Cut(field, "."); found {
+				field = nested
+			}
+			apiErr.WithField(field, validationMessage(fieldErr))
+		}
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		apiErr.WithField(typeErr.Field, "Should be of type "+typeErr.Type.String())
+	}
+
+	return apiErr
+}
@@ -0,0 +1,55 @@
+package apierror
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// gRPC metadata key which carries the request ID
+const requestIDMetadataKey = "x-request-id"
+
+// Return the request ID sent along with the gRPC request, A new one is generated if it's missing
+func incomingRequestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDMetadataKey); len(values) != 0 && isValidRequestID(values[0]) {
+			return values[0]
+		}
+	}
+	return uuid.New().String()
+}
+
+// gRPC interceptor which converts the errors returned by the handlers into statuses
+//
+// Errors are mapped the same way as the REST APIs, And the request ID is added to the status details.
+func UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err == nil {
+		return resp, nil
+	}
+
+	// Statuses created by the handlers are sent as they are
+	if _, isAPIErr := err.(*Error); !isAPIErr {
+		if _, isStatus := status.FromError(err); isStatus {
+			return nil, err
+		}
+	}
+
+	requestID := incomingRequestID(ctx)
+	apiErr := From(err)
+
+	if apiErr.Code == CodeInternal {
+		log.Errorln("request ", requestID, " failed with internal error: ", apiErr.Err, "method: ", info.FullMethod)
+	}
+
+	st := apiErr.GRPCStatus()
+	if detailed, detailErr := st.WithDetails(&errdetails.RequestInfo{RequestId: requestID}); detailErr == nil {
+		st = detailed
+	}
+	return nil, st.Err()
+}
@@ -0,0 +1,201 @@
+// Generate the gin server interface of an OpenAPI spec
+//
+// Every operation of the spec becomes a method of the ServerInterface named after its operation ID,
+// RegisterHandlers serves the operations at their paths behind the middlewares of their security requirements.
+//
+// Usage: go run github.com/thejasmeetsingh/spotify-clone/src/services/common/apigen -spec openapi.json -package api -out openapi.gen.go
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Order of the HTTP methods in the generated code
+var methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
+type operation struct {
+	ID       string
+	Summary  string
+	Method   string
+	Path     string
+	GinPath  string
+	Security string
+}
+
+type securityHandler struct {
+	Name        string
+	Description string
+}
+
+const source = `// Code generated by apigen from {{.Spec}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/gin-gonic/gin"
+
+// Handlers of the operations in the OpenAPI spec
+type ServerInterface interface {
+{{- range .Operations}}
+	// {{.Summary}}
+	// ({{.Method}} {{.Path}})
+	{{.ID}}(ctx *gin.Context)
+{{- end}}
+}
+
+// Middlewares which authenticate the requests by the security requirements of their operations
+type SecurityHandlers struct {
+{{- range .SecurityHandlers}}
+	// {{.Description}}
+	{{.Name}} gin.HandlerFunc
+{{- end}}
+}
+
+// Serve the operations of the OpenAPI spec on the given router
+func RegisterHandlers(router gin.IRouter, si ServerInterface, security SecurityHandlers) {
+{{- range .Operations}}
+	router.{{.Method}}("{{.GinPath}}", {{if .Security}}security.{{.Security}}, {{end}}si.{{.ID}})
+{{- end}}
+}
+`
+
+func exportedName(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// Convert an OpenAPI path template into the gin route path, Like /api/v1/{id}/ into /api/v1/:id/
+func ginPath(path string) string {
+	segments := strings.Split(path, "/")
+	for idx, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[idx] = ":" + strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Return the name of the security handler for the given requirements, Empty if the operation is not authenticated
+//
+// Requirements can be a single scheme, Or an empty requirement along with a single scheme for optional authentication.
+func securityName(requirements openapi3.SecurityRequirements) (string, string, error) {
+	optional := false
+	var schemes []string
+
+	for _, requirement := range requirements {
+		if len(requirement) == 0 {
+			optional = true
+			continue
+		}
+		for scheme := range requirement {
+			schemes = append(schemes, scheme)
+		}
+	}
+
+	switch {
+	case len(schemes) == 0:
+		return "", "", nil
+	case len(schemes) > 1:
+		return "", "", fmt.Errorf("multiple security schemes are not supported: %v", schemes)
+	case optional:
+		return "Optional" + exportedName(schemes[0]), schemes[0] + " is optional", nil
+	default:
+		return exportedName(schemes[0]), schemes[0] + " is required", nil
+	}
+}
+
+func generate(specPath string, pkg string) ([]byte, error) {
+	doc, err := openapi3.NewLoader().LoadFromFile(specPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+
+	var operations []operation
+	handlers := make(map[string]securityHandler)
+
+	paths := doc.Paths.InMatchingOrder()
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		item := doc.Paths.Value(path)
+		for _, method := range methods {
+			op := item.GetOperation(method)
+			if op == nil {
+				continue
+			}
+			if op.OperationID == "" {
+				return nil, fmt.Errorf("operation %s %s has no operation ID", method, path)
+			}
+
+			requirements := doc.Security
+			if op.Security != nil {
+				requirements = *op.Security
+			}
+			security, description, err := securityName(requirements)
+			if err != nil {
+				return nil, fmt.Errorf("operation %s: %w", op.OperationID, err)
+			}
+			if security != "" {
+				handlers[security] = securityHandler{Name: security, Description: description}
+			}
+
+			operations = append(operations, operation{
+				ID:       exportedName(op.OperationID),
+				Summary:  op.Summary,
+				Method:   method,
+				Path:     path,
+				GinPath:  ginPath(path),
+				Security: security,
+			})
+		}
+	}
+
+	var securityHandlers []securityHandler
+	for _, handler := range handlers {
+		securityHandlers = append(securityHandlers, handler)
+	}
+	sort.Slice(securityHandlers, func(i, j int) bool {
+		return securityHandlers[i].Name < securityHandlers[j].Name
+	})
+
+	var buf bytes.Buffer
+	err = template.Must(template.New("source").Parse(source)).Execute(&buf, map[string]any{
+		"Spec":             filepath.Base(specPath),
+		"Package":          pkg,
+		"Operations":       operations,
+		"SecurityHandlers": securityHandlers,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+func main() {
+	specPath := flag.String("spec", "openapi.json", "OpenAPI spec file")
+	pkg := flag.String("package", "api", "package of the generated code")
+	out := flag.String("out", "openapi.gen.go", "output file")
+	flag.Parse()
+
+	code, err := generate(*specPath, *pkg)
+	if err != nil {
+		log.Fatalln("error caught while generating the server interface: ", err)
+	}
+
+	if err := os.WriteFile(*out, code, 0644); err != nil {
+		log.Fatalln("error caught while writing the generated code: ", err)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Test API", "version": "1.0.0"},
+  "paths": {
+    "/api/v1/{id}/": {
+      "get": {
+        "operationId": "getItem",
+        "summary": "Item details",
+        "security": [{}, {"bearerAuth": []}],
+        "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "responses": {"200": {"description": "Successful response"}}
+      },
+      "delete": {
+        "operationId": "deleteItem",
+        "summary": "Delete the item",
+        "security": [{"bearerAuth": []}],
+        "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "responses": {"200": {"description": "Successful response"}}
+      }
+    },
+    "/api/v1/list/": {
+      "get": {
+        "operationId": "getItems",
+        "summary": "List of items",
+        "responses": {"200": {"description": "Successful response"}}
+      }
+    }
+  },
+  "components": {
+    "securitySchemes": {
+      "bearerAuth": {"type": "http", "scheme": "bearer"}
+    }
+  }
+}`
+
+func TestGenerate(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(specPath, []byte(testSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, err := generate(specPath, "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"package api",
+		"GetItem(ctx *gin.Context)",
+		"BearerAuth gin.HandlerFunc",
+		"OptionalBearerAuth gin.HandlerFunc",
+		`router.GET("/api/v1/:id/", security.OptionalBearerAuth, si.GetItem)`,
+		`router.DELETE("/api/v1/:id/", security.BearerAuth, si.DeleteItem)`,
+		`router.GET("/api/v1/list/", si.GetItems)`,
+	} {
+		if !strings.Contains(string(code), expected) {
+			t.Errorf("generated code doesn't contain %q:\n%s", expected, code)
+		}
+	}
+}
+
+func TestGenerateWithoutOperationID(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "openapi.json")
+	spec := strings.Replace(testSpec, `"operationId": "getItems",`, "", 1)
+	if err := os.WriteFile(specPath, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := generate(specPath, "api"); err == nil {
+		t.Fatal("expected an error for the operation without ID")
+	}
+}
@@ -0,0 +1,220 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: proto/content.proto
+
+package contentpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ContentService_UserDeleted_FullMethodName     = "/content.ContentService/UserDeleted"
+	ContentService_UserDeactivated_FullMethodName = "/content.ContentService/UserDeactivated"
+	ContentService_UserRestored_FullMethodName    = "/content.ContentService/UserRestored"
+	ContentService_ExportUserData_FullMethodName  = "/content.ContentService/ExportUserData"
+)
+
+// ContentServiceClient is the client API for ContentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ContentServiceClient interface {
+	UserDeleted(ctx context.Context, in *UserDeletedRequest, opts ...grpc.CallOption) (*UserDeletedResponse, error)
+	UserDeactivated(ctx context.Context, in *UserDeactivatedRequest, opts ...grpc.CallOption) (*UserDeactivatedResponse, error)
+	UserRestored(ctx context.Context, in *UserRestoredRequest, opts ...grpc.CallOption) (*UserRestoredResponse, error)
+	ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (*ExportUserDataResponse, error)
+}
+
+type contentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewContentServiceClient(cc grpc.ClientConnInterface) ContentServiceClient {
+	return &contentServiceClient{cc}
+}
+
+func (c *contentServiceClient) UserDeleted(ctx context.Context, in *UserDeletedRequest, opts ...grpc.CallOption) (*UserDeletedResponse, error) {
+	out := new(UserDeletedResponse)
+	err := c.cc.Invoke(ctx, ContentService_UserDeleted_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentServiceClient) UserDeactivated(ctx context.Context, in *UserDeactivatedRequest, opts ...grpc.CallOption) (*UserDeactivatedResponse, error) {
+	out := new(UserDeactivatedResponse)
+	err := c.cc.Invoke(ctx, ContentService_UserDeactivated_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentServiceClient) UserRestored(ctx context.Context, in *UserRestoredRequest, opts ...grpc.CallOption) (*UserRestoredResponse, error) {
+	out := new(UserRestoredResponse)
+	err := c.cc.Invoke(ctx, ContentService_UserRestored_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentServiceClient) ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (*ExportUserDataResponse, error) {
+	out := new(ExportUserDataResponse)
+	err := c.cc.Invoke(ctx, ContentService_ExportUserData_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ContentServiceServer is the server API for ContentService service.
+// All implementations must embed UnimplementedContentServiceServer
+// for forward compatibility
+type ContentServiceServer interface {
+	UserDeleted(context.Context, *UserDeletedRequest) (*UserDeletedResponse, error)
+	UserDeactivated(context.Context, *UserDeactivatedRequest) (*UserDeactivatedResponse, error)
+	UserRestored(context.Context, *UserRestoredRequest) (*UserRestoredResponse, error)
+	ExportUserData(context.Context, *ExportUserDataRequest) (*ExportUserDataResponse, error)
+	mustEmbedUnimplementedContentServiceServer()
+}
+
+// UnimplementedContentServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedContentServiceServer struct {
+}
+
+func (UnimplementedContentServiceServer) UserDeleted(context.Context, *UserDeletedRequest) (*UserDeletedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UserDeleted not implemented")
+}
+func (UnimplementedContentServiceServer) UserDeactivated(context.Context, *UserDeactivatedRequest) (*UserDeactivatedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UserDeactivated not implemented")
+}
+func (UnimplementedContentServiceServer) UserRestored(context.Context, *UserRestoredRequest) (*UserRestoredResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UserRestored not implemented")
+}
+func (UnimplementedContentServiceServer) ExportUserData(context.Context, *ExportUserDataRequest) (*ExportUserDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportUserData not implemented")
+}
+func (UnimplementedContentServiceServer) mustEmbedUnimplementedContentServiceServer() {}
+
+// UnsafeContentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ContentServiceServer will
+// result in compilation errors.
+type UnsafeContentServiceServer interface {
+	mustEmbedUnimplementedContentServiceServer()
+}
+
+func RegisterContentServiceServer(s grpc.ServiceRegistrar, srv ContentServiceServer) {
+	s.RegisterService(&ContentService_ServiceDesc, srv)
+}
+
+func _ContentService_UserDeleted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserDeletedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentServiceServer).UserDeleted(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentService_UserDeleted_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentServiceServer).UserDeleted(ctx, req.(*UserDeletedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentService_UserDeactivated_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserDeactivatedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentServiceServer).UserDeactivated(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentService_UserDeactivated_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentServiceServer).UserDeactivated(ctx, req.(*UserDeactivatedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentService_UserRestored_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRestoredRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentServiceServer).UserRestored(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentService_UserRestored_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentServiceServer).UserRestored(ctx, req.(*UserRestoredRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentService_ExportUserData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportUserDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentServiceServer).ExportUserData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentService_ExportUserData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentServiceServer).ExportUserData(ctx, req.(*ExportUserDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ContentService_ServiceDesc is the grpc.ServiceDesc for ContentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ContentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "content.ContentService",
+	HandlerType: (*ContentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "UserDeleted",
+			Handler:    _ContentService_UserDeleted_Handler,
+		},
+		{
+			MethodName: "UserDeactivated",
+			Handler:    _ContentService_UserDeactivated_Handler,
+		},
+		{
+			MethodName: "UserRestored",
+			Handler:    _ContentService_UserRestored_Handler,
+		},
+		{
+			MethodName: "ExportUserData",
+			Handler:    _ContentService_ExportUserData_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/content.proto",
+}
@@ -0,0 +1,508 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+// Check weather or not the current user can manage the given artist
+func isArtistUser(dbCfg *database.Config, ctx *gin.Context, artistID uuid.UUID) (bool, error) {
+	user, err := getUser(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return database.IsArtistUserDB(dbCfg, ctx, database.IsArtistUserParams{
+		ArtistID: artistID,
+		UserID:   user.ID,
+	})
+}
+
+// API for creating an artist profile
+//
+// Current user is linked with the artist, So that they can publish releases on its behalf
+func createArtist(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		type Parameters struct {
+			Name string `json:"name" binding:"required,max=255"`
+			Bio  string `json:"bio"`
+		}
+		var params Parameters
+
+		err := ctx.ShouldBindJSON(&params)
+		if err != nil {
+			log.Errorln("error while parsing request data: ", err)
+			apierror.Respond(ctx, apierror.FromBinding(err))
+			return
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbArtist, err := database.CreateArtistDB(dbCfg, ctx, database.CreateArtistParams{
+			ID: uuid.New(),
+			CreatedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			Name: strings.TrimSpace(params.Name),
+			Bio:  params.Bio,
+		}, user.ID)
+
+		if err != nil {
+			log.Errorln("error caught while adding artist to DB: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusCreated, gin.H{"data": databaseArtistToArtist(dbArtist)})
+	}
+}
+
+// API for getting artist detail
+// Non-auth API: Anyone can view the artist details
+func getArtistDetail(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		artistID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid artist ID"))
+			return
+		}
+
+		dbArtist, err := database.GetArtistDetailDB(dbCfg, ctx, artistID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.NotFound("Artist not found"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while fetching artist detail: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"data": databaseArtistToArtist(dbArtist)})
+	}
+}
+
+// API for linking another user with the artist
+//
+// Only the users who are already linked with the artist can link a new user
+func addArtistUser(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		type Parameters struct {
+			UserID string `json:"user_id" binding:"required"`
+		}
+		var params Parameters
+
+		err := ctx.ShouldBindJSON(&params)
+		if err != nil {
+			log.Errorln("error while parsing request data: ", err)
+			apierror.Respond(ctx, apierror.FromBinding(err))
+			return
+		}
+
+		artistID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid artist ID"))
+			return
+		}
+
+		userID, err := uuid.Parse(params.UserID)
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid user ID"))
+			return
+		}
+
+		allowed, err := isArtistUser(dbCfg, ctx, artistID)
+		if err != nil {
+			log.Errorln("error caught while checking artist user: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if !allowed {
+			apierror.Respond(ctx, apierror.PermissionDenied("You are not allowed to manage this artist"))
+			return
+		}
+
+		if err = database.AddArtistUserDB(dbCfg, ctx, database.AddArtistUserParams{
+			ArtistID: artistID,
+			UserID:   userID,
+		}); err != nil {
+			log.Errorln("error caught while linking user with the artist: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"message": "User linked with the artist successfully"})
+	}
+}
+
+// API for getting albums released by an artist, Latest first
+// Non-auth API: Anyone can view the artist albums
+func getArtistAlbums(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		artistID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid artist ID"))
+			return
+		}
+
+		p, ok := getPage(ctx, "newest")
+		if !ok {
+			return
+		}
+
+		dbAlbums, err := database.GetArtistAlbumsDB(dbCfg, ctx, database.GetArtistAlbumsParams{
+			ArtistID:          artistID,
+			CursorID:          p.cursorID(),
+			Backward:          p.backward(),
+			CursorReleaseDate: p.dateKey(),
+			Limit:             p.limit(),
+		})
+
+		if err != nil {
+			log.Errorln("error caught while fetching artist albums: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbAlbums, cursors := paginate(p, dbAlbums, func(row database.Album) (string, uuid.UUID) {
+			return dateKey(row.ReleaseDate.Time), row.ID
+		})
+
+		ctx.SecureJSON(http.StatusOK, pageResponse(databaseAlbumsToAlbums(dbAlbums), cursors))
+	}
+}
+
+// API for adding an album, Single or EP of an artist
+func createAlbum(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		type Parameters struct {
+			ArtistID    string `json:"artist_id" binding:"required"`
+			Title       string `json:"title" binding:"required,max=255"`
+			Type        string `json:"type" binding:"required"`
+			ReleaseDate string `json:"release_date" binding:"required"`
+			UPC         string `json:"upc"`
+		}
+		var params Parameters
+
+		err := ctx.ShouldBindJSON(&params)
+		if err != nil {
+			log.Errorln("error while parsing request data: ", err)
+			apierror.Respond(ctx, apierror.FromBinding(err))
+			return
+		}
+
+		artistID, err := uuid.Parse(params.ArtistID)
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid artist ID"))
+			return
+		}
+
+		if !isValidAlbumType(params.Type) {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid album type"))
+			return
+		}
+
+		releaseDate, err := time.Parse(time.DateOnly, params.ReleaseDate)
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Release date should be in YYYY-MM-DD format"))
+			return
+		}
+
+		if params.UPC != "" && !isValidUPC(params.UPC) {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid UPC code"))
+			return
+		}
+
+		allowed, err := isArtistUser(dbCfg, ctx, artistID)
+		if err != nil {
+			log.Errorln("error caught while checking artist user: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if !allowed {
+			apierror.Respond(ctx, apierror.PermissionDenied("You are not allowed to manage this artist"))
+			return
+		}
+
+		dbAlbum, err := database.CreateAlbumDB(dbCfg, ctx, database.CreateAlbumParams{
+			ID: uuid.New(),
+			CreatedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			ArtistID: artistID,
+			Title:    strings.TrimSpace(params.Title),
+			Type:     database.AlbumType(params.Type),
+			ReleaseDate: pgtype.Date{
+				Time:  releaseDate,
+				Valid: true,
+			},
+			Upc: pgtype.Text{
+				String: params.UPC,
+				Valid:  params.UPC != "",
+			},
+		})
+
+		if err != nil {
+			log.Errorln("error caught while adding album to DB: ", err)
+			apierror.Respond(ctx, apierror.FromDB(err, "", "Album with this UPC already exists"))
+			return
+		}
+
+		ctx.SecureJSON(http.StatusCreated, gin.H{"data": databaseAlbumToAlbum(dbAlbum)})
+	}
+}
+
+// API for getting album detail along with its artist
+// Non-auth API: Anyone can view the album details
+func getAlbumDetail(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		albumID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid album ID"))
+			return
+		}
+
+		dbAlbum, err := database.GetAlbumDetailDB(dbCfg, ctx, albumID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.NotFound("Album not found"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while fetching album detail: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbArtist, err := database.GetArtistDetailDB(dbCfg, ctx, dbAlbum.ArtistID)
+		if err != nil {
+			log.Errorln("error caught while fetching album artist: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		album := databaseAlbumToAlbum(dbAlbum)
+		artist := databaseArtistToArtist(dbArtist)
+		album.Artist = &artist
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"data": album})
+	}
+}
+
+// API for getting the tracklist of an album, Ordered by disc and track number
+// Non-auth API: Anyone can view the album tracks
+func getAlbumTracks(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		albumID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid album ID"))
+			return
+		}
+
+		dbTracks, err := database.GetAlbumTracksDB(dbCfg, ctx, albumID)
+		if err != nil {
+			log.Errorln("error caught while fetching album tracks: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		contentIDs := make([]uuid.UUID, 0, len(dbTracks))
+		for _, dbTrack := range dbTracks {
+			contentIDs = append(contentIDs, dbTrack.ID)
+		}
+
+		dbArtists, err := database.GetContentArtistsDB(dbCfg, ctx, contentIDs)
+		if err != nil {
+			log.Errorln("error caught while fetching track artists: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		tracks := databaseTracksToTracks(dbTracks, databaseContentArtistsToCreditedArtists(dbArtists))
+		ctx.SecureJSON(http.StatusOK, gin.H{"results": tracks})
+	}
+}
+
+// API for adding a content into the album tracklist
+//
+// Content should be owned by the current user and not be in trash, And the user should be linked with the album artist.
+// Album artist is credited as the primary artist, Along with the given featured artists which the user is linked with as well.
+func addAlbumTrack(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		type Parameters struct {
+			ContentID         string   `json:"content_id" binding:"required"`
+			DiscNumber        int32    `json:"disc_number" binding:"min=0"`
+			TrackNumber       int32    `json:"track_number" binding:"required,min=1"`
+			ISRC              string   `json:"isrc"`
+			FeaturedArtistIDs []string `json:"featured_artist_ids" binding:"max=10"`
+		}
+		var params Parameters
+
+		err := ctx.ShouldBindJSON(&params)
+		if err != nil {
+			log.Errorln("error while parsing request data: ", err)
+			apierror.Respond(ctx, apierror.FromBinding(err))
+			return
+		}
+
+		albumID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid album ID"))
+			return
+		}
+
+		contentID, err := uuid.Parse(params.ContentID)
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID"))
+			return
+		}
+
+		// Tracks are on the first disc by default
+		if params.DiscNumber == 0 {
+			params.DiscNumber = 1
+		}
+
+		params.ISRC = strings.ToUpper(strings.ReplaceAll(params.ISRC, "-", ""))
+		if params.ISRC != "" && !isValidISRC(params.ISRC) {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid ISRC code"))
+			return
+		}
+
+		var featuredArtistIDs []uuid.UUID
+		for _, value := range params.FeaturedArtistIDs {
+			artistID, err := uuid.Parse(value)
+			if err != nil {
+				apierror.Respond(ctx, apierror.InvalidArgument("Invalid featured artist ID"))
+				return
+			}
+			if !slices.Contains(featuredArtistIDs, artistID) {
+				featuredArtistIDs = append(featuredArtistIDs, artistID)
+			}
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbAlbum, err := database.GetAlbumDetailDB(dbCfg, ctx, albumID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.NotFound("Album not found"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while fetching album detail: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		allowed, err := isArtistUser(dbCfg, ctx, dbAlbum.ArtistID)
+		if err != nil {
+			log.Errorln("error caught while checking artist user: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if !allowed {
+			apierror.Respond(ctx, apierror.PermissionDenied("You are not allowed to manage this album"))
+			return
+		}
+
+		// Featured artists are credited only by their own users, So that nobody can attach an artist to unrelated tracks
+		for _, artistID := range featuredArtistIDs {
+			if artistID == dbAlbum.ArtistID {
+				continue
+			}
+
+			_, err = database.GetArtistDetailDB(dbCfg, ctx, artistID)
+			if errors.Is(err, pgx.ErrNoRows) {
+				apierror.Respond(ctx, apierror.NotFound("Featured artist not found").WithField("featured_artist_ids", "Artist not found"))
+				return
+			} else if err != nil {
+				log.Errorln("error caught while fetching featured artist detail: ", err)
+				apierror.Respond(ctx, err)
+				return
+			}
+
+			allowed, err = isArtistUser(dbCfg, ctx, artistID)
+			if err != nil {
+				log.Errorln("error caught while checking artist user: ", err)
+				apierror.Respond(ctx, err)
+				return
+			}
+
+			if !allowed {
+				apierror.Respond(ctx, apierror.PermissionDenied("You are not allowed to credit this artist").WithField("featured_artist_ids", "Not allowed"))
+				return
+			}
+		}
+
+		// Contents in trash can't be added to an album
+		if _, ok := getOwnedContent(dbCfg, ctx, contentID, user.ID); !ok {
+			return
+		}
+
+		dbContent, err := database.AddAlbumTrackDB(dbCfg, ctx, database.AddAlbumTrackParams{
+			AlbumID: pgtype.UUID{
+				Bytes: albumID,
+				Valid: true,
+			},
+			DiscNumber: pgtype.Int4{
+				Int32: params.DiscNumber,
+				Valid: true,
+			},
+			TrackNumber: pgtype.Int4{
+				Int32: params.TrackNumber,
+				Valid: true,
+			},
+			Isrc: pgtype.Text{
+				String: params.ISRC,
+				Valid:  params.ISRC != "",
+			},
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			ID:     contentID,
+			UserID: user.ID,
+		}, dbAlbum.ArtistID, featuredArtistIDs)
+
+		// Content is not updated if it does not belong to the current user or is moved to trash in between
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.NotFound("Content not found"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while adding album track: ", err)
+			apierror.Respond(ctx, apierror.FromDB(err, "", "Track number or ISRC is already used"))
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"data": databaseContentToContent(dbContent)})
+	}
+}
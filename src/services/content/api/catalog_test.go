@@ -0,0 +1,156 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+func TestAddAlbumTrackFeaturedArtists(t *testing.T) {
+	userID := uuid.New()
+	album := database.Album{ID: uuid.New(), ArtistID: uuid.New(), Title: "Album", Type: database.AlbumTypeA}
+	ownArtist := database.Artist{ID: uuid.New(), Name: "Own"}
+	otherArtist := database.Artist{ID: uuid.New(), Name: "Other"}
+
+	artists := map[uuid.UUID]database.Artist{
+		ownArtist.ID:   ownArtist,
+		otherArtist.ID: otherArtist,
+	}
+	linkedArtists := map[uuid.UUID]bool{
+		album.ArtistID: true,
+		ownArtist.ID:   true,
+	}
+
+	db := newFakeDB()
+	db.on("GetAlbumById", func(args []any) ([][]any, error) {
+		if args[0] != album.ID {
+			return nil, nil
+		}
+		return [][]any{rowOf(album)}, nil
+	})
+	db.on("GetArtistById", func(args []any) ([][]any, error) {
+		artist, exists := artists[args[0].(uuid.UUID)]
+		if !exists {
+			return nil, nil
+		}
+		return [][]any{rowOf(artist)}, nil
+	})
+	db.on("IsArtistUser", func(args []any) ([][]any, error) {
+		return [][]any{{args[1] == userID && linkedArtists[args[0].(uuid.UUID)]}}, nil
+	})
+
+	dbCfg := &database.Config{Queries: database.New(db)}
+	engine := gin.New()
+	engine.PUT("/albums/:id/tracks/", JWTAuth(dbCfg), addAlbumTrack(dbCfg))
+
+	tests := []struct {
+		name     string
+		featured string
+		status   int
+	}{
+		{name: "invalid artist ID", featured: "invalid", status: http.StatusBadRequest},
+		{name: "missing artist", featured: uuid.NewString(), status: http.StatusNotFound},
+		{name: "artist of another user", featured: otherArtist.ID.String(), status: http.StatusForbidden},
+		{name: "own artist along with another one", featured: ownArtist.ID.String() + `","` + otherArtist.ID.String(), status: http.StatusForbidden},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			body := `{"content_id":"` + uuid.NewString() + `","track_number":1,"featured_artist_ids":["` + tc.featured + `"]}`
+			req := httptest.NewRequest(http.MethodPut, "/albums/"+album.ID.String()+"/tracks/", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := serve(t, engine, req, &userID)
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAddAlbumTrackTrashedContent(t *testing.T) {
+	userID := uuid.New()
+	album := database.Album{ID: uuid.New(), ArtistID: uuid.New(), Title: "Album", Type: database.AlbumTypeA}
+
+	createdAt := pgtype.Timestamp{Time: time.Now().UTC().Add(-time.Hour), Valid: true}
+	contents := map[string]database.Content{}
+	for _, name := range []string{"active", "trashed"} {
+		contents[name] = database.Content{
+			ID:          uuid.New(),
+			CreatedAt:   createdAt,
+			ModifiedAt:  createdAt,
+			UserID:      userID,
+			Title:       name,
+			Description: "Description",
+			Type:        database.ContentTypeM,
+			Visibility:  database.ContentVisibilityPublic,
+			ShareToken:  uuid.New(),
+			Released:    true,
+		}
+	}
+	trashed := contents["trashed"]
+	trashed.DeletedAt = pgtype.Timestamp{Time: time.Now().UTC(), Valid: true}
+	contents["trashed"] = trashed
+
+	db := newFakeDB()
+	db.on("GetAlbumById", func(args []any) ([][]any, error) {
+		return [][]any{rowOf(album)}, nil
+	})
+	db.on("IsArtistUser", func(args []any) ([][]any, error) {
+		return [][]any{{args[1] == userID}}, nil
+	})
+	// Mirror of the query which skips the contents in trash
+	db.on("GetContentById", func(args []any) ([][]any, error) {
+		for _, content := range contents {
+			if content.ID == args[0] && !content.DeletedAt.Valid {
+				return [][]any{rowOf(content)}, nil
+			}
+		}
+		return nil, nil
+	})
+
+	pool, attempts := newTestPool(t)
+	dbCfg := &database.Config{DB: pool, Queries: database.New(db)}
+	engine := gin.New()
+	engine.PUT("/albums/:id/tracks/", JWTAuth(dbCfg), addAlbumTrack(dbCfg))
+
+	tests := []struct {
+		name    string
+		content database.Content
+		status  int
+	}{
+		// Track is added in a transaction, Which can't be started in the tests
+		{name: "active", content: contents["active"], status: http.StatusInternalServerError},
+		{name: "trashed", content: contents["trashed"], status: http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			before := attempts.Load()
+
+			body := `{"content_id":"` + tc.content.ID.String() + `","track_number":1}`
+			req := httptest.NewRequest(http.MethodPut, "/albums/"+album.ID.String()+"/tracks/", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := serve(t, engine, req, &userID)
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+
+			if added := attempts.Load() != before; added != (tc.status != http.StatusNotFound) {
+				t.Errorf("track added: %v", added)
+			}
+		})
+	}
+
+	if sql := db.sql("GetContentById"); !strings.Contains(sql, "deleted_at IS NULL") {
+		t.Errorf("contents in trash are not skipped: %s", sql)
+	}
+}
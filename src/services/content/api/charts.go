@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
+)
+
+// API for getting the most played contents of the last week
+// Non-auth API: Anyone can view the charts
+func getTrendingChart(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		entries, err := internal.GetTrendingChart(dbCfg, ctx)
+		if err != nil {
+			log.Errorln("error caught while fetching trending chart: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"results": entries})
+	}
+}
+
+// API for getting the most played contents of all time, Filtered by the content type (M or P)
+// Non-auth API: Anyone can view the charts
+func getTopChart(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		contentType := database.ContentType(ctx.DefaultQuery("type", string(database.ContentTypeM)))
+		if contentType != database.ContentTypeM && contentType != database.ContentTypeP {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content type"))
+			return
+		}
+
+		entries, err := internal.GetTopChart(dbCfg, ctx, contentType)
+		if err != nil {
+			log.Errorln("error caught while fetching top chart: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"results": entries})
+	}
+}
@@ -2,10 +2,10 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
-	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -14,160 +14,336 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
 )
 
-// Parse the offset from query params
-func getOffset(ctx *gin.Context) int32 {
-	offsetStr := ctx.Query("offset")
-	if offsetStr == "" {
-		offsetStr = "0"
-	}
+// Sort options of the content lists
+var contentListSorts = []string{"newest", "title", "popularity"}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil {
-		offset = 0
+// Cursor key of a content list row for the given sort
+func contentListKey(sort string, createdAt time.Time, title string, plays int64) string {
+	switch sort {
+	case "title":
+		return title
+	case "popularity":
+		return intKey(plays)
 	}
-	return int32(offset)
+	return timeKey(createdAt)
 }
 
 // Get user object from the context
 func getUser(ctx *gin.Context) (internal.User, error) {
 	value, exists := ctx.Get("user")
 	if !exists {
-		return internal.User{}, fmt.Errorf("authentication required")
+		return internal.User{}, apierror.Unauthenticated("Authentication required")
 	}
 
 	user, ok := value.(internal.User)
 	if !ok {
-		return internal.User{}, fmt.Errorf("invalid user")
+		return internal.User{}, apierror.Internal(fmt.Errorf("invalid user in request context"))
 	}
 
 	return user, nil
 }
 
+// Return the content which should be modified by the current user, Sends the error response if it can't be modified
+//
+// Missing contents, The ones in trash and the ones which the user can't view are not found,
+// Visible contents of other users are forbidden. So that the private contents of others are not revealed.
+func getOwnedContent(dbCfg *database.Config, ctx *gin.Context, contentID uuid.UUID, userID uuid.UUID) (*database.Content, bool) {
+	dbContent, ok := getViewableContent(dbCfg, ctx, contentID)
+	if !ok {
+		return nil, false
+	}
+
+	if dbContent.UserID != userID {
+		apierror.Respond(ctx, apierror.PermissionDenied("You are not allowed to modify this content"))
+		return nil, false
+	}
+	return dbContent, true
+}
+
 // API for getting list of content present on the system
 // Non-auth API: Anyone can view contents
+//
+// Contents can be filtered by multiple genres (genre) and tags (tag) query params,
+// Contents matching any of the given genres and any of the given tags are returned.
+// Genre & tag counts of the matching contents are returned as facets, For rendering the browse filters.
+// Contents can be sorted by newest (Default), title or popularity using the sort query param.
 func getContentList(dbCfg *database.Config) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		offset := getOffset(ctx)
+		p, ok := getPage(ctx, contentListSorts...)
+		if !ok {
+			return
+		}
+
+		// Empty arrays are used instead of nil, Since nil is sent as NULL to the DB
+		genres := append([]string{}, ctx.QueryArray("genre")...)
+		tags, ok := normalizeTags(append([]string{}, ctx.QueryArray("tag")...))
+		if !ok {
+			apierror.Respond(ctx, apierror.InvalidArgument("Too many tags"))
+			return
+		}
 
 		// Fetch content list from DB
 		dbContentList, err := database.GetContentListDB(dbCfg, ctx, database.GetContentListParams{
-			Limit:  10,
-			Offset: offset,
+			Genres:          genres,
+			Tags:            tags,
+			CursorID:        p.cursorID(),
+			Sort:            p.Sort,
+			Backward:        p.backward(),
+			CursorTitle:     p.textKey(),
+			CursorPlays:     p.intKey(),
+			CursorCreatedAt: p.timeKey(),
+			Limit:           p.limit(),
 		})
 
 		if err != nil {
 			log.Errorln("error caught while fetching content list: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, err)
 			return
 		}
 
+		dbGenreFacets, dbTagFacets, err := database.GetContentFacetsDB(dbCfg, ctx, database.GetTagFacetsParams{
+			Genres: genres,
+			Tags:   tags,
+			Limit:  maxTagFacets,
+		})
+
+		if err != nil {
+			log.Errorln("error caught while fetching content facets: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbContentList, cursors := paginate(p, dbContentList, func(row database.GetContentListRow) (string, uuid.UUID) {
+			return contentListKey(p.Sort, row.CreatedAt.Time, row.Title, row.Plays), row.ID
+		})
+
+		response := pageResponse([]string{}, cursors)
+		response["facets"] = gin.H{
+			"genres": databaseGenreFacetsToFacets(dbGenreFacets),
+			"tags":   databaseTagFacetsToFacets(dbTagFacets),
+		}
+
 		// Return an empty array if db contents list is empty
 		if len(dbContentList) == 0 {
-			ctx.JSON(http.StatusOK, gin.H{"results": []string{}})
+			ctx.JSON(http.StatusOK, response)
 			return
 		}
 
 		// Parse DB content list with appropriate key names
 		contentList, err := databaseContentListToContentList(dbContentList)
 		if err != nil {
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, err)
 			return
 		}
 
-		ctx.SecureJSON(http.StatusOK, gin.H{"results": contentList})
+		response["results"] = addCreatorsToContentList(ctx, contentList)
+		ctx.SecureJSON(http.StatusOK, response)
 	}
 }
 
-// API for getting contents added by current user
+// API for getting contents added by current user, Sorted the same way as the content list
 func getUserContentList(dbCfg *database.Config) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		user, err := getUser(ctx)
 		if err != nil {
 			log.Errorln(err)
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, err)
 			return
 		}
 
-		offset := getOffset(ctx)
+		p, ok := getPage(ctx, contentListSorts...)
+		if !ok {
+			return
+		}
 
 		// Fetch user contents from DB
 		dbContentUserList, err := database.GetUserContentDB(dbCfg, ctx, database.GetUserContentParams{
-			UserID: user.ID,
-			Limit:  10,
-			Offset: offset,
+			UserID:          user.ID,
+			CursorID:        p.cursorID(),
+			Sort:            p.Sort,
+			Backward:        p.backward(),
+			CursorTitle:     p.textKey(),
+			CursorPlays:     p.intKey(),
+			CursorCreatedAt: p.timeKey(),
+			Limit:           p.limit(),
 		})
 
 		if err != nil {
 			log.Errorln("error caught while fetching user content list: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, err)
 			return
 		}
 
+		dbContentUserList, cursors := paginate(p, dbContentUserList, func(row database.GetUserContentRow) (string, uuid.UUID) {
+			return contentListKey(p.Sort, row.CreatedAt.Time, row.Title, row.Plays), row.ID
+		})
+
 		// Return an empty array if user contents list is empty
 		if len(dbContentUserList) == 0 {
-			ctx.JSON(http.StatusOK, gin.H{"results": []string{}})
+			ctx.JSON(http.StatusOK, pageResponse([]string{}, cursors))
 			return
 		}
 
 		// Parse DB content list with appropriate key names
 		userContentList, err := databaseUserContentListToContentList(dbContentUserList)
 		if err != nil {
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, err)
 			return
 		}
 
-		ctx.SecureJSON(http.StatusOK, gin.H{"results": userContentList})
+		ctx.SecureJSON(http.StatusOK, pageResponse(addCreatorsToContentList(ctx, userContentList), cursors))
 	}
 }
 
 // API for getting content detail
-// Non-auth API: Anyone can view the content details
+// Non-auth API: Anyone can view the content details, Resume position is included for the authenticated user
 func getContentDetail(dbCfg *database.Config) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		contentID, err := uuid.Parse(ctx.Param("id"))
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid content ID format"})
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID format"))
 			return
 		}
 
 		dbContent, err := database.GetContentDetailDB(dbCfg, ctx, contentID)
-		if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.NotFound("Content not found"))
+			return
+		} else if err != nil {
 			log.Errorln("error caught while fetching content detail: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, err)
 			return
 		}
 
-		ctx.SecureJSON(http.StatusOK, gin.H{"data": databaseContentToContent(dbContent)})
+		sendContentDetail(dbCfg, ctx, dbContent)
 	}
 }
 
+// Send the content details along with the artists, Labels, Likes & creator
+//
+// Private content is not found for anyone except its owner, Share link is included only for the owner
+func sendContentDetail(dbCfg *database.Config, ctx *gin.Context, dbContent *database.Content) {
+	if !canViewContent(ctx, dbContent) {
+		apierror.Respond(ctx, apierror.NotFound("Content not found"))
+		return
+	}
+
+	content := databaseContentToContent(dbContent)
+
+	// Attach the credited artists
+	dbArtists, err := database.GetContentArtistsDB(dbCfg, ctx, []uuid.UUID{dbContent.ID})
+	if err != nil {
+		log.Errorln("error caught while fetching content artists: ", err)
+		apierror.Respond(ctx, err)
+		return
+	}
+
+	if artists, exists := databaseContentArtistsToCreditedArtists(dbArtists)[dbContent.ID]; exists {
+		content.Artists = artists
+	}
+
+	genres, tags, err := database.GetContentLabelsDB(dbCfg, ctx, dbContent.ID)
+	if err != nil {
+		log.Errorln("error caught while fetching content genres & tags: ", err)
+		apierror.Respond(ctx, err)
+		return
+	}
+
+	// Contents without labels have empty lists instead of null
+	content.Genres = append(content.Genres, genres...)
+	content.Tags = append(content.Tags, tags...)
+
+	content.LikeCount, err = database.GetContentLikeCountDB(dbCfg, ctx, dbContent.ID)
+	if err != nil {
+		log.Errorln("error caught while fetching content like count: ", err)
+		apierror.Respond(ctx, err)
+		return
+	}
+
+	// Attach the resume position if the user is authenticated
+	if user, err := getUser(ctx); err == nil {
+		if user.ID == dbContent.UserID {
+			shareURL := getShareURL(dbContent.ShareToken)
+			content.ShareURL = &shareURL
+		}
+
+		dbPosition, err := database.GetPlaybackPositionDB(dbCfg, ctx, database.GetPlaybackPositionParams{
+			UserID:    user.ID,
+			ContentID: dbContent.ID,
+		})
+		if err == nil {
+			content.ResumePosition = &dbPosition.Position
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			log.Errorln("error caught while fetching playback position: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+	}
+
+	// Attach the creator details, Content is returned without them if user service is not reachable
+	if creators, err := internal.GetCreators(ctx, []uuid.UUID{dbContent.UserID}); err != nil {
+		log.Errorln("error caught while fetching content creator: ", err)
+	} else if creator, exists := creators[dbContent.UserID]; exists {
+		content.Creator = &creator
+	}
+
+	ctx.SecureJSON(http.StatusOK, gin.H{"data": content})
+}
+
 // API for adding a content into the DB
 func addContent(dbCfg *database.Config) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		type Parameters struct {
-			Title       string `json:"title" binding:"required"`
-			Description string `json:"description" binding:"required"`
-			Type        string `json:"type" binding:"required"`
+			Title       string     `json:"title" binding:"required"`
+			Description string     `json:"description" binding:"required"`
+			Type        string     `json:"type" binding:"required"`
+			Visibility  string     `json:"visibility"`
+			PublishAt   *time.Time `json:"publish_at"`
+			UnpublishAt *time.Time `json:"unpublish_at"`
+			Genres      []string   `json:"genres"`
+			Tags        []string   `json:"tags"`
 		}
 		var params Parameters
 
 		err := ctx.ShouldBindJSON(&params)
 		if err != nil {
 			log.Errorln("error while parsing request data: ", err)
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Invalid request data"})
+			apierror.Respond(ctx, apierror.FromBinding(err))
+			return
+		}
+
+		// Content is public by default
+		if params.Visibility == "" {
+			params.Visibility = string(database.ContentVisibilityPublic)
+		}
+
+		if !isValidVisibility(params.Visibility) {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid visibility"))
+			return
+		}
+
+		publishAt, unpublishAt, ok := parseReleaseSchedule(ctx, params.PublishAt, params.UnpublishAt)
+		if !ok {
+			return
+		}
+
+		genres, tags, ok := parseContentLabels(dbCfg, ctx, params.Genres, params.Tags)
+		if !ok {
 			return
 		}
 
 		user, err := getUser(ctx)
 		if err != nil {
 			log.Errorln(err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, err)
 			return
 		}
 
@@ -185,24 +361,37 @@ func addContent(dbCfg *database.Config) gin.HandlerFunc {
 			Title:       params.Title,
 			Description: params.Description,
 			Type:        database.ContentType(params.Type),
-		})
+			Visibility:  database.ContentVisibility(params.Visibility),
+			PublishAt:   publishAt,
+			UnpublishAt: unpublishAt,
+			Released:    internal.IsReleaseDue(internal.SystemClock, publishAt, unpublishAt),
+		}, genres, tags)
 
 		if err != nil {
 			log.Errorln("error caught while adding content details to DB: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, apierror.FromDB(err, "", "Content with the same title already exists"))
 			return
 		}
 
-		ctx.SecureJSON(http.StatusCreated, gin.H{"data": databaseContentToContent(dbContent)})
+		content := databaseContentToContent(dbContent)
+		shareURL := getShareURL(dbContent.ShareToken)
+		content.ShareURL = &shareURL
+
+		ctx.SecureJSON(http.StatusCreated, gin.H{"data": content})
 	}
 }
 
 func updateContent(dbCfg *database.Config) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		type Parameters struct {
-			Title       string `json:"title"`
-			Description string `json:"description"`
-			Type        string `json:"type"`
+			Title       string     `json:"title"`
+			Description string     `json:"description"`
+			Type        string     `json:"type"`
+			Visibility  string     `json:"visibility"`
+			PublishAt   *time.Time `json:"publish_at"`
+			UnpublishAt *time.Time `json:"unpublish_at"`
+			Genres      []string   `json:"genres"`
+			Tags        []string   `json:"tags"`
 		}
 		var params Parameters
 
@@ -211,29 +400,38 @@ func updateContent(dbCfg *database.Config) gin.HandlerFunc {
 
 		if err != nil {
 			log.Errorln("error while parsing request data: ", err)
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Invalid request data"})
+			apierror.Respond(ctx, apierror.FromBinding(err))
+			return
+		}
+
+		if params.Visibility != "" && !isValidVisibility(params.Visibility) {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid visibility"))
+			return
+		}
+
+		// Genres & tags are replaced only if they are given
+		genres, tags, ok := parseContentLabels(dbCfg, ctx, params.Genres, params.Tags)
+		if !ok {
 			return
 		}
 
 		// Parse content ID passed in request path
 		contentID, err := uuid.Parse(ctx.Param("id"))
 		if err != nil {
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Invalid content ID"})
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID"))
 			return
 		}
 
 		user, err := getUser(ctx)
 		if err != nil {
 			log.Errorln(err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, err)
 			return
 		}
 
 		// Fetch content record from DB
-		dbContent, err := database.GetContentDetailDB(dbCfg, ctx, contentID)
-		if err != nil {
-			log.Errorln("error caught while fetching content detail: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+		dbContent, ok := getOwnedContent(dbCfg, ctx, contentID, user.ID)
+		if !ok {
 			return
 		}
 
@@ -251,6 +449,23 @@ func updateContent(dbCfg *database.Config) gin.HandlerFunc {
 			params.Type = string(dbContent.Type)
 		}
 
+		if params.Visibility == "" {
+			params.Visibility = string(dbContent.Visibility)
+		}
+
+		if params.PublishAt == nil {
+			params.PublishAt = timestampOrNil(dbContent.PublishAt)
+		}
+
+		if params.UnpublishAt == nil {
+			params.UnpublishAt = timestampOrNil(dbContent.UnpublishAt)
+		}
+
+		publishAt, unpublishAt, ok := parseReleaseSchedule(ctx, params.PublishAt, params.UnpublishAt)
+		if !ok {
+			return
+		}
+
 		// Update content detail in DB
 		dbContent, err = database.UpdateContentDetailDB(dbCfg, ctx, database.UpdateContentDetailsParams{
 			ID:          contentID,
@@ -258,18 +473,27 @@ func updateContent(dbCfg *database.Config) gin.HandlerFunc {
 			Title:       params.Title,
 			Description: params.Description,
 			Type:        database.ContentType(params.Type),
+			Visibility:  database.ContentVisibility(params.Visibility),
+			PublishAt:   publishAt,
+			UnpublishAt: unpublishAt,
+			// Content gets released only by the scheduler, So that the published event is sent once
+			Released: dbContent.Released && internal.IsReleaseDue(internal.SystemClock, publishAt, unpublishAt),
 			ModifiedAt: pgtype.Timestamp{
 				Time:  time.Now().UTC(),
 				Valid: true,
 			},
-		})
+		}, genres, tags)
 		if err != nil {
 			log.Errorln("error caught while updating content detail: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, apierror.FromDB(err, "Content not found", "Content with the same title already exists"))
 			return
 		}
 
-		ctx.SecureJSON(http.StatusOK, gin.H{"data": databaseContentToContent(dbContent)})
+		content := databaseContentToContent(dbContent)
+		shareURL := getShareURL(dbContent.ShareToken)
+		content.ShareURL = &shareURL
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"data": content})
 	}
 }
 
@@ -285,36 +509,54 @@ func updateContentS3Key(dbCfg *database.Config) gin.HandlerFunc {
 		err := ctx.ShouldBindJSON(&params)
 		if err != nil {
 			log.Errorln("error while parsing request data: ", err)
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Invalid request data"})
+			apierror.Respond(ctx, apierror.FromBinding(err))
 			return
 		}
 
 		// Parse content ID passed in request path
 		contentID, err := uuid.Parse(ctx.Param("id"))
 		if err != nil {
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Invalid content ID"})
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID"))
 			return
 		}
 
 		user, err := getUser(ctx)
 		if err != nil {
 			log.Errorln(err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, err)
 			return
 		}
 
-		// gRPC to conversion service for processing the media file and update the s3 key
-		go internal.UpdateContentS3Key(dbCfg, ctx, database.UpdateS3KeyParams{
-			ID:     contentID,
-			UserID: user.ID,
-			ModifiedAt: pgtype.Timestamp{
-				Time:  time.Now().UTC(),
-				Valid: true,
-			},
-			S3Key: pgtype.Text{},
+		// Uploaded file should belong to the same content
+		if !isContentMediaKey(contentID, params.Key) {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid key"))
+			return
+		}
+
+		if _, ok := getOwnedContent(dbCfg, ctx, contentID, user.ID); !ok {
+			return
+		}
+
+		// Add a new media version and send it to conversion service,
+		// Content keeps serving the current one until it's converted.
+		// If the storage notification already reported the file, Its existing version is returned.
+		version, _, err := internal.AddUploadedMedia(dbCfg, ctx, contentID, pgtype.UUID{
+			Bytes: user.ID,
+			Valid: true,
 		}, params.Key, params.IsAudioFile)
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.NotFound("Content not found"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while adding media version: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
 
-		ctx.SecureJSON(http.StatusOK, gin.H{"message": "Processing media file. Key will be updated soon"})
+		ctx.SecureJSON(http.StatusOK, gin.H{
+			"message": "Processing media file. Key will be updated soon",
+			"data":    databaseMediaVersionToMediaVersion(version, pgtype.UUID{}),
+		})
 	}
 }
 
@@ -323,24 +565,32 @@ func deleteContent(dbCfg *database.Config) gin.HandlerFunc {
 		// Parse content ID passed in request path
 		contentID, err := uuid.Parse(ctx.Param("id"))
 		if err != nil {
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Invalid content ID"})
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID"))
 			return
 		}
 
 		user, err := getUser(ctx)
 		if err != nil {
 			log.Errorln(err)
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if _, ok := getOwnedContent(dbCfg, ctx, contentID, user.ID); !ok {
 			return
 		}
 
-		// Delete content from DB
+		// Move content to trash
 		if err = database.DeleteContentDB(dbCfg, ctx, database.DeleteContentParams{
+			DeletedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
 			ID:     contentID,
 			UserID: user.ID,
 		}); err != nil {
 			log.Errorln("error caught while deleting content from DB: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, err)
 			return
 		}
 
@@ -349,52 +599,94 @@ func deleteContent(dbCfg *database.Config) gin.HandlerFunc {
 }
 
 // API for getting pre-signed URL for file upload
-func getPresignedURL(ctx *gin.Context) {
-	type Parameters struct {
-		ContentID   string `json:"content_id" binding:"required"`
-		FileName    string `json:"filename" binding:"required"`
-		IsAudioFile bool   `json:"is_audio_file"`
-	}
-	var params Parameters
-	err := ctx.ShouldBindJSON(&params)
+//
+// Media files can be uploaded only by the owner of the content,
+// Issued key is recorded so that the storage notification of the upload is ingested for the owner.
+func getPresignedURL(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		type Parameters struct {
+			ContentID   string `json:"content_id" binding:"required"`
+			FileName    string `json:"filename" binding:"required"`
+			IsAudioFile bool   `json:"is_audio_file"`
+		}
+		var params Parameters
+		err := ctx.ShouldBindJSON(&params)
 
-	if err != nil {
-		log.Errorln("error while parsing request data: ", err)
-		ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Invalid request data"})
-		return
-	}
+		if err != nil {
+			log.Errorln("error while parsing request data: ", err)
+			apierror.Respond(ctx, apierror.FromBinding(err))
+			return
+		}
 
-	// Load default AWS config
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+		contentID, err := uuid.Parse(params.ContentID)
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID"))
+			return
+		}
 
-	if err != nil {
-		log.Errorln("error caught while loading aws config: ", err)
-		ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
-		return
-	}
+		s3_key, ok := getUniqueFilename(contentID.String(), params.FileName, params.IsAudioFile)
+		if !ok {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid filename").WithField("filename", "Unsupported file extension"))
+			return
+		}
 
-	// Create s3 client
-	client := s3.NewPresignClient(s3.NewFromConfig(cfg))
-	s3_key := getUniqueFilename(params.ContentID, params.FileName, params.IsAudioFile)
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
 
-	// Generate pre-signed URL for file upload
-	res, err := client.PresignPutObject(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(os.Getenv("AWS_BUCKET_NAME")),
-		Key:    aws.String(s3_key),
-		ACL:    types.ObjectCannedACLPublicRead,
-	}, s3.WithPresignExpires(time.Minute*5))
+		if _, ok := getOwnedContent(dbCfg, ctx, contentID, user.ID); !ok {
+			return
+		}
 
-	if err != nil {
-		log.Errorln("error caught while generating pre-sign upload URL: ", err)
-		ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
-		return
-	}
+		// Load default AWS config
+		cfg, err := config.LoadDefaultConfig(context.TODO())
 
-	// Prepare response data
-	resData := map[string]string{
-		"url": res.URL,
-		"key": s3_key,
-	}
+		if err != nil {
+			log.Errorln("error caught while loading aws config: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
 
-	ctx.SecureJSON(http.StatusOK, gin.H{"data": resData})
+		// Create s3 client
+		client := s3.NewPresignClient(s3.NewFromConfig(cfg))
+
+		// Generate pre-signed URL for file upload
+		res, err := client.PresignPutObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(os.Getenv("AWS_BUCKET_NAME")),
+			Key:    aws.String(s3_key),
+			ACL:    types.ObjectCannedACLPublicRead,
+		}, s3.WithPresignExpires(time.Minute*5))
+
+		if err != nil {
+			log.Errorln("error caught while generating pre-sign upload URL: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if err := database.CreateMediaUploadDB(dbCfg, ctx, database.CreateMediaUploadParams{
+			S3Key: s3_key,
+			CreatedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			UserID:      user.ID,
+			ContentID:   contentID,
+			IsAudioFile: params.IsAudioFile,
+		}); err != nil {
+			log.Errorln("error caught while adding media upload to DB: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		// Prepare response data
+		resData := map[string]string{
+			"url": res.URL,
+			"key": s3_key,
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"data": resData})
+	}
 }
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+func TestModifyContentOwnership(t *testing.T) {
+	f := newContentFixture(t)
+	other := uuid.New()
+
+	requests := []struct {
+		method string
+		body   func(contentID string) string
+	}{
+		{
+			method: http.MethodPatch,
+			body: func(contentID string) string {
+				return `{"description":"Updated"}`
+			},
+		},
+		{
+			method: http.MethodPut,
+			body: func(contentID string) string {
+				return `{"key":"audio/` + contentID + `.mp3","is_audio_file":true}`
+			},
+		},
+		{method: http.MethodDelete},
+	}
+
+	tests := []struct {
+		name      string
+		userID    uuid.UUID
+		contentID string
+		status    int
+	}{
+		{name: "owner public", userID: f.owner, contentID: f.contents[database.ContentVisibilityPublic].ID.String()},
+		{name: "owner private", userID: f.owner, contentID: f.contents[database.ContentVisibilityPrivate].ID.String()},
+		{name: "non-owner public", userID: other, contentID: f.contents[database.ContentVisibilityPublic].ID.String(), status: http.StatusForbidden},
+		{name: "non-owner unlisted", userID: other, contentID: f.contents[database.ContentVisibilityUnlisted].ID.String(), status: http.StatusForbidden},
+		{name: "non-owner private", userID: other, contentID: f.contents[database.ContentVisibilityPrivate].ID.String(), status: http.StatusNotFound},
+		{name: "missing", userID: f.owner, contentID: uuid.NewString(), status: http.StatusNotFound},
+		{name: "malformed", userID: f.owner, contentID: "not-a-uuid", status: http.StatusBadRequest},
+	}
+
+	for _, req := range requests {
+		for _, tc := range tests {
+			t.Run(req.method+"/"+tc.name, func(t *testing.T) {
+				body := ""
+				if req.body != nil {
+					body = req.body(tc.contentID)
+				}
+
+				// Requests which got past the ownership check fail in the tests, Since the DB can't be reached.
+				// Media version is added without a transaction, Others are saved in one.
+				attempts := f.attempts.Load()
+				f.db.on("AddMediaVersion", func(args []any) ([][]any, error) {
+					attempts--
+					return nil, errors.New("DB is not reachable")
+				})
+				modified := func() bool {
+					return f.attempts.Load() != attempts
+				}
+
+				rec := f.request(t, req.method, "/api/v1/"+tc.contentID+"/", body, &tc.userID)
+
+				if tc.status == 0 {
+					if rec.Code == http.StatusBadRequest || rec.Code == http.StatusForbidden || rec.Code == http.StatusNotFound {
+						t.Fatalf("expected the content to be modified, got status %d: %s", rec.Code, rec.Body.String())
+					}
+					if !modified() {
+						t.Fatalf("content was not modified, got status %d: %s", rec.Code, rec.Body.String())
+					}
+					return
+				}
+
+				if rec.Code != tc.status {
+					t.Fatalf("expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+				}
+				if modified() {
+					t.Fatal("content was modified")
+				}
+			})
+		}
+	}
+}
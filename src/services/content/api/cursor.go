@@ -0,0 +1,279 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+)
+
+const (
+	defaultPageSize = 10
+	maxPageSize     = 50
+)
+
+// Position of an item in a sorted list, Next or previous page starts right after it
+//
+// Key is the value of the sort column of the item, ID is used as the tie-breaker.
+// Path of the list is included, So that a cursor of one list can not be used on another.
+type cursor struct {
+	Path     string    `json:"p"`
+	Sort     string    `json:"s"`
+	Key      string    `json:"k"`
+	ID       uuid.UUID `json:"i"`
+	Backward bool      `json:"b"`
+}
+
+// Secret key used for signing the cursors, Loaded at startup
+var cursorSecretKey []byte
+
+// Load the secret key used for signing the cursors, Fails if it's not configured
+func LoadCursorSecretKey() error {
+	secret := os.Getenv("CURSOR_SECRET_KEY")
+	if secret == "" {
+		return errors.New("CURSOR_SECRET_KEY is not set")
+	}
+
+	cursorSecretKey = []byte(secret)
+	return nil
+}
+
+// Sign the cursor, So that clients can not tamper with it
+func signCursor(payload string) string {
+	mac := hmac.New(sha256.New, cursorSecretKey)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Encode the cursor into an opaque string
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + signCursor(payload)
+}
+
+// Decode the given opaque string into cursor after verifying its signature
+func decodeCursor(value string) (cursor, error) {
+	var c cursor
+
+	payload, signature, found := strings.Cut(value, ".")
+	if !found || len(cursorSecretKey) == 0 || !hmac.Equal([]byte(signature), []byte(signCursor(payload))) {
+		return c, fmt.Errorf("invalid cursor signature")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return c, err
+	}
+
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
+
+// Page of a list requested by the client
+type page struct {
+	Path   string
+	Sort   string
+	Size   int32
+	Cursor *cursor
+}
+
+// Parse the page from query params, Response is sent if they are not valid
+//
+//   - sort: One of the given sort options, First one is used by default
+//   - limit: Number of items in the page, Upto maxPageSize
+//   - cursor: Next or previous cursor returned in the last page of the same list
+func getPage(ctx *gin.Context, sorts ...string) (page, bool) {
+	p := page{
+		Path: ctx.Request.URL.Path,
+		Sort: sorts[0],
+		Size: defaultPageSize,
+	}
+
+	if sort := ctx.Query("sort"); sort != "" {
+		if !slices.Contains(sorts, sort) {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid sort order"))
+			return p, false
+		}
+		p.Sort = sort
+	}
+
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid page size"))
+			return p, false
+		}
+		p.Size = int32(min(limit, maxPageSize))
+	}
+
+	if value := ctx.Query("cursor"); value != "" {
+		c, err := decodeCursor(value)
+		if err != nil || c.Path != p.Path || c.Sort != p.Sort {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid cursor"))
+			return p, false
+		}
+		p.Cursor = &c
+	}
+
+	return p, true
+}
+
+// Number of rows to be fetched, One extra row is fetched to check if there are more items
+func (p page) limit() int32 {
+	return p.Size + 1
+}
+
+// Weather or not the previous page is requested
+func (p page) backward() bool {
+	return p.Cursor != nil && p.Cursor.Backward
+}
+
+func (p page) cursorID() pgtype.UUID {
+	if p.Cursor == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{
+		Bytes: p.Cursor.ID,
+		Valid: true,
+	}
+}
+
+func (p page) timeKey() pgtype.Timestamp {
+	if p.Cursor == nil {
+		return pgtype.Timestamp{}
+	}
+
+	value, err := time.Parse(time.RFC3339Nano, p.Cursor.Key)
+	return pgtype.Timestamp{
+		Time:  value,
+		Valid: err == nil,
+	}
+}
+
+func (p page) dateKey() pgtype.Date {
+	if p.Cursor == nil {
+		return pgtype.Date{}
+	}
+
+	value, err := time.Parse(time.DateOnly, p.Cursor.Key)
+	return pgtype.Date{
+		Time:  value,
+		Valid: err == nil,
+	}
+}
+
+func (p page) textKey() pgtype.Text {
+	if p.Cursor == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{
+		String: p.Cursor.Key,
+		Valid:  true,
+	}
+}
+
+func (p page) intKey() pgtype.Int8 {
+	if p.Cursor == nil {
+		return pgtype.Int8{}
+	}
+
+	value, err := strconv.ParseInt(p.Cursor.Key, 10, 64)
+	return pgtype.Int8{
+		Int64: value,
+		Valid: err == nil,
+	}
+}
+
+func (p page) floatKey() pgtype.Float8 {
+	if p.Cursor == nil {
+		return pgtype.Float8{}
+	}
+
+	value, err := strconv.ParseFloat(p.Cursor.Key, 64)
+	return pgtype.Float8{
+		Float64: value,
+		Valid:   err == nil,
+	}
+}
+
+func timeKey(value time.Time) string {
+	return value.Format(time.RFC3339Nano)
+}
+
+func dateKey(value time.Time) string {
+	return value.Format(time.DateOnly)
+}
+
+func intKey(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
+
+func floatKey(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// Cursors of the next & previous page, Nil if there is no such page
+type pageCursors struct {
+	Next *string
+	Prev *string
+}
+
+// Trim the extra row and put the rows of a previous page back in the sort order,
+// Cursors are then built from the first & last row using the given key function.
+func paginate[T any](p page, rows []T, keyOf func(row T) (string, uuid.UUID)) ([]T, pageCursors) {
+	var cursors pageCursors
+
+	hasMore := len(rows) > int(p.Size)
+	if hasMore {
+		rows = rows[:p.Size]
+	}
+
+	if p.backward() {
+		slices.Reverse(rows)
+	}
+
+	if len(rows) == 0 {
+		return rows, cursors
+	}
+
+	hasNext, hasPrev := hasMore, p.Cursor != nil
+	if p.backward() {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	if hasNext {
+		key, id := keyOf(rows[len(rows)-1])
+		next := encodeCursor(cursor{Path: p.Path, Sort: p.Sort, Key: key, ID: id})
+		cursors.Next = &next
+	}
+
+	if hasPrev {
+		key, id := keyOf(rows[0])
+		prev := encodeCursor(cursor{Path: p.Path, Sort: p.Sort, Key: key, ID: id, Backward: true})
+		cursors.Prev = &prev
+	}
+
+	return rows, cursors
+}
+
+// Response of a list API along with the next & previous page cursors
+func pageResponse(results any, cursors pageCursors) gin.H {
+	return gin.H{
+		"results":     results,
+		"next_cursor": cursors.Next,
+		"prev_cursor": cursors.Prev,
+	}
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestLoadCursorSecretKey(t *testing.T) {
+	t.Cleanup(func() {
+		cursorSecretKey = []byte("test-cursor-key")
+	})
+
+	t.Setenv("CURSOR_SECRET_KEY", "")
+	if err := LoadCursorSecretKey(); err == nil {
+		t.Error("expected an error for the missing secret key")
+	}
+
+	t.Setenv("CURSOR_SECRET_KEY", "another-key")
+	if err := LoadCursorSecretKey(); err != nil || string(cursorSecretKey) != "another-key" {
+		t.Errorf("secret key is not loaded: %v", err)
+	}
+}
+
+func TestCursorSigning(t *testing.T) {
+	c := cursor{Path: "/api/v1/list/", Sort: "title", Key: "Song", ID: uuid.New(), Backward: true}
+	value := encodeCursor(c)
+
+	decoded, err := decodeCursor(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded != c {
+		t.Errorf("expected %+v, got %+v", c, decoded)
+	}
+
+	payload, signature, _ := strings.Cut(value, ".")
+
+	// Payload of another position signed with the original signature
+	tampered := c
+	tampered.ID = uuid.New()
+	data, err := json.Marshal(tampered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := base64.RawURLEncoding.EncodeToString(data)
+
+	invalid := map[string]string{
+		"tampered payload":   forged + "." + signature,
+		"tampered signature": payload + "." + strings.Repeat("A", len(signature)),
+		"missing signature":  payload,
+		"empty":              "",
+		"not base64":         "!!!." + signCursor("!!!"),
+	}
+	for name, value := range invalid {
+		if _, err := decodeCursor(value); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	// Cursors signed with another key are rejected
+	original := cursorSecretKey
+	cursorSecretKey = []byte("another-key")
+	otherKeyCursor := encodeCursor(c)
+	cursorSecretKey = original
+
+	if _, err := decodeCursor(otherKeyCursor); err == nil {
+		t.Error("cursor signed with another key is accepted")
+	}
+}
+
+// Parse the page of a request sent to the given URL, Returns the response status if it's rejected
+func parsePage(t *testing.T, target string, sorts ...string) (page, int) {
+	rec := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rec)
+	ctx.Request = httptest.NewRequest(http.MethodGet, target, nil)
+
+	p, ok := getPage(ctx, sorts...)
+	if ok {
+		return p, 0
+	}
+	return p, rec.Code
+}
+
+func TestGetPage(t *testing.T) {
+	listCursor := encodeCursor(cursor{Path: "/api/v1/list/", Sort: "newest", Key: "2024-03-01T12:00:00Z", ID: uuid.New()})
+
+	tests := []struct {
+		name   string
+		target string
+		size   int32
+		sort   string
+		status int
+	}{
+		{name: "defaults", target: "/api/v1/list/", size: defaultPageSize, sort: "newest"},
+		{name: "page size", target: "/api/v1/list/?limit=25", size: 25, sort: "newest"},
+		{name: "page size clamped", target: "/api/v1/list/?limit=1000", size: maxPageSize, sort: "newest"},
+		{name: "zero page size", target: "/api/v1/list/?limit=0", status: http.StatusBadRequest},
+		{name: "invalid page size", target: "/api/v1/list/?limit=ten", status: http.StatusBadRequest},
+		{name: "sort", target: "/api/v1/list/?sort=title", size: defaultPageSize, sort: "title"},
+		{name: "invalid sort", target: "/api/v1/list/?sort=random", status: http.StatusBadRequest},
+		{name: "cursor", target: "/api/v1/list/?cursor=" + url.QueryEscape(listCursor), size: defaultPageSize, sort: "newest"},
+		{name: "cursor of another sort", target: "/api/v1/list/?sort=title&cursor=" + url.QueryEscape(listCursor), status: http.StatusBadRequest},
+		{name: "cursor of another list", target: "/api/v1/trash/?cursor=" + url.QueryEscape(listCursor), status: http.StatusBadRequest},
+		{name: "invalid cursor", target: "/api/v1/list/?cursor=invalid", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, status := parsePage(t, tc.target, "newest", "title")
+			if status != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, status)
+			}
+			if tc.status != 0 {
+				return
+			}
+
+			if p.Size != tc.size || p.Sort != tc.sort {
+				t.Errorf("expected size %d & sort %s, got %d & %s", tc.size, tc.sort, p.Size, p.Sort)
+			}
+			if p.limit() != tc.size+1 {
+				t.Errorf("expected limit %d, got %d", tc.size+1, p.limit())
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	ids := make([]uuid.UUID, 4)
+	for idx := range ids {
+		ids[idx] = uuid.New()
+	}
+	keyOf := func(id uuid.UUID) (string, uuid.UUID) {
+		return id.String(), id
+	}
+
+	const path = "/api/v1/list/"
+	forward := &cursor{Path: path, Sort: "newest", ID: uuid.New()}
+	backward := &cursor{Path: path, Sort: "newest", ID: uuid.New(), Backward: true}
+
+	tests := []struct {
+		name    string
+		cursor  *cursor
+		rows    []uuid.UUID
+		results []uuid.UUID
+		next    *uuid.UUID
+		prev    *uuid.UUID
+	}{
+		{name: "first page", rows: ids[:4], results: ids[:3], next: &ids[2]},
+		{name: "only page", rows: ids[:2], results: ids[:2]},
+		{name: "empty", rows: nil, results: nil},
+		{name: "middle page", cursor: forward, rows: ids[:4], results: ids[:3], next: &ids[2], prev: &ids[0]},
+		{name: "last page", cursor: forward, rows: ids[:2], results: ids[:2], prev: &ids[0]},
+		// Previous pages are fetched in the reverse order
+		{name: "previous page", cursor: backward, rows: []uuid.UUID{ids[3], ids[2], ids[1], ids[0]}, results: []uuid.UUID{ids[1], ids[2], ids[3]}, next: &ids[3], prev: &ids[1]},
+		{name: "first previous page", cursor: backward, rows: []uuid.UUID{ids[1], ids[0]}, results: []uuid.UUID{ids[0], ids[1]}, next: &ids[1]},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := page{Path: path, Sort: "newest", Size: 3, Cursor: tc.cursor}
+			results, cursors := paginate(p, append([]uuid.UUID{}, tc.rows...), keyOf)
+
+			if len(results) != len(tc.results) {
+				t.Fatalf("expected results %v, got %v", tc.results, results)
+			}
+			for idx := range results {
+				if results[idx] != tc.results[idx] {
+					t.Fatalf("expected results %v, got %v", tc.results, results)
+				}
+			}
+
+			checkCursor := func(name string, value *string, expected *uuid.UUID, backward bool) {
+				if expected == nil {
+					if value != nil {
+						t.Errorf("%s: expected no cursor", name)
+					}
+					return
+				}
+				if value == nil {
+					t.Fatalf("%s: expected a cursor", name)
+				}
+
+				c, err := decodeCursor(*value)
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				if c.ID != *expected || c.Key != expected.String() || c.Backward != backward || c.Path != path || c.Sort != "newest" {
+					t.Errorf("%s: unexpected cursor %+v", name, c)
+				}
+			}
+			checkCursor("next", cursors.Next, tc.next, false)
+			checkCursor("prev", cursors.Prev, tc.prev, true)
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Handler of a query, Returns the result rows with their values in the scan order
+type fakeQuery func(args []any) ([][]any, error)
+
+// In-memory stand-in of the DB used by the API tests
+//
+// Queries are matched by their sqlc name, Running a query which is not registered fails the request.
+type fakeDB struct {
+	mu      sync.Mutex
+	queries map[string]fakeQuery
+
+	// SQL of the queries which were run, By their names
+	ran map[string]string
+
+	// Number of the committed transactions
+	committed int
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{queries: make(map[string]fakeQuery), ran: make(map[string]string)}
+}
+
+// Register the handler of the given query
+func (db *fakeDB) on(name string, query fakeQuery) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.queries[name] = query
+}
+
+// Return the SQL of the given query if it was run
+func (db *fakeDB) sql(name string) string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.ran[name]
+}
+
+// Return the sqlc name of the given query, Which is written on its first line as "-- name: <Name> :<kind>"
+func queryName(sql string) string {
+	line, _, _ := strings.Cut(sql, "\n")
+
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return line
+	}
+	return fields[2]
+}
+
+func (db *fakeDB) run(sql string, args []any) ([][]any, error) {
+	name := queryName(sql)
+
+	db.mu.Lock()
+	query, exists := db.queries[name]
+	db.ran[name] = sql
+	db.mu.Unlock()
+
+	if !exists {
+		return nil, fmt.Errorf("unexpected query: %s", name)
+	}
+	return query(args)
+}
+
+func (db *fakeDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	rows, err := db.run(sql, args)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return pgconn.NewCommandTag(fmt.Sprintf("UPDATE %d", len(rows))), nil
+}
+
+func (db *fakeDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := db.run(sql, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{rows: rows, idx: -1}, nil
+}
+
+func (db *fakeDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	rows, err := db.run(sql, args)
+	if err == nil && len(rows) == 0 {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		return fakeRow{err: err}
+	}
+	return fakeRow{values: rows[0]}
+}
+
+func (db *fakeDB) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return 0, fmt.Errorf("unexpected copy into: %v", tableName)
+}
+
+// Start a transaction whose queries are run by the same handlers
+//
+// Handlers apply their changes right away, So a rolled back transaction only skips the commit count.
+func (db *fakeDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	return &fakeTx{fakeDB: db}, nil
+}
+
+// Return the number of the committed transactions
+func (db *fakeDB) commits() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.committed
+}
+
+type fakeTx struct {
+	*fakeDB
+	done bool
+}
+
+func (tx *fakeTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	return tx, nil
+}
+
+func (tx *fakeTx) Commit(ctx context.Context) error {
+	if tx.done {
+		return pgx.ErrTxClosed
+	}
+	tx.done = true
+
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	tx.committed++
+	return nil
+}
+
+func (tx *fakeTx) Rollback(ctx context.Context) error {
+	if tx.done {
+		return pgx.ErrTxClosed
+	}
+	tx.done = true
+	return nil
+}
+
+func (tx *fakeTx) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	panic("batches are not supported by the fake DB")
+}
+
+func (tx *fakeTx) LargeObjects() pgx.LargeObjects {
+	panic("large objects are not supported by the fake DB")
+}
+
+func (tx *fakeTx) Prepare(ctx context.Context, name, sql string) (*pgconn.StatementDescription, error) {
+	return nil, fmt.Errorf("unexpected prepare: %s", name)
+}
+
+func (tx *fakeTx) Conn() *pgx.Conn {
+	return nil
+}
+
+// Return the fields of the given struct as a result row
+func rowOf(value any) []any {
+	fields := reflect.ValueOf(value)
+
+	row := make([]any, fields.NumField())
+	for idx := range row {
+		row[idx] = fields.Field(idx).Interface()
+	}
+	return row
+}
+
+// Copy the row values into the scan destinations, nil values set the destinations to their zero value
+func scanValues(values []any, dest []any) error {
+	if len(values) != len(dest) {
+		return fmt.Errorf("scanning %d values into %d destinations", len(values), len(dest))
+	}
+
+	for idx, value := range values {
+		target := reflect.ValueOf(dest[idx]).Elem()
+		if value == nil {
+			target.SetZero()
+			continue
+		}
+
+		source := reflect.ValueOf(value)
+		if !source.Type().AssignableTo(target.Type()) {
+			if !source.CanConvert(target.Type()) {
+				return fmt.Errorf("can not scan %T into %s", value, target.Type())
+			}
+			source = source.Convert(target.Type())
+		}
+		target.Set(source)
+	}
+	return nil
+}
+
+type fakeRow struct {
+	values []any
+	err    error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	return scanValues(r.values, dest)
+}
+
+type fakeRows struct {
+	rows [][]any
+	idx  int
+}
+
+func (r *fakeRows) Close() {}
+
+func (r *fakeRows) Err() error {
+	return nil
+}
+
+func (r *fakeRows) CommandTag() pgconn.CommandTag {
+	return pgconn.NewCommandTag(fmt.Sprintf("SELECT %d", len(r.rows)))
+}
+
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription {
+	return nil
+}
+
+func (r *fakeRows) Next() bool {
+	r.idx++
+	return r.idx < len(r.rows)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	return scanValues(r.rows[r.idx], dest)
+}
+
+func (r *fakeRows) Values() ([]any, error) {
+	return r.rows[r.idx], nil
+}
+
+func (r *fakeRows) RawValues() [][]byte {
+	return nil
+}
+
+func (r *fakeRows) Conn() *pgx.Conn {
+	return nil
+}
@@ -0,0 +1,9 @@
+package api
+
+import _ "embed"
+
+// OpenAPI spec of the REST APIs, Routes are generated from it into openapi.gen.go
+//
+//go:generate go run github.com/thejasmeetsingh/spotify-clone/src/services/common/apigen -spec openapi.json -package api -out openapi.gen.go
+//go:embed openapi.json
+var openAPISpec []byte
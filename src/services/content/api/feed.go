@@ -0,0 +1,316 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+// Maximum number of episodes listed in the feed, Podcast apps only fetch the recent ones anyway
+const maxFeedEpisodes = 300
+
+type feedCDATA struct {
+	Text string `xml:",cdata"`
+}
+
+type feedLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type feedImage struct {
+	Href string `xml:"href,attr"`
+}
+
+type feedCategory struct {
+	Text string `xml:"text,attr"`
+}
+
+type feedEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type feedGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type feedResource struct {
+	URL  string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type feedItem struct {
+	Title       string        `xml:"title"`
+	Description feedCDATA     `xml:"description"`
+	Encoded     *feedCDATA    `xml:"content:encoded,omitempty"`
+	GUID        feedGUID      `xml:"guid"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	Enclosure   feedEnclosure `xml:"enclosure"`
+	Duration    int32         `xml:"itunes:duration,omitempty"`
+	Season      int32         `xml:"itunes:season,omitempty"`
+	Episode     int32         `xml:"itunes:episode,omitempty"`
+	EpisodeType string        `xml:"itunes:episodeType"`
+	Chapters    *feedResource `xml:"podcast:chapters,omitempty"`
+	Transcript  *feedResource `xml:"podcast:transcript,omitempty"`
+}
+
+type feedChannel struct {
+	Title         string         `xml:"title"`
+	Link          string         `xml:"link"`
+	AtomLink      feedLink       `xml:"atom:link"`
+	Description   feedCDATA      `xml:"description"`
+	Language      string         `xml:"language"`
+	LastBuildDate string         `xml:"lastBuildDate"`
+	Generator     string         `xml:"generator"`
+	Author        string         `xml:"itunes:author,omitempty"`
+	Summary       feedCDATA      `xml:"itunes:summary"`
+	Image         *feedImage     `xml:"itunes:image,omitempty"`
+	Categories    []feedCategory `xml:"itunes:category"`
+	Explicit      string         `xml:"itunes:explicit"`
+	Type          string         `xml:"itunes:type"`
+	GUID          string         `xml:"podcast:guid"`
+	Items         []feedItem     `xml:"item"`
+}
+
+type feedRSS struct {
+	XMLName   xml.Name    `xml:"rss"`
+	Version   string      `xml:"version,attr"`
+	ITunesNS  string      `xml:"xmlns:itunes,attr"`
+	PodcastNS string      `xml:"xmlns:podcast,attr"`
+	ContentNS string      `xml:"xmlns:content,attr"`
+	AtomNS    string      `xml:"xmlns:atom,attr"`
+	Channel   feedChannel `xml:"channel"`
+}
+
+// Return the public URL of the feed of given show
+func getShowFeedURL(showID uuid.UUID) string {
+	return os.Getenv("FEED_BASE_URL") + "/api/v1/shows/" + showID.String() + "/feed.xml"
+}
+
+// Guess the MIME type of the chapters or transcript file from its extension
+func getResourceType(resourceURL string, defaultType string) string {
+	switch strings.ToLower(path.Ext(strings.SplitN(resourceURL, "?", 2)[0])) {
+	case ".vtt":
+		return "text/vtt"
+	case ".srt":
+		return "application/x-subrip"
+	case ".html":
+		return "text/html"
+	case ".txt":
+		return "text/plain"
+	}
+	return defaultType
+}
+
+// Return the value used for the iTunes boolean tags
+func feedBool(value bool) string {
+	if value {
+		return "true"
+	}
+	return "false"
+}
+
+func buildShowFeed(show *database.PodcastShow, episodes []database.GetShowFeedEpisodesRow, lastModified time.Time) feedRSS {
+	feedURL := getShowFeedURL(show.ID)
+
+	channel := feedChannel{
+		Title: show.Title,
+		Link:  feedURL,
+		AtomLink: feedLink{
+			Href: feedURL,
+			Rel:  "self",
+			Type: "application/rss+xml",
+		},
+		Description:   feedCDATA{Text: show.Description},
+		Language:      show.Language,
+		LastBuildDate: lastModified.Format(time.RFC1123Z),
+		Generator:     "Spotify Clone",
+		Author:        show.Author,
+		Summary:       feedCDATA{Text: show.Description},
+		Explicit:      feedBool(show.Explicit),
+		Type:          "episodic",
+		GUID:          show.ID.String(),
+		Items:         make([]feedItem, 0, len(episodes)),
+	}
+
+	if show.ArtworkUrl.Valid {
+		channel.Image = &feedImage{Href: show.ArtworkUrl.String}
+	}
+
+	for _, category := range show.Categories {
+		channel.Categories = append(channel.Categories, feedCategory{Text: category})
+	}
+
+	for _, episode := range episodes {
+		item := feedItem{
+			Title:       episode.Title,
+			Description: feedCDATA{Text: episode.Description},
+			GUID: feedGUID{
+				Value: episode.ID.String(),
+			},
+			Enclosure: feedEnclosure{
+				URL:    os.Getenv("AWS_CDN_BASE_URL") + "/" + episode.DownloadKey.String,
+				Length: episode.DownloadSize.Int64,
+				Type:   "audio/mp4",
+			},
+			Duration:    episode.Duration.Int32,
+			Season:      episode.SeasonNumber.Int32,
+			Episode:     episode.EpisodeNumber.Int32,
+			EpisodeType: "full",
+		}
+
+		// Keep the original GUID of the imported episodes, So that the podcast apps do not list them again
+		if episode.Guid.Valid {
+			item.GUID.Value = episode.Guid.String
+		}
+
+		if episode.PublishedAt.Valid {
+			item.PubDate = episode.PublishedAt.Time.Format(time.RFC1123Z)
+		}
+
+		if episode.ShowNotes.Valid {
+			item.Encoded = &feedCDATA{Text: episode.ShowNotes.String}
+		}
+
+		if episode.ChaptersUrl.Valid {
+			item.Chapters = &feedResource{
+				URL:  episode.ChaptersUrl.String,
+				Type: "application/json+chapters",
+			}
+		}
+
+		if episode.TranscriptUrl.Valid {
+			item.Transcript = &feedResource{
+				URL:  episode.TranscriptUrl.String,
+				Type: getResourceType(episode.TranscriptUrl.String, "application/json"),
+			}
+		}
+
+		channel.Items = append(channel.Items, item)
+	}
+
+	return feedRSS{
+		Version:   "2.0",
+		ITunesNS:  "http://www.itunes.com/dtds/podcast-1.0.dtd",
+		PodcastNS: "https://podcastindex.org/namespace/1.0",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		Channel:   channel,
+	}
+}
+
+// Check weather or not the feed cached by the client is still fresh
+func isFeedNotModified(ctx *gin.Context, etag string, lastModified time.Time) bool {
+	// If-None-Match takes precedence over If-Modified-Since as per RFC 9110
+	if ifNoneMatch := ctx.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		for _, value := range strings.Split(ifNoneMatch, ",") {
+			value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+			if value == etag || value == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ifModifiedSince := ctx.GetHeader("If-Modified-Since"); ifModifiedSince != "" {
+		if t, err := http.ParseTime(ifModifiedSince); err == nil {
+			return !lastModified.After(t)
+		}
+	}
+	return false
+}
+
+// API for getting the RSS feed of a podcast show
+// Non-auth API: Feed is public, So that the show can be listed in other podcast apps
+//
+// Only the published episodes whose media file is ready are listed.
+// ETag and Last-Modified headers are returned, So that the feed crawlers can make conditional requests.
+func getShowFeed(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		showID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid show ID"))
+			return
+		}
+
+		dbShow, err := database.GetShowDetailDB(dbCfg, ctx, showID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.NotFound("Show not found"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while fetching show detail: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbEpisodes, err := database.GetShowFeedEpisodesDB(dbCfg, ctx, database.GetShowFeedEpisodesParams{
+			ShowID: pgtype.UUID{
+				Bytes: showID,
+				Valid: true,
+			},
+			PublishedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			Limit: maxFeedEpisodes,
+		})
+		if err != nil {
+			log.Errorln("error caught while fetching show feed episodes: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		// Feed is modified whenever the show, An episode or the list of published episodes is changed
+		lastModified := dbShow.ModifiedAt.Time
+		for _, episode := range dbEpisodes {
+			if episode.ModifiedAt.Time.After(lastModified) {
+				lastModified = episode.ModifiedAt.Time
+			}
+			if episode.PublishedAt.Valid && episode.PublishedAt.Time.After(lastModified) {
+				lastModified = episode.PublishedAt.Time
+			}
+		}
+		lastModified = lastModified.UTC().Truncate(time.Second)
+
+		body, err := xml.MarshalIndent(buildShowFeed(dbShow, dbEpisodes, lastModified), "", "  ")
+		if err != nil {
+			log.Errorln("error caught while rendering show feed: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+		body = append([]byte(xml.Header), body...)
+
+		hash := sha256.Sum256(body)
+		etag := strconv.Quote(hex.EncodeToString(hash[:16]))
+
+		ctx.Header("ETag", etag)
+		ctx.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+		ctx.Header("Cache-Control", "public, max-age=300")
+
+		if isFeedNotModified(ctx, etag, lastModified) {
+			ctx.Status(http.StatusNotModified)
+			return
+		}
+
+		ctx.Data(http.StatusOK, "application/rss+xml; charset=utf-8", body)
+	}
+}
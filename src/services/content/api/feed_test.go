@@ -0,0 +1,278 @@
+package api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apidocs/apitest"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+const (
+	itunesNS  = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+	podcastNS = "https://podcastindex.org/namespace/1.0"
+	contentNS = "http://purl.org/rss/1.0/modules/content/"
+	atomNS    = "http://www.w3.org/2005/Atom"
+)
+
+// Feed as read by the podcast apps, Tags are matched by their namespace instead of the prefix
+type parsedFeed struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	Channel struct {
+		Title    string `xml:"title"`
+		AtomLink struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"http://www.w3.org/2005/Atom link"`
+		Description   string `xml:"description"`
+		Language      string `xml:"language"`
+		LastBuildDate string `xml:"lastBuildDate"`
+		Author        string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author"`
+		Image         struct {
+			Href string `xml:"href,attr"`
+		} `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+		Categories []struct {
+			Text string `xml:"text,attr"`
+		} `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd category"`
+		Explicit string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd explicit"`
+		Type     string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd type"`
+		GUID     string `xml:"https://podcastindex.org/namespace/1.0 guid"`
+		Items    []struct {
+			Title       string `xml:"title"`
+			Description string `xml:"description"`
+			Encoded     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+			GUID        struct {
+				IsPermaLink string `xml:"isPermaLink,attr"`
+				Value       string `xml:",chardata"`
+			} `xml:"guid"`
+			PubDate   string `xml:"pubDate"`
+			Enclosure struct {
+				URL    string `xml:"url,attr"`
+				Length int64  `xml:"length,attr"`
+				Type   string `xml:"type,attr"`
+			} `xml:"enclosure"`
+			Duration    int32  `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+			Season      int32  `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd season"`
+			Episode     int32  `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+			EpisodeType string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episodeType"`
+			Chapters    *struct {
+				URL  string `xml:"url,attr"`
+				Type string `xml:"type,attr"`
+			} `xml:"https://podcastindex.org/namespace/1.0 chapters"`
+			Transcript *struct {
+				URL  string `xml:"url,attr"`
+				Type string `xml:"type,attr"`
+			} `xml:"https://podcastindex.org/namespace/1.0 transcript"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+func TestGetShowFeed(t *testing.T) {
+	t.Setenv("FEED_BASE_URL", "https://api.example.com")
+	t.Setenv("AWS_CDN_BASE_URL", "https://cdn.example.com")
+
+	modifiedAt := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	publishedAt := modifiedAt.Add(36 * time.Hour)
+
+	show := database.PodcastShow{
+		ID:          uuid.New(),
+		CreatedAt:   pgtype.Timestamp{Time: modifiedAt, Valid: true},
+		ModifiedAt:  pgtype.Timestamp{Time: modifiedAt, Valid: true},
+		UserID:      uuid.New(),
+		Title:       "Show & Tell",
+		Author:      "Host",
+		Description: "Weekly <b>show</b>",
+		ArtworkUrl:  pgtype.Text{String: "https://cdn.example.com/artwork.jpg", Valid: true},
+		Categories:  []string{"Technology", "News"},
+		Explicit:    true,
+		Language:    "en",
+	}
+
+	imported := database.GetShowFeedEpisodesRow{
+		ID:            uuid.New(),
+		ModifiedAt:    pgtype.Timestamp{Time: modifiedAt.Add(time.Hour), Valid: true},
+		Title:         "Imported episode",
+		Description:   "Imported description",
+		DownloadKey:   pgtype.Text{String: "podcasts/imported.m4a", Valid: true},
+		DownloadSize:  pgtype.Int8{Int64: 1024, Valid: true},
+		Duration:      pgtype.Int4{Int32: 1800, Valid: true},
+		SeasonNumber:  pgtype.Int4{Int32: 2, Valid: true},
+		EpisodeNumber: pgtype.Int4{Int32: 5, Valid: true},
+		PublishedAt:   pgtype.Timestamp{Time: publishedAt, Valid: true},
+		ShowNotes:     pgtype.Text{String: "<p>Show notes</p>", Valid: true},
+		Guid:          pgtype.Text{String: "original-guid", Valid: true},
+		ChaptersUrl:   pgtype.Text{String: "https://example.com/chapters.json", Valid: true},
+		TranscriptUrl: pgtype.Text{String: "https://example.com/transcript.vtt?v=1", Valid: true},
+	}
+	uploaded := database.GetShowFeedEpisodesRow{
+		ID:           uuid.New(),
+		ModifiedAt:   pgtype.Timestamp{Time: modifiedAt, Valid: true},
+		Title:        "Uploaded episode",
+		Description:  "Uploaded description",
+		DownloadKey:  pgtype.Text{String: "podcasts/uploaded.m4a", Valid: true},
+		DownloadSize: pgtype.Int8{Int64: 2048, Valid: true},
+	}
+
+	db := newFakeDB()
+	db.on("GetShowById", func(args []any) ([][]any, error) {
+		if args[0] != show.ID {
+			return nil, nil
+		}
+		return [][]any{rowOf(show)}, nil
+	})
+	db.on("GetShowFeedEpisodes", func(args []any) ([][]any, error) {
+		params := database.GetShowFeedEpisodesParams{ShowID: args[0].(pgtype.UUID), PublishedAt: args[1].(pgtype.Timestamp), Limit: args[2].(int32)}
+		if params.ShowID.Bytes != show.ID || params.Limit != maxFeedEpisodes || time.Since(params.PublishedAt.Time) > time.Minute {
+			t.Errorf("unexpected feed episodes params: %+v", params)
+		}
+		return [][]any{rowOf(imported), rowOf(uploaded)}, nil
+	})
+
+	engine := gin.New()
+	engine.Use(apitest.LoadSpec(t, openAPISpec).Validator(t))
+	Routes(engine, &database.Config{DB: db, Queries: database.New(db)})
+
+	path := "/api/v1/shows/" + show.ID.String() + "/feed.xml"
+	get := func(t *testing.T, path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		return serve(t, engine, req, nil)
+	}
+
+	rec := get(t, path, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if contentType := rec.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/rss+xml") {
+		t.Errorf("unexpected content type: %s", contentType)
+	}
+
+	// Feed is last modified when its latest episode got published
+	etag, lastModified := rec.Header().Get("ETag"), rec.Header().Get("Last-Modified")
+	if lastModified != publishedAt.Format(http.TimeFormat) {
+		t.Errorf("expected Last-Modified %q, got %q", publishedAt.Format(http.TimeFormat), lastModified)
+	}
+	if etag == "" {
+		t.Fatal("ETag is not returned")
+	}
+
+	var feed parsedFeed
+	if err := xml.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("feed is not valid XML: %v\n%s", err, rec.Body.String())
+	}
+
+	t.Run("channel", func(t *testing.T) {
+		channel := feed.Channel
+		if feed.Version != "2.0" || channel.Title != show.Title || channel.Description != show.Description || channel.Language != "en" {
+			t.Errorf("unexpected channel: %+v", channel)
+		}
+		if channel.AtomLink.Href != "https://api.example.com"+path || channel.AtomLink.Rel != "self" {
+			t.Errorf("unexpected self link: %+v", channel.AtomLink)
+		}
+		if channel.LastBuildDate != publishedAt.Format(time.RFC1123Z) {
+			t.Errorf("unexpected last build date: %s", channel.LastBuildDate)
+		}
+		if channel.Author != "Host" || channel.Image.Href != show.ArtworkUrl.String || channel.Explicit != "true" || channel.Type != "episodic" {
+			t.Errorf("unexpected iTunes tags: %+v", channel)
+		}
+		if len(channel.Categories) != 2 || channel.Categories[0].Text != "Technology" || channel.Categories[1].Text != "News" {
+			t.Errorf("unexpected categories: %+v", channel.Categories)
+		}
+		if channel.GUID != show.ID.String() {
+			t.Errorf("unexpected podcast GUID: %s", channel.GUID)
+		}
+	})
+
+	t.Run("items", func(t *testing.T) {
+		if len(feed.Channel.Items) != 2 {
+			t.Fatalf("expected 2 items, got %d", len(feed.Channel.Items))
+		}
+
+		item := feed.Channel.Items[0]
+		if item.GUID.Value != "original-guid" || item.GUID.IsPermaLink != "false" {
+			t.Errorf("imported episode should keep its original GUID: %+v", item.GUID)
+		}
+		if item.Enclosure.URL != "https://cdn.example.com/podcasts/imported.m4a" || item.Enclosure.Length != 1024 || item.Enclosure.Type != "audio/mp4" {
+			t.Errorf("unexpected enclosure: %+v", item.Enclosure)
+		}
+		if item.PubDate != publishedAt.Format(time.RFC1123Z) || item.Encoded != "<p>Show notes</p>" {
+			t.Errorf("unexpected item: %+v", item)
+		}
+		if item.Duration != 1800 || item.Season != 2 || item.Episode != 5 || item.EpisodeType != "full" {
+			t.Errorf("unexpected iTunes tags: %+v", item)
+		}
+		if item.Chapters == nil || item.Chapters.Type != "application/json+chapters" {
+			t.Errorf("unexpected chapters: %+v", item.Chapters)
+		}
+		if item.Transcript == nil || item.Transcript.URL != imported.TranscriptUrl.String || item.Transcript.Type != "text/vtt" {
+			t.Errorf("unexpected transcript: %+v", item.Transcript)
+		}
+
+		// Optional tags are left out of the uploaded episode
+		item = feed.Channel.Items[1]
+		if item.GUID.Value != uploaded.ID.String() || item.PubDate != "" || item.Duration != 0 || item.Chapters != nil || item.Transcript != nil {
+			t.Errorf("unexpected uploaded item: %+v", item)
+		}
+		if strings.Count(rec.Body.String(), "<itunes:season>") != 1 {
+			t.Error("empty iTunes tags are rendered")
+		}
+	})
+
+	t.Run("conditional requests", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			header   http.Header
+			expected int
+		}{
+			{name: "matching ETag", header: http.Header{"If-None-Match": {etag}}, expected: http.StatusNotModified},
+			{name: "weak ETag in a list", header: http.Header{"If-None-Match": {`"stale", W/` + etag}}, expected: http.StatusNotModified},
+			{name: "any ETag", header: http.Header{"If-None-Match": {"*"}}, expected: http.StatusNotModified},
+			{name: "stale ETag", header: http.Header{"If-None-Match": {`"stale"`}}, expected: http.StatusOK},
+			{name: "not modified since", header: http.Header{"If-Modified-Since": {lastModified}}, expected: http.StatusNotModified},
+			{name: "modified since", header: http.Header{"If-Modified-Since": {publishedAt.Add(-time.Second).Format(http.TimeFormat)}}, expected: http.StatusOK},
+			{name: "invalid date", header: http.Header{"If-Modified-Since": {"yesterday"}}, expected: http.StatusOK},
+			{
+				name:     "stale ETag takes precedence over date",
+				header:   http.Header{"If-None-Match": {`"stale"`}, "If-Modified-Since": {lastModified}},
+				expected: http.StatusOK,
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				rec := get(t, path, test.header)
+				if rec.Code != test.expected {
+					t.Fatalf("expected status %d, got %d", test.expected, rec.Code)
+				}
+				if rec.Header().Get("ETag") != etag || rec.Header().Get("Last-Modified") != lastModified {
+					t.Errorf("validators changed between the requests: %v", rec.Header())
+				}
+				if rec.Code == http.StatusNotModified && rec.Body.Len() != 0 {
+					t.Errorf("not modified response has a body: %s", rec.Body.String())
+				}
+			})
+		}
+	})
+
+	t.Run("unknown show", func(t *testing.T) {
+		if rec := get(t, "/api/v1/shows/"+uuid.NewString()+"/feed.xml", nil); rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("invalid show ID", func(t *testing.T) {
+		if rec := get(t, "/api/v1/shows/invalid/feed.xml", nil); rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
+)
+
+// Authenticate the storage notifications using the shared secret configured on the webhook
+func StorageWebhookAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		secret := os.Getenv("STORAGE_WEBHOOK_SECRET")
+		authToken, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+
+		if secret == "" || !ok || subtle.ConstantTimeCompare([]byte(authToken), []byte(secret)) != 1 {
+			apierror.Respond(ctx, apierror.Unauthenticated("Invalid authentication token"))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// API for the s3 event notifications of the uploaded media files
+//
+// Conversion is triggered for the new uploads, So clients don't have to call PUT :id/ after uploading.
+// Notifications which are sent again are skipped, A failed request should be retried by the sender.
+func ingestStorageEvents(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		type Parameters struct {
+			Records []struct {
+				EventName string `json:"eventName"`
+				S3        struct {
+					Bucket struct {
+						Name string `json:"name"`
+					} `json:"bucket"`
+					Object struct {
+						Key string `json:"key"`
+					} `json:"object"`
+				} `json:"s3"`
+			} `json:"Records"`
+		}
+		var params Parameters
+
+		// Parse request data
+		err := ctx.ShouldBindJSON(&params)
+		if err != nil {
+			log.Errorln("error while parsing request data: ", err)
+			apierror.Respond(ctx, apierror.FromBinding(err))
+			return
+		}
+
+		bucket := os.Getenv("AWS_BUCKET_NAME")
+		processed, skipped := 0, 0
+
+		for _, record := range params.Records {
+			if !strings.HasPrefix(record.EventName, "ObjectCreated:") || record.S3.Bucket.Name != bucket {
+				skipped++
+				continue
+			}
+
+			// Object keys are URL encoded in the notifications
+			key, err := url.QueryUnescape(record.S3.Object.Key)
+			if err != nil {
+				skipped++
+				continue
+			}
+
+			triggered, err := internal.IngestUploadedMedia(dbCfg, ctx, key)
+			if err != nil {
+				log.Errorln("error caught while ingesting uploaded media: ", err, "key: ", key)
+				apierror.Respond(ctx, err)
+				return
+			}
+
+			if triggered {
+				processed++
+			} else {
+				skipped++
+			}
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{
+			"message": "Storage events processed",
+			"data": gin.H{
+				"processed": processed,
+				"skipped":   skipped,
+			},
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+// Item type used for the saved albums in the library, Liked contents use their content type
+const libraryAlbumType = "album"
+
+// API for liking a content
+//
+// Request is idempotent, Liking an already liked content does nothing
+func likeContent(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		contentID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID"))
+			return
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if _, ok := getViewableContent(dbCfg, ctx, contentID); !ok {
+			return
+		}
+
+		if err = database.LikeContentDB(dbCfg, ctx, database.LikeContentParams{
+			ID: uuid.New(),
+			CreatedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			UserID:    user.ID,
+			ContentID: contentID,
+		}); err != nil {
+			log.Errorln("error caught while liking content: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"message": "Content added to your library"})
+	}
+}
+
+// API for removing the like from a content
+//
+// Request is idempotent, Unliking a content which is not liked does nothing
+func unlikeContent(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		contentID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID"))
+			return
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if err = database.UnlikeContentDB(dbCfg, ctx, database.UnlikeContentParams{
+			UserID:    user.ID,
+			ContentID: contentID,
+		}); err != nil {
+			log.Errorln("error caught while unliking content: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"message": "Content removed from your library"})
+	}
+}
+
+// API for saving an album into the library
+//
+// Request is idempotent, Saving an already saved album does nothing
+func saveAlbum(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		albumID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid album ID"))
+			return
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		_, err = database.GetAlbumDetailDB(dbCfg, ctx, albumID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.NotFound("Album not found"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while fetching album detail: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if err = database.SaveAlbumDB(dbCfg, ctx, database.SaveAlbumParams{
+			ID: uuid.New(),
+			CreatedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			UserID:  user.ID,
+			AlbumID: albumID,
+		}); err != nil {
+			log.Errorln("error caught while saving album: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"message": "Album added to your library"})
+	}
+}
+
+// API for removing an album from the library
+//
+// Request is idempotent, Removing an album which is not saved does nothing
+func unsaveAlbum(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		albumID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid album ID"))
+			return
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if err = database.UnsaveAlbumDB(dbCfg, ctx, database.UnsaveAlbumParams{
+			UserID:  user.ID,
+			AlbumID: albumID,
+		}); err != nil {
+			log.Errorln("error caught while removing saved album: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"message": "Album removed from your library"})
+	}
+}
+
+// API for getting the library of current user, Which has the liked contents and saved albums
+//
+// Query params:
+//   - type: Filter the items by their type (M, P or album)
+//   - sort: Order of the date added, newest (Default) or oldest
+func getLibrary(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		itemType := ctx.Query("type")
+		switch itemType {
+		case "", string(database.ContentTypeM), string(database.ContentTypeP), libraryAlbumType:
+		default:
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid library item type"))
+			return
+		}
+
+		p, ok := getPage(ctx, "newest", "oldest")
+		if !ok {
+			return
+		}
+
+		dbItems, err := database.GetUserLibraryDB(dbCfg, ctx, database.GetUserLibraryParams{
+			UserID: user.ID,
+			ItemType: pgtype.Text{
+				String: itemType,
+				Valid:  itemType != "",
+			},
+			CursorID:      p.cursorID(),
+			OldestFirst:   p.Sort == "oldest",
+			Backward:      p.backward(),
+			CursorAddedAt: p.timeKey(),
+			Limit:         p.limit(),
+		})
+
+		if err != nil {
+			log.Errorln("error caught while fetching user library: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbItems, cursors := paginate(p, dbItems, func(row database.GetUserLibraryRow) (string, uuid.UUID) {
+			return timeKey(row.AddedAt.Time), row.ItemID
+		})
+
+		ctx.SecureJSON(http.StatusOK, pageResponse(databaseLibraryToLibrary(dbItems), cursors))
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
+)
+
+const testKeyID = "test"
+
+var (
+	testRedis      *miniredis.Miniredis
+	testSigningKey ed25519.PrivateKey
+)
+
+// Serve the JWKS of the test signing key, Same as the user service does
+func serveJWKS(publicKey ed25519.PublicKey) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "OKP",
+				"crv": "Ed25519",
+				"use": "sig",
+				"alg": "EdDSA",
+				"kid": testKeyID,
+				"x":   base64.RawURLEncoding.EncodeToString(publicKey),
+			}},
+		})
+	}))
+}
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	log.SetOutput(io.Discard)
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	testSigningKey = privateKey
+
+	jwksServer := serveJWKS(publicKey)
+	os.Setenv("USER_JWKS_URL", jwksServer.URL)
+
+	os.Setenv("CURSOR_SECRET_KEY", "test-cursor-key")
+	if err := LoadCursorSecretKey(); err != nil {
+		panic(err)
+	}
+
+	testRedis, err = miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("REDIS_HOST", testRedis.Addr())
+
+	code := m.Run()
+
+	testRedis.Close()
+	jwksServer.Close()
+	os.Exit(code)
+}
+
+// Return a DB pool which never connects, Along with the number of connection attempts
+//
+// Transactions can not be started in the tests, So an attempt tells that the request passed all the checks before it
+func newTestPool(t *testing.T) (*pgxpool.Pool, *atomic.Int32) {
+	config, err := pgxpool.ParseConfig("postgres://test@127.0.0.1:1/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attempts := &atomic.Int32{}
+	config.BeforeConnect = func(ctx context.Context, connConfig *pgx.ConnConfig) error {
+		attempts.Add(1)
+		return errors.New("DB is not available in tests")
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool, attempts
+}
+
+// Issue an access token for the given user, Signed the same way as the user service does
+func accessToken(t *testing.T, userID uuid.UUID) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, internal.Claims{
+		Data: userID.String(),
+		Type: "access",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	token.Header["kid"] = testKeyID
+
+	signedToken, err := token.SignedString(testSigningKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signedToken
+}
+
+// Serve the given request and return the recorded response, Request is authenticated as the given user unless it's nil
+func serve(t *testing.T, engine *gin.Engine, req *http.Request, userID *uuid.UUID) *httptest.ResponseRecorder {
+	if userID != nil {
+		req.Header.Set("Authorization", "Bearer "+accessToken(t, *userID))
+	}
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+// API for getting the media versions of a content, Only the owner can view them
+func getMediaVersions(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		contentID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID"))
+			return
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbContent, err := database.GetContentDetailDB(dbCfg, ctx, contentID)
+		if errors.Is(err, pgx.ErrNoRows) || (err == nil && dbContent.UserID != user.ID) {
+			apierror.Respond(ctx, apierror.NotFound("Content not found"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while fetching content detail: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbVersions, err := database.GetContentMediaVersionsDB(dbCfg, ctx, contentID)
+		if err != nil {
+			log.Errorln("error caught while fetching media versions: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"results": databaseMediaVersionsToMediaVersions(dbVersions, dbContent.MediaVersionID)})
+	}
+}
+
+// API for switching the content back to one of its previous media versions
+func rollbackMediaVersion(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		contentID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID"))
+			return
+		}
+
+		versionID, err := uuid.Parse(ctx.Param("version_id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid version ID"))
+			return
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		// Only the ready versions of the contents owned by the user can be served
+		rows, err := database.RollbackMediaVersionDB(dbCfg, ctx, database.RollbackMediaVersionParams{
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			VersionID: versionID,
+			ContentID: contentID,
+			UserID:    user.ID,
+		})
+		if err != nil {
+			log.Errorln("error caught while rolling back media version: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if rows == 0 {
+			apierror.Respond(ctx, apierror.NotFound("Media version not found"))
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"message": "Media version restored successfully"})
+	}
+}
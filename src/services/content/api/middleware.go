@@ -1,11 +1,11 @@
 package api
 
 import (
-	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
 )
@@ -15,8 +15,7 @@ func JWTAuth(dbCfg *database.Config) gin.HandlerFunc {
 		headerAuthToken := ctx.GetHeader("Authorization")
 
 		if headerAuthToken == "" {
-			ctx.JSON(http.StatusForbidden, gin.H{"message": "Authentication required"})
-			ctx.Abort()
+			apierror.Respond(ctx, apierror.Unauthenticated("Authentication required"))
 			return
 		}
 
@@ -25,21 +24,44 @@ func JWTAuth(dbCfg *database.Config) gin.HandlerFunc {
 
 		// Validate the token string
 		if len(authToken) != 2 || authToken[0] != "Bearer" {
-			ctx.JSON(http.StatusForbidden, gin.H{"message": "Invalid authentication format"})
-			ctx.Abort()
+			apierror.Respond(ctx, apierror.Unauthenticated("Invalid authentication format"))
 			return
 		}
 
-		// Fetch user and set to the context
-		user, err := internal.GetUserDetail(ctx, authToken[1])
+		// Verify the token locally against the public keys of user service
+		userID, err := internal.VerifyAccessToken(ctx, authToken[1])
 		if err != nil {
-			log.Errorln("error while fetching user details: ", err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
-			ctx.Abort()
+			log.Errorln("error while verifying access token: ", err)
+			apierror.Respond(ctx, apierror.Unauthenticated("Invalid authentication token"))
 			return
 		}
 
-		ctx.Set("user", *user)
+		// Tokens stay valid until they expire, So the ones of deleted accounts are rejected here
+		revoked, err := internal.IsUserRevoked(ctx, userID)
+		if err != nil {
+			log.Errorln("error while checking revoked users: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+		if revoked {
+			apierror.Respond(ctx, apierror.Unauthenticated("Account is deleted"))
+			return
+		}
+
+		ctx.Set("user", internal.User{ID: userID})
 		ctx.Next()
 	}
 }
+
+// Authenticate the user only if the auth token is given, Used by the public APIs which have user specific details
+func OptionalJWTAuth(dbCfg *database.Config) gin.HandlerFunc {
+	auth := JWTAuth(dbCfg)
+
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("Authorization") == "" {
+			ctx.Next()
+			return
+		}
+		auth(ctx)
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
+)
+
+func TestJWTAuthRevokedUser(t *testing.T) {
+	engine := gin.New()
+	engine.GET("/me/", JWTAuth(nil), func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+
+	userID := uuid.New()
+	request := func() int {
+		return serve(t, engine, httptest.NewRequest(http.MethodGet, "/me/", nil), &userID).Code
+	}
+
+	if status := request(); status != http.StatusNoContent {
+		t.Fatalf("active user: expected status %d, got %d", http.StatusNoContent, status)
+	}
+
+	if err := internal.RevokeUser(context.Background(), userID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if status := request(); status != http.StatusUnauthorized {
+		t.Fatalf("revoked user: expected status %d, got %d", http.StatusUnauthorized, status)
+	}
+
+	if err := internal.RestoreUser(context.Background(), userID); err != nil {
+		t.Fatal(err)
+	}
+	if status := request(); status != http.StatusNoContent {
+		t.Fatalf("restored user: expected status %d, got %d", http.StatusNoContent, status)
+	}
+
+	// Redis being down must not let the tokens of the revoked users through
+	testRedis.SetError("server is down")
+	defer testRedis.SetError("")
+
+	if status := request(); status != http.StatusInternalServerError {
+		t.Fatalf("redis error: expected status %d, got %d", http.StatusInternalServerError, status)
+	}
+}
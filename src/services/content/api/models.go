@@ -1,29 +1,59 @@
 package api
 
 import (
+	"context"
 	"os"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
 )
 
 type Content struct {
-	ID          uuid.UUID `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	ModifiedAt  time.Time `json:"modified_at"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Type        string    `json:"type"`
-	Url         *string   `json:"url"`
+	ID             uuid.UUID         `json:"id"`
+	CreatedAt      time.Time         `json:"created_at"`
+	ModifiedAt     time.Time         `json:"modified_at"`
+	Title          string            `json:"title"`
+	Description    string            `json:"description"`
+	Type           string            `json:"type"`
+	Visibility     string            `json:"visibility"`
+	ShareURL       *string           `json:"share_url"`
+	PublishAt      *time.Time        `json:"publish_at"`
+	UnpublishAt    *time.Time        `json:"unpublish_at"`
+	Released       bool              `json:"released"`
+	Url            *string           `json:"url"`
+	AlbumID        *uuid.UUID        `json:"album_id"`
+	DiscNumber     *int32            `json:"disc_number"`
+	TrackNumber    *int32            `json:"track_number"`
+	ISRC           *string           `json:"isrc"`
+	Artists        []CreditedArtist  `json:"artists"`
+	Genres         []string          `json:"genres"`
+	Tags           []string          `json:"tags"`
+	ShowID         *uuid.UUID        `json:"show_id"`
+	SeasonNumber   *int32            `json:"season_number"`
+	EpisodeNumber  *int32            `json:"episode_number"`
+	PublishedAt    *time.Time        `json:"published_at"`
+	Duration       *int32            `json:"duration"`
+	ShowNotes      *string           `json:"show_notes"`
+	ChaptersURL    *string           `json:"chapters_url"`
+	TranscriptURL  *string           `json:"transcript_url"`
+	LikeCount      int64             `json:"like_count"`
+	ResumePosition *int32            `json:"resume_position"`
+	Creator        *internal.Creator `json:"creator"`
 }
 
 type ContentList struct {
-	ID          uuid.UUID `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Type        string    `json:"type"`
+	ID          uuid.UUID         `json:"id"`
+	CreatedAt   time.Time         `json:"created_at"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Type        string            `json:"type"`
+	Creator     *internal.Creator `json:"creator"`
+
+	userID uuid.UUID
 }
 
 func databaseContentToContent(content *database.Content) Content {
@@ -37,13 +67,32 @@ func databaseContentToContent(content *database.Content) Content {
 	}
 
 	return Content{
-		ID:          content.ID,
-		CreatedAt:   content.CreatedAt.Time,
-		ModifiedAt:  content.ModifiedAt.Time,
-		Title:       content.Title,
-		Description: content.Description,
-		Type:        string(content.Type),
-		Url:         mediaUrl,
+		ID:            content.ID,
+		CreatedAt:     content.CreatedAt.Time,
+		ModifiedAt:    content.ModifiedAt.Time,
+		Title:         content.Title,
+		Description:   content.Description,
+		Type:          string(content.Type),
+		Visibility:    string(content.Visibility),
+		PublishAt:     timestampOrNil(content.PublishAt),
+		UnpublishAt:   timestampOrNil(content.UnpublishAt),
+		Released:      content.Released,
+		Url:           mediaUrl,
+		AlbumID:       uuidOrNil(content.AlbumID),
+		DiscNumber:    int4OrNil(content.DiscNumber),
+		TrackNumber:   int4OrNil(content.TrackNumber),
+		ISRC:          textOrNil(content.Isrc),
+		Artists:       []CreditedArtist{},
+		Genres:        []string{},
+		Tags:          []string{},
+		ShowID:        uuidOrNil(content.ShowID),
+		SeasonNumber:  int4OrNil(content.SeasonNumber),
+		EpisodeNumber: int4OrNil(content.EpisodeNumber),
+		PublishedAt:   timestampOrNil(content.PublishedAt),
+		Duration:      int4OrNil(content.Duration),
+		ShowNotes:     textOrNil(content.ShowNotes),
+		ChaptersURL:   textOrNil(content.ChaptersUrl),
+		TranscriptURL: textOrNil(content.TranscriptUrl),
 	}
 }
 
@@ -57,6 +106,7 @@ func databaseContentListToContentList(dbContentList []database.GetContentListRow
 			Title:       dbContent.Title,
 			Description: dbContent.Description,
 			Type:        string(dbContent.Type),
+			userID:      dbContent.UserID,
 		})
 	}
 
@@ -73,8 +123,499 @@ func databaseUserContentListToContentList(dbContentList []database.GetUserConten
 			Title:       dbContent.Title,
 			Description: dbContent.Description,
 			Type:        string(dbContent.Type),
+			userID:      dbContent.UserID,
 		})
 	}
 
 	return contentList, nil
 }
+
+// Attach the creator details to the given content list
+//
+// Creator details are optional, So the list is returned as it is if user service is not reachable
+func addCreatorsToContentList(ctx context.Context, contentList []ContentList) []ContentList {
+	var userIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+
+	for _, content := range contentList {
+		if !seen[content.userID] {
+			seen[content.userID] = true
+			userIDs = append(userIDs, content.userID)
+		}
+	}
+
+	creators, err := internal.GetCreators(ctx, userIDs)
+	if err != nil {
+		log.Errorln("error caught while fetching content creators: ", err)
+		return contentList
+	}
+
+	for idx := range contentList {
+		if creator, exists := creators[contentList[idx].userID]; exists {
+			contentList[idx].Creator = &creator
+		}
+	}
+	return contentList
+}
+
+type Artist struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Name      string    `json:"name"`
+	Bio       string    `json:"bio"`
+}
+
+type Album struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	ArtistID    uuid.UUID `json:"artist_id"`
+	Title       string    `json:"title"`
+	Type        string    `json:"type"`
+	ReleaseDate string    `json:"release_date"`
+	UPC         *string   `json:"upc"`
+	Artist      *Artist   `json:"artist,omitempty"`
+}
+
+type CreditedArtist struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Role string    `json:"role"`
+}
+
+type Track struct {
+	ID          uuid.UUID        `json:"id"`
+	Title       string           `json:"title"`
+	Type        string           `json:"type"`
+	Url         *string          `json:"url"`
+	DiscNumber  *int32           `json:"disc_number"`
+	TrackNumber *int32           `json:"track_number"`
+	ISRC        *string          `json:"isrc"`
+	Artists     []CreditedArtist `json:"artists"`
+}
+
+func uuidOrNil(value pgtype.UUID) *uuid.UUID {
+	if !value.Valid {
+		return nil
+	}
+	id := uuid.UUID(value.Bytes)
+	return &id
+}
+
+func int4OrNil(value pgtype.Int4) *int32 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int32
+}
+
+func textOrNil(value pgtype.Text) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+// Return the CDN URL of the given media key
+func getMediaURL(key pgtype.Text) *string {
+	if len(key.String) == 0 {
+		return nil
+	}
+	url := os.Getenv("AWS_CDN_BASE_URL") + "/" + key.String
+	return &url
+}
+
+func databaseArtistToArtist(artist *database.Artist) Artist {
+	return Artist{
+		ID:        artist.ID,
+		CreatedAt: artist.CreatedAt.Time,
+		Name:      artist.Name,
+		Bio:       artist.Bio,
+	}
+}
+
+func databaseAlbumToAlbum(album *database.Album) Album {
+	return Album{
+		ID:          album.ID,
+		CreatedAt:   album.CreatedAt.Time,
+		ArtistID:    album.ArtistID,
+		Title:       album.Title,
+		Type:        string(album.Type),
+		ReleaseDate: album.ReleaseDate.Time.Format(time.DateOnly),
+		UPC:         textOrNil(album.Upc),
+	}
+}
+
+func databaseAlbumsToAlbums(dbAlbums []database.Album) []Album {
+	albums := make([]Album, 0, len(dbAlbums))
+
+	for idx := range dbAlbums {
+		albums = append(albums, databaseAlbumToAlbum(&dbAlbums[idx]))
+	}
+	return albums
+}
+
+// Group the credited artists by the content ID
+func databaseContentArtistsToCreditedArtists(dbArtists []database.GetContentArtistsRow) map[uuid.UUID][]CreditedArtist {
+	artists := make(map[uuid.UUID][]CreditedArtist)
+
+	for _, dbArtist := range dbArtists {
+		artists[dbArtist.ContentID] = append(artists[dbArtist.ContentID], CreditedArtist{
+			ID:   dbArtist.ID,
+			Name: dbArtist.Name,
+			Role: string(dbArtist.Role),
+		})
+	}
+	return artists
+}
+
+func databaseTracksToTracks(dbTracks []database.GetAlbumTracksRow, artists map[uuid.UUID][]CreditedArtist) []Track {
+	tracks := make([]Track, 0, len(dbTracks))
+
+	for _, dbTrack := range dbTracks {
+		trackArtists := artists[dbTrack.ID]
+		if trackArtists == nil {
+			trackArtists = []CreditedArtist{}
+		}
+
+		tracks = append(tracks, Track{
+			ID:          dbTrack.ID,
+			Title:       dbTrack.Title,
+			Type:        string(dbTrack.Type),
+			Url:         getMediaURL(dbTrack.S3Key),
+			DiscNumber:  int4OrNil(dbTrack.DiscNumber),
+			TrackNumber: int4OrNil(dbTrack.TrackNumber),
+			ISRC:        textOrNil(dbTrack.Isrc),
+			Artists:     trackArtists,
+		})
+	}
+	return tracks
+}
+
+type Show struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	Title       string    `json:"title"`
+	Author      string    `json:"author"`
+	Description string    `json:"description"`
+	ArtworkURL  *string   `json:"artwork_url"`
+	Categories  []string  `json:"categories"`
+	Explicit    bool      `json:"explicit"`
+	Language    string    `json:"language"`
+	FeedURL     *string   `json:"feed_url"`
+}
+
+type Episode struct {
+	ID            uuid.UUID  `json:"id"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	Url           *string    `json:"url"`
+	SeasonNumber  *int32     `json:"season_number"`
+	EpisodeNumber *int32     `json:"episode_number"`
+	PublishedAt   *time.Time `json:"published_at"`
+	Duration      *int32     `json:"duration"`
+	ShowNotes     *string    `json:"show_notes,omitempty"`
+	ChaptersURL   *string    `json:"chapters_url,omitempty"`
+	TranscriptURL *string    `json:"transcript_url,omitempty"`
+}
+
+func timestampOrNil(value pgtype.Timestamp) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
+
+func databaseShowToShow(show *database.PodcastShow) Show {
+	return Show{
+		ID:          show.ID,
+		CreatedAt:   show.CreatedAt.Time,
+		Title:       show.Title,
+		Author:      show.Author,
+		Description: show.Description,
+		ArtworkURL:  textOrNil(show.ArtworkUrl),
+		Categories:  show.Categories,
+		Explicit:    show.Explicit,
+		Language:    show.Language,
+		FeedURL:     textOrNil(show.FeedUrl),
+	}
+}
+
+func databaseContentToEpisode(content *database.Content) Episode {
+	return Episode{
+		ID:            content.ID,
+		Title:         content.Title,
+		Description:   content.Description,
+		Url:           getMediaURL(content.S3Key),
+		SeasonNumber:  int4OrNil(content.SeasonNumber),
+		EpisodeNumber: int4OrNil(content.EpisodeNumber),
+		PublishedAt:   timestampOrNil(content.PublishedAt),
+		Duration:      int4OrNil(content.Duration),
+		ShowNotes:     textOrNil(content.ShowNotes),
+		ChaptersURL:   textOrNil(content.ChaptersUrl),
+		TranscriptURL: textOrNil(content.TranscriptUrl),
+	}
+}
+
+func databaseEpisodesToEpisodes(dbEpisodes []database.GetShowEpisodesRow) []Episode {
+	episodes := make([]Episode, 0, len(dbEpisodes))
+
+	for _, dbEpisode := range dbEpisodes {
+		episodes = append(episodes, Episode{
+			ID:            dbEpisode.ID,
+			Title:         dbEpisode.Title,
+			Description:   dbEpisode.Description,
+			Url:           getMediaURL(dbEpisode.S3Key),
+			SeasonNumber:  int4OrNil(dbEpisode.SeasonNumber),
+			EpisodeNumber: int4OrNil(dbEpisode.EpisodeNumber),
+			PublishedAt:   timestampOrNil(dbEpisode.PublishedAt),
+		})
+	}
+	return episodes
+}
+
+type LibraryItem struct {
+	ID      uuid.UUID `json:"id"`
+	Type    string    `json:"type"`
+	Title   string    `json:"title"`
+	Url     *string   `json:"url"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+func databaseLibraryToLibrary(dbItems []database.GetUserLibraryRow) []LibraryItem {
+	items := make([]LibraryItem, 0, len(dbItems))
+
+	for _, dbItem := range dbItems {
+		items = append(items, LibraryItem{
+			ID:      dbItem.ItemID,
+			Type:    dbItem.ItemType,
+			Title:   dbItem.Title,
+			Url:     getMediaURL(dbItem.S3Key),
+			AddedAt: dbItem.AddedAt.Time,
+		})
+	}
+	return items
+}
+
+type HistoryItem struct {
+	ID       uuid.UUID `json:"id"`
+	Title    string    `json:"title"`
+	Type     string    `json:"type"`
+	Url      *string   `json:"url"`
+	PlayedAt time.Time `json:"played_at"`
+}
+
+func databaseHistoryToHistory(dbHistory []database.GetUserPlayHistoryRow) []HistoryItem {
+	history := make([]HistoryItem, 0, len(dbHistory))
+
+	for _, dbItem := range dbHistory {
+		history = append(history, HistoryItem{
+			ID:       dbItem.ID,
+			Title:    dbItem.Title,
+			Type:     string(dbItem.Type),
+			Url:      getMediaURL(dbItem.S3Key),
+			PlayedAt: dbItem.PlayedAt.Time,
+		})
+	}
+	return history
+}
+
+type PlaybackPosition struct {
+	ContentID uuid.UUID `json:"content_id"`
+	Position  int32     `json:"position"`
+	Played    bool      `json:"played"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func databasePositionToPosition(position *database.PlaybackPosition) PlaybackPosition {
+	return PlaybackPosition{
+		ContentID: position.ContentID,
+		Position:  position.Position,
+		Played:    position.Played,
+		UpdatedAt: position.ClientUpdatedAt.Time,
+	}
+}
+
+type RecommendedContent struct {
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title"`
+	Type  string    `json:"type"`
+	Url   *string   `json:"url"`
+}
+
+func databaseRecommendationsToRecommendations(dbContents []database.GetUserRecommendationsRow) []RecommendedContent {
+	contents := make([]RecommendedContent, 0, len(dbContents))
+
+	for _, dbContent := range dbContents {
+		contents = append(contents, RecommendedContent{
+			ID:    dbContent.ID,
+			Title: dbContent.Title,
+			Type:  string(dbContent.Type),
+			Url:   getMediaURL(dbContent.S3Key),
+		})
+	}
+	return contents
+}
+
+func databaseSimilarContentToRecommendations(dbContents []database.GetSimilarContentRow) []RecommendedContent {
+	contents := make([]RecommendedContent, 0, len(dbContents))
+
+	for _, dbContent := range dbContents {
+		contents = append(contents, RecommendedContent{
+			ID:    dbContent.ID,
+			Title: dbContent.Title,
+			Type:  string(dbContent.Type),
+			Url:   getMediaURL(dbContent.S3Key),
+		})
+	}
+	return contents
+}
+
+// Convert the chart entries into recommendations, Used for the users who do not have any recommendations yet
+func chartToRecommendations(entries []internal.ChartEntry, limit int) []RecommendedContent {
+	contents := make([]RecommendedContent, 0, limit)
+
+	for _, entry := range entries {
+		if len(contents) == limit {
+			break
+		}
+
+		contents = append(contents, RecommendedContent{
+			ID:    entry.ID,
+			Title: entry.Title,
+			Type:  entry.Type,
+			Url:   entry.Url,
+		})
+	}
+	return contents
+}
+
+type Genre struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type Facet struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+func databaseGenresToGenres(dbGenres []database.Genre) []Genre {
+	genres := make([]Genre, 0, len(dbGenres))
+
+	for _, dbGenre := range dbGenres {
+		genres = append(genres, Genre{
+			Slug: dbGenre.Slug,
+			Name: dbGenre.Name,
+			Type: string(dbGenre.Type),
+		})
+	}
+	return genres
+}
+
+func databaseGenreFacetsToFacets(dbFacets []database.GetGenreFacetsRow) []Facet {
+	facets := make([]Facet, 0, len(dbFacets))
+
+	for _, dbFacet := range dbFacets {
+		facets = append(facets, Facet{
+			Value: dbFacet.Value,
+			Count: dbFacet.Count,
+		})
+	}
+	return facets
+}
+
+func databaseTagFacetsToFacets(dbFacets []database.GetTagFacetsRow) []Facet {
+	facets := make([]Facet, 0, len(dbFacets))
+
+	for _, dbFacet := range dbFacets {
+		facets = append(facets, Facet{
+			Value: dbFacet.Value,
+			Count: dbFacet.Count,
+		})
+	}
+	return facets
+}
+
+type TrashedContent struct {
+	ID        uuid.UUID `json:"id"`
+	Title     string    `json:"title"`
+	Type      string    `json:"type"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+func databaseTrashToTrashedContent(dbContents []database.GetUserTrashRow, retention time.Duration) []TrashedContent {
+	contents := make([]TrashedContent, 0, len(dbContents))
+
+	for _, dbContent := range dbContents {
+		contents = append(contents, TrashedContent{
+			ID:        dbContent.ID,
+			Title:     dbContent.Title,
+			Type:      string(dbContent.Type),
+			DeletedAt: dbContent.DeletedAt.Time,
+			PurgeAt:   dbContent.DeletedAt.Time.Add(retention),
+		})
+	}
+	return contents
+}
+
+type MediaVersion struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Version   int32     `json:"version"`
+	Status    string    `json:"status"`
+	Url       *string   `json:"url"`
+	Duration  *int32    `json:"duration"`
+	Current   bool      `json:"current"`
+}
+
+func databaseMediaVersionToMediaVersion(version *database.ContentMediaVersion, currentVersionID pgtype.UUID) MediaVersion {
+	return MediaVersion{
+		ID:        version.ID,
+		CreatedAt: version.CreatedAt.Time,
+		Version:   version.Version,
+		Status:    string(version.Status),
+		Url:       getMediaURL(version.S3Key),
+		Duration:  int4OrNil(version.Duration),
+		Current:   currentVersionID.Valid && currentVersionID.Bytes == version.ID,
+	}
+}
+
+func databaseMediaVersionsToMediaVersions(dbVersions []database.ContentMediaVersion, currentVersionID pgtype.UUID) []MediaVersion {
+	versions := make([]MediaVersion, 0, len(dbVersions))
+
+	for _, dbVersion := range dbVersions {
+		versions = append(versions, databaseMediaVersionToMediaVersion(&dbVersion, currentVersionID))
+	}
+	return versions
+}
+
+type UploadSession struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ContentID   uuid.UUID `json:"content_id"`
+	Key         string    `json:"key"`
+	IsAudioFile bool      `json:"is_audio_file"`
+	Status      string    `json:"status"`
+	PartSize    int64     `json:"part_size"`
+}
+
+func databaseUploadSessionToUploadSession(session *database.UploadSession) UploadSession {
+	return UploadSession{
+		ID:          session.ID,
+		CreatedAt:   session.CreatedAt.Time,
+		ExpiresAt:   session.ExpiresAt.Time,
+		ContentID:   session.ContentID,
+		Key:         session.S3Key,
+		IsAudioFile: session.IsAudioFile,
+		Status:      string(session.Status),
+		PartSize:    uploadPartSize,
+	}
+}
+
+type UploadPartURL struct {
+	PartNumber int32  `json:"part_number"`
+	Url        string `json:"url"`
+}
@@ -0,0 +1,531 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apidocs/apitest"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+const testWebhookSecret = "secret"
+
+// Handler of a query which returns the given rows whatever its args are
+func returns(rows ...[]any) fakeQuery {
+	return func(args []any) ([][]any, error) {
+		return rows, nil
+	}
+}
+
+// Upload sessions of the fake DB, By their IDs
+type uploadStore map[uuid.UUID]database.UploadSession
+
+func (s uploadStore) register(db *fakeDB) {
+	db.on("CreateUploadSession", func(args []any) ([][]any, error) {
+		session := database.UploadSession{
+			ID:          args[0].(uuid.UUID),
+			CreatedAt:   args[1].(pgtype.Timestamp),
+			ModifiedAt:  args[2].(pgtype.Timestamp),
+			ExpiresAt:   args[3].(pgtype.Timestamp),
+			UserID:      args[4].(uuid.UUID),
+			ContentID:   args[5].(uuid.UUID),
+			S3Key:       args[6].(string),
+			S3UploadID:  args[7].(string),
+			IsAudioFile: args[8].(bool),
+			Status:      database.UploadSessionStatusActive,
+		}
+		s[session.ID] = session
+		return [][]any{rowOf(session)}, nil
+	})
+	db.on("GetUploadSession", func(args []any) ([][]any, error) {
+		session, exists := s[args[0].(uuid.UUID)]
+		if !exists || session.UserID != args[1] {
+			return nil, nil
+		}
+		return [][]any{rowOf(session)}, nil
+	})
+	db.on("UpdateUploadSessionStatus", func(args []any) ([][]any, error) {
+		session := s[args[2].(uuid.UUID)]
+		session.Status, session.ModifiedAt = args[0].(database.UploadSessionStatus), args[1].(pgtype.Timestamp)
+		s[session.ID] = session
+		return nil, nil
+	})
+}
+
+// Encode the given podcast feed as the multipart form of the import show API
+func feedForm(t *testing.T, feed string) (string, string) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	part, err := writer.CreateFormFile("feed", "feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write([]byte(feed)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String(), writer.FormDataContentType()
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	s3 := newFakeS3(t)
+	t.Setenv("AWS_BUCKET_NAME", "media")
+	t.Setenv("AWS_CDN_BASE_URL", "https://cdn.example.com")
+	t.Setenv("FEED_BASE_URL", "https://api.example.com")
+	t.Setenv("STORAGE_WEBHOOK_SECRET", testWebhookSecret)
+
+	f := newContentFixture(t)
+	content := f.contents[database.ContentVisibilityPublic]
+	owner := f.owner
+
+	createdAt := pgtype.Timestamp{Time: time.Now().UTC().Add(-time.Hour), Valid: true}
+	s3Key := pgtype.Text{String: "audio/" + content.ID.String() + ".m3u8", Valid: true}
+
+	artist := database.Artist{
+		ID:         uuid.New(),
+		CreatedAt:  createdAt,
+		ModifiedAt: createdAt,
+		Name:       "Artist",
+		Bio:        "Bio",
+	}
+	album := database.Album{
+		ID:          uuid.New(),
+		CreatedAt:   createdAt,
+		ModifiedAt:  createdAt,
+		ArtistID:    artist.ID,
+		Title:       "Album",
+		Type:        database.AlbumTypeA,
+		ReleaseDate: pgtype.Date{Time: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+	}
+	show := database.PodcastShow{
+		ID:          uuid.New(),
+		CreatedAt:   createdAt,
+		ModifiedAt:  createdAt,
+		UserID:      owner,
+		Title:       "Show",
+		Author:      "Host",
+		Description: "Description",
+		Categories:  []string{"Music"},
+		Language:    "en",
+	}
+	version := database.ContentMediaVersion{
+		ID:         uuid.New(),
+		CreatedAt:  createdAt,
+		ModifiedAt: createdAt,
+		ContentID:  content.ID,
+		Version:    1,
+		SourceKey:  "audio/" + content.ID.String() + ".mp3",
+		S3Key:      s3Key,
+		Status:     database.MediaVersionStatusReady,
+	}
+
+	track := content
+	track.AlbumID = pgtype.UUID{Bytes: album.ID, Valid: true}
+	track.DiscNumber = pgtype.Int4{Int32: 1, Valid: true}
+	track.TrackNumber = pgtype.Int4{Int32: 1, Valid: true}
+
+	episode := content
+	episode.Type = database.ContentTypeP
+	episode.ShowID = pgtype.UUID{Bytes: show.ID, Valid: true}
+	episode.PublishedAt = createdAt
+
+	// Every list is served with a single row, So that the schema of its items is validated as well
+	f.db.on("GetContentList", returns(rowOf(database.GetContentListRow{
+		ID:          content.ID,
+		CreatedAt:   content.CreatedAt,
+		UserID:      content.UserID,
+		Title:       content.Title,
+		Description: content.Description,
+		Type:        content.Type,
+	})))
+	f.db.on("GetGenreFacets", returns(rowOf(database.GetGenreFacetsRow{Value: "rock", Count: 1})))
+	f.db.on("GetTagFacets", returns(rowOf(database.GetTagFacetsRow{Value: "live", Count: 1})))
+	f.db.on("GetUserContent", returns(rowOf(database.GetUserContentRow{
+		ID:          content.ID,
+		CreatedAt:   content.CreatedAt,
+		UserID:      content.UserID,
+		Title:       content.Title,
+		Description: content.Description,
+		Type:        content.Type,
+		Plays:       1,
+	})))
+	f.db.on("GetGenres", returns(rowOf(database.Genre{Slug: "rock", Name: "Rock", Type: database.ContentTypeM})))
+	f.db.on("GetContentGenres", returns([]any{"rock"}))
+	f.db.on("GetContentTags", returns([]any{"live"}))
+	f.db.on("GetContentArtists", returns(rowOf(database.GetContentArtistsRow{
+		ContentID: content.ID,
+		Role:      database.ArtistRoleP,
+		ID:        artist.ID,
+		Name:      artist.Name,
+	})))
+	f.db.on("AddContent", func(args []any) ([][]any, error) {
+		return [][]any{rowOf(database.Content{
+			ID:          args[0].(uuid.UUID),
+			CreatedAt:   args[1].(pgtype.Timestamp),
+			ModifiedAt:  args[2].(pgtype.Timestamp),
+			UserID:      args[3].(uuid.UUID),
+			Title:       args[4].(string),
+			Description: args[5].(string),
+			Type:        args[6].(database.ContentType),
+			Visibility:  args[7].(database.ContentVisibility),
+			ShareToken:  uuid.New(),
+			PublishAt:   args[8].(pgtype.Timestamp),
+			UnpublishAt: args[9].(pgtype.Timestamp),
+			Released:    args[10].(bool),
+		})}, nil
+	})
+	f.db.on("UpdateContentDetails", returns(rowOf(content)))
+	f.db.on("RotateShareToken", returns([]any{uuid.New()}))
+	f.db.on("GetUserTrash", returns(rowOf(database.GetUserTrashRow{
+		ID:        content.ID,
+		Title:     content.Title,
+		Type:      content.Type,
+		DeletedAt: pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+	})))
+	f.db.on("RestoreContent", returns(rowOf(content)))
+
+	// Media version of the uploaded key is reported as already existing, So that the conversion is not started
+	f.db.on("AddMediaVersion", returns())
+	f.db.on("GetMediaVersionBySourceKey", returns(rowOf(version)))
+	f.db.on("GetContentMediaVersions", returns(rowOf(version)))
+	f.db.on("RollbackMediaVersion", returns([]any{}))
+	f.db.on("GetIssuedUpload", returns())
+
+	f.db.on("CreateArtist", returns(rowOf(artist)))
+	f.db.on("GetArtistById", returns(rowOf(artist)))
+	f.db.on("IsArtistUser", returns([]any{true}))
+	f.db.on("GetArtistAlbums", returns(rowOf(album)))
+	f.db.on("CreateAlbum", returns(rowOf(album)))
+	f.db.on("GetAlbumById", returns(rowOf(album)))
+	f.db.on("AddAlbumTrack", returns(rowOf(track)))
+	f.db.on("GetAlbumTracks", returns(rowOf(database.GetAlbumTracksRow{
+		ID:          track.ID,
+		Title:       track.Title,
+		Type:        track.Type,
+		S3Key:       s3Key,
+		DiscNumber:  track.DiscNumber,
+		TrackNumber: track.TrackNumber,
+	})))
+
+	f.db.on("CreateShow", returns(rowOf(show)))
+	f.db.on("GetShowById", returns(rowOf(show)))
+	f.db.on("AddEpisode", returns(rowOf(episode)))
+	f.db.on("GetShowEpisodes", returns(rowOf(database.GetShowEpisodesRow{
+		ID:          episode.ID,
+		Title:       episode.Title,
+		Description: episode.Description,
+		S3Key:       s3Key,
+		PublishedAt: episode.PublishedAt,
+		ReleasedAt:  episode.PublishedAt,
+	})))
+	f.db.on("GetShowFeedEpisodes", returns(rowOf(database.GetShowFeedEpisodesRow{
+		ID:           episode.ID,
+		ModifiedAt:   episode.ModifiedAt,
+		Title:        episode.Title,
+		Description:  episode.Description,
+		DownloadKey:  pgtype.Text{String: "audio/" + episode.ID.String() + ".m4a", Valid: true},
+		DownloadSize: pgtype.Int8{Int64: 1024, Valid: true},
+		Duration:     pgtype.Int4{Int32: 60, Valid: true},
+		PublishedAt:  episode.PublishedAt,
+	})))
+
+	f.db.on("GetUserLibrary", returns(rowOf(database.GetUserLibraryRow{
+		ItemID:   album.ID,
+		ItemType: libraryAlbumType,
+		Title:    album.Title,
+		AddedAt:  createdAt,
+	})))
+	f.db.on("GetUserPlayHistory", returns(rowOf(database.GetUserPlayHistoryRow{
+		ID:       content.ID,
+		Title:    content.Title,
+		Type:     content.Type,
+		S3Key:    s3Key,
+		PlayedAt: createdAt,
+	})))
+	f.db.on("GetTrendingContent", returns(rowOf(database.GetTrendingContentRow{
+		ID:    content.ID,
+		Title: content.Title,
+		Type:  content.Type,
+		S3Key: s3Key,
+		Plays: 1,
+	})))
+	f.db.on("GetTopContent", returns(rowOf(database.GetTopContentRow{
+		ID:    content.ID,
+		Title: content.Title,
+		Type:  content.Type,
+		S3Key: s3Key,
+		Plays: 1,
+	})))
+	f.db.on("GetUserRecommendations", returns(rowOf(database.GetUserRecommendationsRow{
+		ID:    content.ID,
+		Title: content.Title,
+		Type:  content.Type,
+		S3Key: s3Key,
+		Score: 1,
+	})))
+	f.db.on("GetSimilarContent", returns(rowOf(database.GetSimilarContentRow{
+		ID:    content.ID,
+		Title: content.Title,
+		Type:  content.Type,
+		S3Key: s3Key,
+		Score: 1,
+	})))
+
+	positions := &positionStore{positions: make(map[[2]uuid.UUID]database.PlaybackPosition)}
+	f.db.on("GetPlaybackPosition", positions.get)
+	f.db.on("UpsertPlaybackPosition", positions.upsert)
+
+	uploads := uploadStore{}
+	uploads.register(f.db)
+
+	for _, name := range []string{
+		"DeleteContent", "AddContentGenres", "DeleteContentGenres", "AddContentTags", "DeleteContentTags", "CreateMediaUpload",
+		"AddArtistUser", "AddContentArtist", "DeleteContentArtists", "LikeContent", "UnlikeContent", "SaveAlbum", "UnsaveAlbum",
+	} {
+		f.db.on(name, returns())
+	}
+
+	// Charts cached by the other tests are loaded from the DB again
+	testRedis.Del("charts:trending")
+	testRedis.Del("charts:top:" + string(database.ContentTypeM))
+
+	spec := apitest.LoadSpec(t, openAPISpec)
+
+	// Transactions are run by the fake DB itself
+	engine := gin.New()
+	engine.Use(spec.Validator(t))
+	Routes(engine, &database.Config{DB: f.db, Queries: database.New(f.db)})
+
+	spec.CheckRoutes(t, engine.Routes())
+
+	request := func(t *testing.T, method, path, contentType, body, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		return serve(t, engine, req, nil)
+	}
+	ownerAuth := "Bearer " + accessToken(t, owner)
+
+	// Every route is called with an empty body and its path params pointing to an existing content,
+	// As anonymous and as its owner. Responses are validated by the engine.
+	for _, route := range engine.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/") {
+			continue
+		}
+
+		path := strings.NewReplacer(
+			":id", content.ID.String(),
+			":token", content.ShareToken.String(),
+			":version_id", uuid.NewString(),
+		).Replace(route.Path)
+
+		body := ""
+		if route.Method != http.MethodGet && route.Method != http.MethodDelete {
+			body = "{}"
+		}
+
+		t.Run(route.Method+" "+route.Path, func(t *testing.T) {
+			request(t, route.Method, path, "application/json", body, "")
+			request(t, route.Method, path, "application/json", body, ownerAuth)
+		})
+	}
+
+	feed, feedContentType := feedForm(t, `<rss version="2.0"><channel><title>Imported show</title></channel></rss>`)
+	storageEvents := `{"Records": [{"eventName": "ObjectCreated:Put", "s3": {"bucket": {"name": "media"}, "object": {"key": "audio/` + content.ID.String() + `_other.mp3"}}}]}`
+
+	contentPath := "/api/v1/" + content.ID.String() + "/"
+	albumPath := "/api/v1/albums/" + album.ID.String() + "/"
+	showPath := "/api/v1/shows/" + show.ID.String() + "/"
+
+	// Every operation is called with a valid request as well, In order as the user would
+	tests := []struct {
+		method        string
+		path          string
+		contentType   string
+		body          string
+		authorization string
+		status        int
+	}{
+		{method: http.MethodGet, path: "/api/v1/genres/", status: http.StatusOK},
+		{method: http.MethodPost, path: "/api/v1/add/", body: `{"title": "New content", "description": "Description", "type": "M", "genres": ["rock"], "tags": ["Live"]}`, authorization: ownerAuth, status: http.StatusCreated},
+		{method: http.MethodGet, path: "/api/v1/list/?genre=rock", status: http.StatusOK},
+		{method: http.MethodGet, path: "/api/v1/user/", authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodGet, path: contentPath, authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodPatch, path: contentPath, body: `{"description": "Updated", "tags": ["live"]}`, authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodPost, path: "/api/v1/upload-url/", body: `{"content_id": "` + content.ID.String() + `", "filename": "song.mp3", "is_audio_file": true}`, authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodPut, path: contentPath, body: `{"key": "` + version.SourceKey + `", "is_audio_file": true}`, authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodPost, path: "/api/v1/storage/events/", body: storageEvents, authorization: "Bearer " + testWebhookSecret, status: http.StatusOK},
+		{method: http.MethodGet, path: contentPath + "versions/", authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodPost, path: contentPath + "versions/" + version.ID.String() + "/rollback/", authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodPost, path: contentPath + "share/", authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodGet, path: "/api/v1/shared/" + content.ShareToken.String() + "/", status: http.StatusOK},
+		{method: http.MethodPost, path: "/api/v1/artists/", body: `{"name": "Artist", "bio": "Bio"}`, authorization: ownerAuth, status: http.StatusCreated},
+		{method: http.MethodGet, path: "/api/v1/artists/" + artist.ID.String() + "/", status: http.StatusOK},
+		{method: http.MethodPost, path: "/api/v1/artists/" + artist.ID.String() + "/users/", body: `{"user_id": "` + uuid.NewString() + `"}`, authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodPost, path: "/api/v1/albums/", body: `{"artist_id": "` + artist.ID.String() + `", "title": "Album", "type": "A", "release_date": "2024-03-01"}`, authorization: ownerAuth, status: http.StatusCreated},
+		{method: http.MethodGet, path: "/api/v1/artists/" + artist.ID.String() + "/albums/", status: http.StatusOK},
+		{method: http.MethodGet, path: albumPath, status: http.StatusOK},
+		{method: http.MethodPut, path: albumPath + "tracks/", body: `{"content_id": "` + content.ID.String() + `", "track_number": 1}`, authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodGet, path: albumPath + "tracks/", status: http.StatusOK},
+		{method: http.MethodPost, path: "/api/v1/shows/", body: `{"title": "Show", "author": "Host", "categories": ["Music"]}`, authorization: ownerAuth, status: http.StatusCreated},
+		{method: http.MethodPost, path: "/api/v1/shows/import/", contentType: feedContentType, body: feed, authorization: ownerAuth, status: http.StatusAccepted},
+		{method: http.MethodGet, path: showPath, status: http.StatusOK},
+		{method: http.MethodPost, path: showPath + "episodes/", body: `{"title": "Episode", "description": "Description", "episode_number": 1}`, authorization: ownerAuth, status: http.StatusCreated},
+		{method: http.MethodGet, path: showPath + "episodes/", status: http.StatusOK},
+		{method: http.MethodGet, path: showPath + "feed.xml", status: http.StatusOK},
+		{method: http.MethodPut, path: contentPath + "like/", authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodPut, path: albumPath + "save/", authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodGet, path: "/api/v1/library/", authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodDelete, path: contentPath + "like/", authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodDelete, path: albumPath + "save/", authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodPost, path: "/api/v1/plays/", body: `{"events": [{"content_id": "` + content.ID.String() + `", "type": "E", "position": 60, "duration_played": 60, "client": "web"}]}`, authorization: ownerAuth, status: http.StatusAccepted},
+		{method: http.MethodGet, path: "/api/v1/history/", authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodPut, path: contentPath + "position/", body: `{"position": 60, "duration": 120, "updated_at": "` + time.Now().UTC().Format(time.RFC3339) + `"}`, authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodGet, path: contentPath + "position/", authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodGet, path: "/api/v1/charts/trending/", status: http.StatusOK},
+		{method: http.MethodGet, path: "/api/v1/charts/top/", status: http.StatusOK},
+		{method: http.MethodGet, path: contentPath + "similar/", status: http.StatusOK},
+		{method: http.MethodGet, path: "/api/v1/recommendations/", authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodDelete, path: contentPath, authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodGet, path: "/api/v1/trash/", authorization: ownerAuth, status: http.StatusOK},
+		{method: http.MethodPost, path: contentPath + "restore/", authorization: ownerAuth, status: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			if tc.contentType == "" {
+				tc.contentType = "application/json"
+			}
+
+			rec := request(t, tc.method, tc.path, tc.contentType, tc.body, tc.authorization)
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	// Multipart upload APIs are called in the order of the upload, Parts are uploaded to their pre-signed URLs
+	initiateUpload := func(t *testing.T) UploadSession {
+		rec := request(t, http.MethodPost, "/api/v1/uploads/", "application/json", `{"content_id": "`+content.ID.String()+`", "filename": "song.mp3", "is_audio_file": true}`, ownerAuth)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("initiate: expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+		}
+
+		var res struct {
+			Data UploadSession `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		return res.Data
+	}
+
+	t.Run("multipart upload", func(t *testing.T) {
+		session := initiateUpload(t)
+		uploadPath := "/api/v1/uploads/" + session.ID.String() + "/"
+
+		rec := request(t, http.MethodPost, uploadPath+"parts/", "application/json", `{"part_numbers": [1, 2]}`, ownerAuth)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("part URLs: expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+
+		var partURLs struct {
+			Results []UploadPartURL `json:"results"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &partURLs); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, part := range partURLs.Results {
+			req, err := http.NewRequest(http.MethodPut, part.Url, strings.NewReader(fmt.Sprintf("part %d ", part.PartNumber)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+			if res.StatusCode != http.StatusOK {
+				t.Fatalf("uploading part %d: got status %d", part.PartNumber, res.StatusCode)
+			}
+		}
+
+		rec = request(t, http.MethodGet, uploadPath+"parts/", "application/json", "", ownerAuth)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("uploaded parts: expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+
+		var uploadedParts struct {
+			Results []struct {
+				PartNumber int32  `json:"part_number"`
+				ETag       string `json:"etag"`
+			} `json:"results"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &uploadedParts); err != nil {
+			t.Fatal(err)
+		}
+		if len(uploadedParts.Results) != 2 {
+			t.Fatalf("expected 2 uploaded parts, got %+v", uploadedParts.Results)
+		}
+
+		parts, err := json.Marshal(map[string]any{"parts": uploadedParts.Results})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec = request(t, http.MethodPost, uploadPath+"complete/", "application/json", string(parts), ownerAuth)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("complete: expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+
+		if data, exists := s3.get("media/" + session.Key); !exists || string(data) != "part 1 part 2 " {
+			t.Errorf("uploaded file is not assembled from its parts: %q", data)
+		}
+		if status := uploads[session.ID].Status; status != database.UploadSessionStatusCompleted {
+			t.Errorf("expected the upload to be completed, got %s", status)
+		}
+
+		// Completed upload can't be aborted
+		rec = request(t, http.MethodDelete, uploadPath, "application/json", "", ownerAuth)
+		if rec.Code != http.StatusConflict {
+			t.Errorf("abort: expected status %d, got %d: %s", http.StatusConflict, rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("aborted upload", func(t *testing.T) {
+		session := initiateUpload(t)
+		uploadID := uploads[session.ID].S3UploadID
+
+		rec := request(t, http.MethodDelete, "/api/v1/uploads/"+session.ID.String()+"/", "application/json", "", ownerAuth)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+
+		if s3.isUploading(uploadID) {
+			t.Error("multipart upload is not aborted")
+		}
+		if status := uploads[session.ID].Status; status != database.UploadSessionStatusAborted {
+			t.Errorf("expected the upload to be aborted, got %s", status)
+		}
+	})
+
+	spec.CheckSucceeded(t)
+}
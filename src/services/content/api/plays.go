@@ -0,0 +1,155 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
+)
+
+// Only the recent partitions are scanned for the listening history
+const playHistoryWindow = 90 * 24 * time.Hour
+
+// API for recording a batch of play events
+//
+// Events are buffered and written into DB in background, So that the clients can report them frequently.
+// Whole batch is rejected if any of its contents is not visible to the user.
+func addPlayEvents(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		type Event struct {
+			ContentID      uuid.UUID  `json:"content_id" binding:"required"`
+			Type           string     `json:"type" binding:"required"`
+			Position       int32      `json:"position" binding:"min=0"`
+			DurationPlayed int32      `json:"duration_played" binding:"min=0"`
+			Client         string     `json:"client" binding:"required,max=50"`
+			PlayedAt       *time.Time `json:"played_at"`
+		}
+		type Parameters struct {
+			Events []Event `json:"events" binding:"required,min=1,max=100,dive"`
+		}
+		var params Parameters
+
+		err := ctx.ShouldBindJSON(&params)
+		if err != nil {
+			log.Errorln("error while parsing request data: ", err)
+			apierror.Respond(ctx, apierror.FromBinding(err))
+			return
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		receivedAt := time.Now().UTC()
+		events := make([]internal.PlayEvent, 0, len(params.Events))
+
+		for _, event := range params.Events {
+			if !isValidPlayEventType(event.Type) {
+				apierror.Respond(ctx, apierror.InvalidArgument("Invalid play event type"))
+				return
+			}
+
+			// Client clocks can be ahead of the server, So the future timestamps are capped to the received time
+			playedAt := receivedAt
+			if event.PlayedAt != nil && event.PlayedAt.Before(receivedAt) {
+				playedAt = event.PlayedAt.UTC()
+			}
+
+			events = append(events, internal.PlayEvent{
+				UserID:         user.ID,
+				ContentID:      event.ContentID,
+				Type:           database.PlayEventType(event.Type),
+				Position:       event.Position,
+				DurationPlayed: event.DurationPlayed,
+				Client:         event.Client,
+				PlayedAt:       playedAt,
+				ReceivedAt:     receivedAt,
+			})
+		}
+
+		// Play history must not reveal the private contents of others
+		contentIDs := make([]uuid.UUID, 0, len(events))
+		for _, event := range events {
+			if !slices.Contains(contentIDs, event.ContentID) {
+				contentIDs = append(contentIDs, event.ContentID)
+			}
+		}
+
+		viewableIDs, err := database.GetViewableContentIDsDB(dbCfg, ctx, database.GetViewableContentIDsParams{
+			Ids:    contentIDs,
+			UserID: user.ID,
+		})
+		if err != nil {
+			log.Errorln("error caught while checking played contents: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		for idx, event := range events {
+			if !slices.Contains(viewableIDs, event.ContentID) {
+				apierror.Respond(ctx, apierror.NotFound("Content not found").WithField(fmt.Sprintf("events[%d].content_id", idx), "Content not found"))
+				return
+			}
+		}
+
+		if err = internal.BufferPlayEvents(ctx, events); err != nil {
+			log.Errorln("error caught while buffering play events: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusAccepted, gin.H{"message": "Play events recorded"})
+	}
+}
+
+// API for getting the recently played contents of current user
+func getPlayHistory(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		p, ok := getPage(ctx, "newest")
+		if !ok {
+			return
+		}
+
+		dbHistory, err := database.GetUserPlayHistoryDB(dbCfg, ctx, database.GetUserPlayHistoryParams{
+			UserID: user.ID,
+			ReceivedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC().Add(-playHistoryWindow),
+				Valid: true,
+			},
+			CursorID:       p.cursorID(),
+			Backward:       p.backward(),
+			CursorPlayedAt: p.timeKey(),
+			Limit:          p.limit(),
+		})
+
+		if err != nil {
+			log.Errorln("error caught while fetching play history: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbHistory, cursors := paginate(p, dbHistory, func(row database.GetUserPlayHistoryRow) (string, uuid.UUID) {
+			return timeKey(row.PlayedAt.Time), row.ID
+		})
+
+		ctx.SecureJSON(http.StatusOK, pageResponse(databaseHistoryToHistory(dbHistory), cursors))
+	}
+}
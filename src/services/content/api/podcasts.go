@@ -0,0 +1,342 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
+)
+
+// API for creating a podcast show
+func createShow(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		type Parameters struct {
+			Title       string   `json:"title" binding:"required,max=255"`
+			Author      string   `json:"author" binding:"max=255"`
+			Description string   `json:"description"`
+			ArtworkURL  string   `json:"artwork_url" binding:"omitempty,url"`
+			Categories  []string `json:"categories"`
+			Explicit    bool     `json:"explicit"`
+			Language    string   `json:"language" binding:"max=16"`
+		}
+		var params Parameters
+
+		err := ctx.ShouldBindJSON(&params)
+		if err != nil {
+			log.Errorln("error while parsing request data: ", err)
+			apierror.Respond(ctx, apierror.FromBinding(err))
+			return
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if params.Categories == nil {
+			params.Categories = []string{}
+		}
+
+		if params.Language == "" {
+			params.Language = "en"
+		}
+
+		dbShow, err := database.CreateShowDB(dbCfg, ctx, database.CreateShowParams{
+			ID: uuid.New(),
+			CreatedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			UserID:      user.ID,
+			Title:       strings.TrimSpace(params.Title),
+			Author:      strings.TrimSpace(params.Author),
+			Description: params.Description,
+			ArtworkUrl: pgtype.Text{
+				String: params.ArtworkURL,
+				Valid:  params.ArtworkURL != "",
+			},
+			Categories: params.Categories,
+			Explicit:   params.Explicit,
+			Language:   strings.ToLower(params.Language),
+		})
+
+		if err != nil {
+			log.Errorln("error caught while adding show to DB: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusCreated, gin.H{"data": databaseShowToShow(dbShow)})
+	}
+}
+
+// API for getting podcast show detail
+// Non-auth API: Anyone can view the show details
+func getShowDetail(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		showID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid show ID"))
+			return
+		}
+
+		dbShow, err := database.GetShowDetailDB(dbCfg, ctx, showID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.NotFound("Show not found"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while fetching show detail: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"data": databaseShowToShow(dbShow)})
+	}
+}
+
+// API for getting episodes of a podcast show, Latest first
+//
+// Episodes without a publish date are ordered by the date they were added
+// Non-auth API: Anyone can view the show episodes
+func getShowEpisodes(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		showID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid show ID"))
+			return
+		}
+
+		p, ok := getPage(ctx, "newest")
+		if !ok {
+			return
+		}
+
+		dbEpisodes, err := database.GetShowEpisodesDB(dbCfg, ctx, database.GetShowEpisodesParams{
+			ShowID: pgtype.UUID{
+				Bytes: showID,
+				Valid: true,
+			},
+			CursorID:         p.cursorID(),
+			Backward:         p.backward(),
+			CursorReleasedAt: p.timeKey(),
+			Limit:            p.limit(),
+		})
+
+		if err != nil {
+			log.Errorln("error caught while fetching show episodes: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbEpisodes, cursors := paginate(p, dbEpisodes, func(row database.GetShowEpisodesRow) (string, uuid.UUID) {
+			return timeKey(row.ReleasedAt.Time), row.ID
+		})
+
+		ctx.SecureJSON(http.StatusOK, pageResponse(databaseEpisodesToEpisodes(dbEpisodes), cursors))
+	}
+}
+
+// API for adding an episode into a podcast show
+//
+// Media file of the episode is uploaded afterwards using the same flow as any other content
+func addEpisode(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		type Parameters struct {
+			Title         string     `json:"title" binding:"required,max=255"`
+			Description   string     `json:"description" binding:"required"`
+			SeasonNumber  int32      `json:"season_number" binding:"min=0"`
+			EpisodeNumber int32      `json:"episode_number" binding:"min=0"`
+			PublishedAt   *time.Time `json:"published_at"`
+			ShowNotes     string     `json:"show_notes"`
+			ChaptersURL   string     `json:"chapters_url" binding:"omitempty,url"`
+			TranscriptURL string     `json:"transcript_url" binding:"omitempty,url"`
+		}
+		var params Parameters
+
+		err := ctx.ShouldBindJSON(&params)
+		if err != nil {
+			log.Errorln("error while parsing request data: ", err)
+			apierror.Respond(ctx, apierror.FromBinding(err))
+			return
+		}
+
+		showID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid show ID"))
+			return
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbShow, err := database.GetShowDetailDB(dbCfg, ctx, showID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.NotFound("Show not found"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while fetching show detail: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if dbShow.UserID != user.ID {
+			apierror.Respond(ctx, apierror.PermissionDenied("You are not allowed to manage this show"))
+			return
+		}
+
+		// Episode is published right away if no publish date is given
+		publishedAt := time.Now().UTC()
+		if params.PublishedAt != nil {
+			publishedAt = params.PublishedAt.UTC()
+		}
+
+		dbEpisode, err := database.AddEpisodeDB(dbCfg, ctx, database.AddEpisodeParams{
+			ID: uuid.New(),
+			CreatedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			UserID:      user.ID,
+			Title:       strings.TrimSpace(params.Title),
+			Description: params.Description,
+			ShowID: pgtype.UUID{
+				Bytes: showID,
+				Valid: true,
+			},
+			SeasonNumber: pgtype.Int4{
+				Int32: params.SeasonNumber,
+				Valid: params.SeasonNumber > 0,
+			},
+			EpisodeNumber: pgtype.Int4{
+				Int32: params.EpisodeNumber,
+				Valid: params.EpisodeNumber > 0,
+			},
+			PublishedAt: pgtype.Timestamp{
+				Time:  publishedAt,
+				Valid: true,
+			},
+			ShowNotes: pgtype.Text{
+				String: params.ShowNotes,
+				Valid:  params.ShowNotes != "",
+			},
+			ChaptersUrl: pgtype.Text{
+				String: params.ChaptersURL,
+				Valid:  params.ChaptersURL != "",
+			},
+			TranscriptUrl: pgtype.Text{
+				String: params.TranscriptURL,
+				Valid:  params.TranscriptURL != "",
+			},
+		})
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.AlreadyExists("Content with the same title already exists"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while adding episode to DB: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusCreated, gin.H{"data": databaseContentToEpisode(dbEpisode)})
+	}
+}
+
+// API for importing a podcast show from its RSS feed
+//
+// Feed can be given either as a URL in JSON body or as a file in multipart form (Key: feed).
+// Episodes are created right away and their media files are imported & converted in background.
+func importShow(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		var feed *internal.Feed
+		var feedURL string
+		var loadErr error
+
+		if ctx.ContentType() == gin.MIMEMultipartPOSTForm {
+			fileHeader, err := ctx.FormFile("feed")
+			if err != nil {
+				apierror.Respond(ctx, apierror.InvalidArgument("Feed file is required"))
+				return
+			}
+
+			file, err := fileHeader.Open()
+			if err != nil {
+				log.Errorln("error caught while opening uploaded feed: ", err)
+				apierror.Respond(ctx, err)
+				return
+			}
+			defer file.Close()
+
+			feed, loadErr = internal.ParseFeed(file)
+		} else {
+			type Parameters struct {
+				URL string `json:"url" binding:"required,url"`
+			}
+			var params Parameters
+
+			if err := ctx.ShouldBindJSON(&params); err != nil {
+				log.Errorln("error while parsing request data: ", err)
+				apierror.Respond(ctx, apierror.FromBinding(err))
+				return
+			}
+
+			feedURL = params.URL
+			feed, loadErr = internal.FetchFeed(ctx, feedURL)
+		}
+
+		if errors.Is(loadErr, internal.ErrInvalidFeed) || errors.Is(loadErr, internal.ErrFeedTooLarge) {
+			apierror.Respond(ctx, apierror.InvalidArgument(loadErr.Error()))
+			return
+		} else if loadErr != nil {
+			log.Errorln("error caught while loading podcast feed: ", loadErr)
+			apierror.Respond(ctx, apierror.InvalidArgument("Unable to load the podcast feed"))
+			return
+		}
+
+		dbShow, dbEpisodes, err := internal.ImportShow(dbCfg, ctx, user.ID, feed, feedURL)
+		if err != nil {
+			log.Errorln("error caught while importing podcast show: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusAccepted, gin.H{
+			"message": "Show imported successfully. Episode media files will be available soon",
+			"data": gin.H{
+				"show":     databaseShowToShow(dbShow),
+				"episodes": len(dbEpisodes),
+				"skipped":  len(feed.Episodes) - len(dbEpisodes),
+			},
+		})
+	}
+}
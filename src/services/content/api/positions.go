@@ -0,0 +1,141 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+// Return the percentage of the content which should be played to mark it as played
+func getPlayedThreshold() int32 {
+	threshold, err := strconv.Atoi(os.Getenv("PLAYED_THRESHOLD_PERCENT"))
+	if err != nil || threshold <= 0 || threshold > 100 {
+		threshold = 95
+	}
+	return int32(threshold)
+}
+
+// Check weather or not the given position crossed the played threshold of the content
+func isPlayed(position, duration int32) bool {
+	if duration <= 0 {
+		return false
+	}
+	return int64(position)*100 >= int64(duration)*int64(getPlayedThreshold())
+}
+
+// API for getting the playback position of a content for current user
+func getPlaybackPosition(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		contentID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID"))
+			return
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if _, ok := getViewableContent(dbCfg, ctx, contentID); !ok {
+			return
+		}
+
+		dbPosition, err := database.GetPlaybackPositionDB(dbCfg, ctx, database.GetPlaybackPositionParams{
+			UserID:    user.ID,
+			ContentID: contentID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.NotFound("Playback position not found"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while fetching playback position: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"data": databasePositionToPosition(dbPosition)})
+	}
+}
+
+// API for saving the playback position of a content for current user
+//
+// Positions are synced using last-write-wins by the client timestamp (updated_at),
+// The latest saved position is returned, Which can be different from the given one if it's older.
+// Content is marked as played once the position crosses the played threshold.
+func updatePlaybackPosition(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		type Parameters struct {
+			Position  int32      `json:"position" binding:"min=0"`
+			Duration  int32      `json:"duration" binding:"min=0"`
+			UpdatedAt *time.Time `json:"updated_at" binding:"required"`
+		}
+		var params Parameters
+
+		err := ctx.ShouldBindJSON(&params)
+		if err != nil {
+			log.Errorln("error while parsing request data: ", err)
+			apierror.Respond(ctx, apierror.FromBinding(err))
+			return
+		}
+
+		contentID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID"))
+			return
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbContent, ok := getViewableContent(dbCfg, ctx, contentID)
+		if !ok {
+			return
+		}
+
+		// Duration detected while converting the media file is preferred over the one sent by the client
+		duration := params.Duration
+		if dbContent.Duration.Valid {
+			duration = dbContent.Duration.Int32
+		}
+
+		dbPosition, err := database.SavePlaybackPositionDB(dbCfg, ctx, database.UpsertPlaybackPositionParams{
+			UserID:    user.ID,
+			ContentID: contentID,
+			Position:  params.Position,
+			Played:    isPlayed(params.Position, duration),
+			ClientUpdatedAt: pgtype.Timestamp{
+				Time:  params.UpdatedAt.UTC(),
+				Valid: true,
+			},
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+		})
+
+		if err != nil {
+			log.Errorln("error caught while saving playback position: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"data": databasePositionToPosition(dbPosition)})
+	}
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apidocs/apitest"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+// Saved playback positions of the fake DB, By user & content
+type positionStore struct {
+	mu        sync.Mutex
+	positions map[[2]uuid.UUID]database.PlaybackPosition
+}
+
+func (s *positionStore) get(args []any) ([][]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	position, exists := s.positions[[2]uuid.UUID{args[0].(uuid.UUID), args[1].(uuid.UUID)}]
+	if !exists {
+		return nil, nil
+	}
+	return [][]any{rowOf(position)}, nil
+}
+
+// Mirror of the upsert which keeps the position having the latest client time
+func (s *positionStore) upsert(args []any) ([][]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	position := database.PlaybackPosition{
+		UserID:          args[0].(uuid.UUID),
+		ContentID:       args[1].(uuid.UUID),
+		Position:        args[2].(int32),
+		Played:          args[3].(bool),
+		ClientUpdatedAt: args[4].(pgtype.Timestamp),
+		ModifiedAt:      args[5].(pgtype.Timestamp),
+	}
+	key := [2]uuid.UUID{position.UserID, position.ContentID}
+
+	if saved, exists := s.positions[key]; exists {
+		if !saved.ClientUpdatedAt.Time.Before(position.ClientUpdatedAt.Time) {
+			return nil, nil
+		}
+		position.Played = position.Played || saved.Played
+	}
+
+	s.positions[key] = position
+	return nil, nil
+}
+
+func TestPlaybackPositionSync(t *testing.T) {
+	f := newContentFixture(t)
+	content := f.contents[database.ContentVisibilityPublic]
+	listener := uuid.New()
+
+	store := &positionStore{positions: make(map[[2]uuid.UUID]database.PlaybackPosition)}
+	f.db.on("GetPlaybackPosition", store.get)
+	f.db.on("UpsertPlaybackPosition", store.upsert)
+
+	// Positions are saved in a transaction, So the routes are served by the fake DB itself
+	engine := gin.New()
+	engine.Use(apitest.LoadSpec(t, openAPISpec).Validator(t))
+	Routes(engine, &database.Config{DB: f.db, Queries: database.New(f.db)})
+
+	path := "/api/v1/" + content.ID.String() + "/position/"
+	base := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	save := func(t *testing.T, position, duration int32, updatedAt time.Time) PlaybackPosition {
+		body := fmt.Sprintf(`{"position":%d,"duration":%d,"updated_at":%q}`, position, duration, updatedAt.Format(time.RFC3339))
+		req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := serve(t, engine, req, &listener)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+
+		var res struct {
+			Data PlaybackPosition `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		return res.Data
+	}
+
+	tests := []struct {
+		name      string
+		position  int32
+		duration  int32
+		updatedAt time.Time
+		expected  PlaybackPosition
+	}{
+		{
+			name:      "first position",
+			position:  100,
+			duration:  1000,
+			updatedAt: base,
+			expected:  PlaybackPosition{Position: 100, UpdatedAt: base},
+		},
+		{
+			name:      "older position is ignored",
+			position:  50,
+			duration:  1000,
+			updatedAt: base.Add(-time.Minute),
+			expected:  PlaybackPosition{Position: 100, UpdatedAt: base},
+		},
+		{
+			name:      "same client time is ignored",
+			position:  70,
+			duration:  1000,
+			updatedAt: base,
+			expected:  PlaybackPosition{Position: 100, UpdatedAt: base},
+		},
+		{
+			// Client time of another timezone is compared in UTC
+			name:      "newer position from another device",
+			position:  400,
+			duration:  1000,
+			updatedAt: base.Add(time.Minute).In(time.FixedZone("IST", 5*60*60+30*60)),
+			expected:  PlaybackPosition{Position: 400, UpdatedAt: base.Add(time.Minute)},
+		},
+		{
+			name:      "played threshold crossed",
+			position:  960,
+			duration:  1000,
+			updatedAt: base.Add(2 * time.Minute),
+			expected:  PlaybackPosition{Position: 960, Played: true, UpdatedAt: base.Add(2 * time.Minute)},
+		},
+		{
+			name:      "played content is replayed",
+			position:  10,
+			duration:  1000,
+			updatedAt: base.Add(3 * time.Minute),
+			expected:  PlaybackPosition{Position: 10, Played: true, UpdatedAt: base.Add(3 * time.Minute)},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expected.ContentID = content.ID
+
+			saved := save(t, tc.position, tc.duration, tc.updatedAt)
+			if saved.ContentID != tc.expected.ContentID || saved.Position != tc.expected.Position ||
+				saved.Played != tc.expected.Played || !saved.UpdatedAt.Equal(tc.expected.UpdatedAt) {
+				t.Errorf("expected %+v, got %+v", tc.expected, saved)
+			}
+		})
+	}
+
+	// Latest position is returned to the other devices
+	rec := serve(t, engine, httptest.NewRequest(http.MethodGet, path, nil), &listener)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var res struct {
+		Data PlaybackPosition `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Data.Position != 10 || !res.Data.Played {
+		t.Errorf("unexpected latest position %+v", res.Data)
+	}
+
+	// Positions of the other users are kept apart
+	rec = serve(t, engine, httptest.NewRequest(http.MethodGet, path, nil), &f.owner)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for another user, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	if commits := f.db.commits(); commits != len(tests) {
+		t.Errorf("expected %d committed syncs, got %d", len(tests), commits)
+	}
+	if sql := f.db.sql("UpsertPlaybackPosition"); !strings.Contains(sql, "WHERE playback_positions.client_updated_at < EXCLUDED.client_updated_at") {
+		t.Errorf("older positions are not ignored by the upsert: %s", sql)
+	}
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
+)
+
+// API for getting the recommended contents for current user
+//
+// Recommendations are based on the similar contents of the recently played & liked contents,
+// Trending contents are returned for the users who do not have any recommendations yet.
+func getRecommendations(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		p, ok := getPage(ctx, "relevance")
+		if !ok {
+			return
+		}
+
+		dbContents, err := database.GetUserRecommendationsDB(dbCfg, ctx, database.GetUserRecommendationsParams{
+			UserID: user.ID,
+			ListenedAfter: pgtype.Timestamp{
+				Time:  time.Now().UTC().Add(-playHistoryWindow),
+				Valid: true,
+			},
+			CursorID:    p.cursorID(),
+			Backward:    p.backward(),
+			CursorScore: p.floatKey(),
+			Limit:       p.limit(),
+		})
+
+		if err != nil {
+			log.Errorln("error caught while fetching user recommendations: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if len(dbContents) != 0 || p.Cursor != nil {
+			dbContents, cursors := paginate(p, dbContents, func(row database.GetUserRecommendationsRow) (string, uuid.UUID) {
+				return floatKey(row.Score), row.ID
+			})
+
+			ctx.SecureJSON(http.StatusOK, pageResponse(databaseRecommendationsToRecommendations(dbContents), cursors))
+			return
+		}
+
+		// Cold-start user, Fallback to the trending contents
+		entries, err := internal.GetTrendingChart(dbCfg, ctx)
+		if err != nil {
+			log.Errorln("error caught while fetching trending chart: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, pageResponse(chartToRecommendations(entries, int(p.Size)), pageCursors{}))
+	}
+}
+
+// API for getting the contents similar to the given content
+// Non-auth API: Anyone can view the similar contents
+func getSimilarContent(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		contentID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID"))
+			return
+		}
+
+		if _, ok := getViewableContent(dbCfg, ctx, contentID); !ok {
+			return
+		}
+
+		p, ok := getPage(ctx, "relevance")
+		if !ok {
+			return
+		}
+
+		dbContents, err := database.GetSimilarContentDB(dbCfg, ctx, database.GetSimilarContentParams{
+			ContentID:   contentID,
+			CursorID:    p.cursorID(),
+			Backward:    p.backward(),
+			CursorScore: p.floatKey(),
+			Limit:       p.limit(),
+		})
+
+		if err != nil {
+			log.Errorln("error caught while fetching similar contents: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbContents, cursors := paginate(p, dbContents, func(row database.GetSimilarContentRow) (string, uuid.UUID) {
+			return floatKey(row.Score), row.ID
+		})
+
+		ctx.SecureJSON(http.StatusOK, pageResponse(databaseSimilarContentToRecommendations(dbContents), cursors))
+	}
+}
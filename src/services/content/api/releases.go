@@ -0,0 +1,30 @@
+package api
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+)
+
+func timestampOrNull(value *time.Time) pgtype.Timestamp {
+	if value == nil {
+		return pgtype.Timestamp{}
+	}
+	return pgtype.Timestamp{
+		Time:  value.UTC(),
+		Valid: true,
+	}
+}
+
+// Parse the release schedule of a content, Sends the error response if the schedule is invalid
+//
+// Content becomes public at publish_at and gets hidden again at unpublish_at, Both are optional.
+func parseReleaseSchedule(ctx *gin.Context, publishAt *time.Time, unpublishAt *time.Time) (pgtype.Timestamp, pgtype.Timestamp, bool) {
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		apierror.Respond(ctx, apierror.InvalidArgument("unpublish_at should be after publish_at"))
+		return pgtype.Timestamp{}, pgtype.Timestamp{}, false
+	}
+	return timestampOrNull(publishAt), timestampOrNull(unpublishAt), true
+}
@@ -4,15 +4,15 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apidocs"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
 )
 
-func Routes(engine *gin.Engine, dbConn *pgx.Conn) {
-	dbConfig := &database.Config{
-		DB:      dbConn,
-		Queries: database.New(dbConn),
-	}
+// Register the API routes, Background jobs are started separately by the main function
+func Routes(engine *gin.Engine, dbConfig *database.Config) {
+	// Assign an ID to every request, Sent back in the error responses
+	engine.Use(apierror.RequestID())
 
 	// Default route for health check
 	engine.GET("/health-check/", func(ctx *gin.Context) {
@@ -21,19 +21,13 @@ func Routes(engine *gin.Engine, dbConn *pgx.Conn) {
 		})
 	})
 
-	pubRouter := engine.Group("/api/v1/")
-	authRouter := pubRouter.Group("")
-	authRouter.Use(JWTAuth((dbConfig)))
+	// OpenAPI spec of the REST APIs along with its docs page
+	apidocs.Routes(engine, openAPISpec, "Content API")
 
-	// Non auth routes
-	pubRouter.GET("list/", getContentList(dbConfig))
-	pubRouter.GET(":id/", getContentDetail(dbConfig))
-
-	// Auth routes
-	authRouter.GET("user/", getUserContentList(dbConfig))
-	authRouter.POST("add/", addContent(dbConfig))
-	authRouter.PATCH(":id/", updateContent(dbConfig))
-	authRouter.PUT(":id/", updateContentS3Key(dbConfig))
-	authRouter.DELETE(":id/", deleteContent(dbConfig))
-	authRouter.POST("upload-url/", getPresignedURL)
+	// Operations of the OpenAPI spec, Authenticated by their security requirements
+	RegisterHandlers(engine, &server{dbConfig: dbConfig}, SecurityHandlers{
+		BearerAuth:         JWTAuth(dbConfig),
+		OptionalBearerAuth: OptionalJWTAuth(dbConfig),
+		StorageWebhook:     StorageWebhookAuth(),
+	})
 }
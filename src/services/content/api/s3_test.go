@@ -0,0 +1,189 @@
+package api
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Parts of a multipart upload which is not completed yet, By their part numbers
+type fakeMultipartUpload struct {
+	key   string
+	parts map[int][]byte
+}
+
+// In-memory stand-in of S3 used by the API tests, Serves the multipart upload APIs with path style URLs
+type fakeS3 struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	uploads map[string]*fakeMultipartUpload
+	objects map[string][]byte
+}
+
+// Start the fake S3 and point the AWS clients to it, Along with the dummy credentials
+func newFakeS3(t *testing.T) *fakeS3 {
+	s := &fakeS3{uploads: make(map[string]*fakeMultipartUpload), objects: make(map[string][]byte)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+
+	t.Setenv("AWS_ENDPOINT_URL", s.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	return s
+}
+
+// Return the object stored with the given key, Keys are prefixed by their bucket
+func (s *fakeS3) get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, exists := s.objects[key]
+	return data, exists
+}
+
+// Return weather or not the multipart upload with the given ID is neither completed nor aborted
+func (s *fakeS3) isUploading(uploadID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, exists := s.uploads[uploadID]
+	return exists
+}
+
+func etagOf(data []byte) string {
+	sum := md5.Sum(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string
+	}{Code: code})
+}
+
+func (s *fakeS3) serve(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	query := r.URL.Query()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method == http.MethodPost && query.Has("uploads") {
+		uploadID := uuid.NewString()
+		s.uploads[uploadID] = &fakeMultipartUpload{key: bucket + "/" + key, parts: make(map[int][]byte)}
+
+		xml.NewEncoder(w).Encode(struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			Bucket   string
+			Key      string
+			UploadId string
+		}{Bucket: bucket, Key: key, UploadId: uploadID})
+		return
+	}
+
+	upload, exists := s.uploads[query.Get("uploadId")]
+	if !exists || upload.key != bucket+"/"+key {
+		writeS3Error(w, http.StatusNotFound, "NoSuchUpload")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		partNumber, err := strconv.Atoi(query.Get("partNumber"))
+		if err != nil {
+			writeS3Error(w, http.StatusBadRequest, "InvalidArgument")
+			return
+		}
+
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeS3Error(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		upload.parts[partNumber] = data
+		w.Header().Set("ETag", etagOf(data))
+
+	case http.MethodGet:
+		type part struct {
+			PartNumber   int
+			ETag         string
+			Size         int
+			LastModified string
+		}
+		result := struct {
+			XMLName     xml.Name `xml:"ListPartsResult"`
+			Bucket      string
+			Key         string
+			UploadId    string
+			IsTruncated bool
+			Parts       []part `xml:"Part"`
+		}{Bucket: bucket, Key: key, UploadId: query.Get("uploadId")}
+
+		for partNumber, data := range upload.parts {
+			result.Parts = append(result.Parts, part{
+				PartNumber:   partNumber,
+				ETag:         etagOf(data),
+				Size:         len(data),
+				LastModified: time.Now().UTC().Format(time.RFC3339),
+			})
+		}
+		slices.SortFunc(result.Parts, func(a, b part) int { return a.PartNumber - b.PartNumber })
+		xml.NewEncoder(w).Encode(result)
+
+	case http.MethodPost:
+		var request struct {
+			Parts []struct {
+				PartNumber int
+				ETag       string
+			} `xml:"Part"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeS3Error(w, http.StatusBadRequest, "MalformedXML")
+			return
+		}
+
+		var object []byte
+		for _, part := range request.Parts {
+			data, exists := upload.parts[part.PartNumber]
+			if !exists || etagOf(data) != part.ETag {
+				writeS3Error(w, http.StatusBadRequest, "InvalidPart")
+				return
+			}
+			object = append(object, data...)
+		}
+
+		s.objects[upload.key] = object
+		delete(s.uploads, query.Get("uploadId"))
+
+		xml.NewEncoder(w).Encode(struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+			Bucket  string
+			Key     string
+			ETag    string
+		}{Bucket: bucket, Key: key, ETag: etagOf(object)})
+
+	case http.MethodDelete:
+		delete(s.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "unexpected S3 request: "+r.Method+" "+r.URL.String(), http.StatusNotImplemented)
+	}
+}
@@ -0,0 +1,205 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+// Handlers of the operations in the OpenAPI spec, Served by the generated RegisterHandlers
+type server struct {
+	dbConfig *database.Config
+}
+
+var _ ServerInterface = (*server)(nil)
+
+func (s *server) AddContent(ctx *gin.Context) {
+	addContent(s.dbConfig)(ctx)
+}
+
+func (s *server) CreateAlbum(ctx *gin.Context) {
+	createAlbum(s.dbConfig)(ctx)
+}
+
+func (s *server) GetAlbumDetail(ctx *gin.Context) {
+	getAlbumDetail(s.dbConfig)(ctx)
+}
+
+func (s *server) SaveAlbum(ctx *gin.Context) {
+	saveAlbum(s.dbConfig)(ctx)
+}
+
+func (s *server) UnsaveAlbum(ctx *gin.Context) {
+	unsaveAlbum(s.dbConfig)(ctx)
+}
+
+func (s *server) GetAlbumTracks(ctx *gin.Context) {
+	getAlbumTracks(s.dbConfig)(ctx)
+}
+
+func (s *server) AddAlbumTrack(ctx *gin.Context) {
+	addAlbumTrack(s.dbConfig)(ctx)
+}
+
+func (s *server) CreateArtist(ctx *gin.Context) {
+	createArtist(s.dbConfig)(ctx)
+}
+
+func (s *server) GetArtistDetail(ctx *gin.Context) {
+	getArtistDetail(s.dbConfig)(ctx)
+}
+
+func (s *server) GetArtistAlbums(ctx *gin.Context) {
+	getArtistAlbums(s.dbConfig)(ctx)
+}
+
+func (s *server) AddArtistUser(ctx *gin.Context) {
+	addArtistUser(s.dbConfig)(ctx)
+}
+
+func (s *server) GetTopChart(ctx *gin.Context) {
+	getTopChart(s.dbConfig)(ctx)
+}
+
+func (s *server) GetTrendingChart(ctx *gin.Context) {
+	getTrendingChart(s.dbConfig)(ctx)
+}
+
+func (s *server) GetGenres(ctx *gin.Context) {
+	getGenres(s.dbConfig)(ctx)
+}
+
+func (s *server) GetPlayHistory(ctx *gin.Context) {
+	getPlayHistory(s.dbConfig)(ctx)
+}
+
+func (s *server) GetLibrary(ctx *gin.Context) {
+	getLibrary(s.dbConfig)(ctx)
+}
+
+func (s *server) GetContentList(ctx *gin.Context) {
+	getContentList(s.dbConfig)(ctx)
+}
+
+func (s *server) AddPlayEvents(ctx *gin.Context) {
+	addPlayEvents(s.dbConfig)(ctx)
+}
+
+func (s *server) GetRecommendations(ctx *gin.Context) {
+	getRecommendations(s.dbConfig)(ctx)
+}
+
+func (s *server) GetSharedContent(ctx *gin.Context) {
+	getSharedContent(s.dbConfig)(ctx)
+}
+
+func (s *server) CreateShow(ctx *gin.Context) {
+	createShow(s.dbConfig)(ctx)
+}
+
+func (s *server) ImportShow(ctx *gin.Context) {
+	importShow(s.dbConfig)(ctx)
+}
+
+func (s *server) GetShowDetail(ctx *gin.Context) {
+	getShowDetail(s.dbConfig)(ctx)
+}
+
+func (s *server) GetShowEpisodes(ctx *gin.Context) {
+	getShowEpisodes(s.dbConfig)(ctx)
+}
+
+func (s *server) AddEpisode(ctx *gin.Context) {
+	addEpisode(s.dbConfig)(ctx)
+}
+
+func (s *server) GetShowFeed(ctx *gin.Context) {
+	getShowFeed(s.dbConfig)(ctx)
+}
+
+func (s *server) IngestStorageEvents(ctx *gin.Context) {
+	ingestStorageEvents(s.dbConfig)(ctx)
+}
+
+func (s *server) GetTrash(ctx *gin.Context) {
+	getTrash(s.dbConfig)(ctx)
+}
+
+func (s *server) GetPresignedURL(ctx *gin.Context) {
+	getPresignedURL(s.dbConfig)(ctx)
+}
+
+func (s *server) InitiateUpload(ctx *gin.Context) {
+	initiateUpload(s.dbConfig)(ctx)
+}
+
+func (s *server) AbortUpload(ctx *gin.Context) {
+	abortUpload(s.dbConfig)(ctx)
+}
+
+func (s *server) CompleteUpload(ctx *gin.Context) {
+	completeUpload(s.dbConfig)(ctx)
+}
+
+func (s *server) GetUploadedParts(ctx *gin.Context) {
+	getUploadedParts(s.dbConfig)(ctx)
+}
+
+func (s *server) GetUploadPartURLs(ctx *gin.Context) {
+	getUploadPartURLs(s.dbConfig)(ctx)
+}
+
+func (s *server) GetUserContentList(ctx *gin.Context) {
+	getUserContentList(s.dbConfig)(ctx)
+}
+
+func (s *server) GetContentDetail(ctx *gin.Context) {
+	getContentDetail(s.dbConfig)(ctx)
+}
+
+func (s *server) UpdateContentMedia(ctx *gin.Context) {
+	updateContentS3Key(s.dbConfig)(ctx)
+}
+
+func (s *server) UpdateContent(ctx *gin.Context) {
+	updateContent(s.dbConfig)(ctx)
+}
+
+func (s *server) DeleteContent(ctx *gin.Context) {
+	deleteContent(s.dbConfig)(ctx)
+}
+
+func (s *server) LikeContent(ctx *gin.Context) {
+	likeContent(s.dbConfig)(ctx)
+}
+
+func (s *server) UnlikeContent(ctx *gin.Context) {
+	unlikeContent(s.dbConfig)(ctx)
+}
+
+func (s *server) GetPlaybackPosition(ctx *gin.Context) {
+	getPlaybackPosition(s.dbConfig)(ctx)
+}
+
+func (s *server) UpdatePlaybackPosition(ctx *gin.Context) {
+	updatePlaybackPosition(s.dbConfig)(ctx)
+}
+
+func (s *server) RestoreContent(ctx *gin.Context) {
+	restoreContent(s.dbConfig)(ctx)
+}
+
+func (s *server) RotateShareLink(ctx *gin.Context) {
+	rotateShareLink(s.dbConfig)(ctx)
+}
+
+func (s *server) GetSimilarContent(ctx *gin.Context) {
+	getSimilarContent(s.dbConfig)(ctx)
+}
+
+func (s *server) GetMediaVersions(ctx *gin.Context) {
+	getMediaVersions(s.dbConfig)(ctx)
+}
+
+func (s *server) RollbackMediaVersion(ctx *gin.Context) {
+	rollbackMediaVersion(s.dbConfig)(ctx)
+}
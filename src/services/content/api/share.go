@@ -0,0 +1,118 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+// Return the public share link of a content
+func getShareURL(shareToken uuid.UUID) string {
+	return os.Getenv("FEED_BASE_URL") + "/api/v1/shared/" + shareToken.String() + "/"
+}
+
+// Check weather or not the current user can view the given content
+//
+// Private and unreleased content is visible only to its owner, So that the owner can preview a scheduled release.
+// Public and unlisted content is visible to everyone who has the link
+func canViewContent(ctx *gin.Context, content *database.Content) bool {
+	if content.Visibility != database.ContentVisibilityPrivate && content.Released {
+		return true
+	}
+
+	user, err := getUser(ctx)
+	return err == nil && user.ID == content.UserID
+}
+
+// Fetch the given content if the current user can view it
+//
+// Content which is not visible to the user is not found, So that the APIs acting on a content by its ID
+// don't reveal the private content of others. Error response is already sent if the content is not returned.
+func getViewableContent(dbCfg *database.Config, ctx *gin.Context, contentID uuid.UUID) (*database.Content, bool) {
+	dbContent, err := database.GetContentDetailDB(dbCfg, ctx, contentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		apierror.Respond(ctx, apierror.NotFound("Content not found"))
+		return nil, false
+	} else if err != nil {
+		log.Errorln("error caught while fetching content detail: ", err)
+		apierror.Respond(ctx, err)
+		return nil, false
+	}
+
+	if !canViewContent(ctx, dbContent) {
+		apierror.Respond(ctx, apierror.NotFound("Content not found"))
+		return nil, false
+	}
+	return dbContent, true
+}
+
+// API for getting content detail using the share link
+// Non-auth API: Anyone who has the link can view the public & unlisted content
+func getSharedContent(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		shareToken, err := uuid.Parse(ctx.Param("token"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid share link"))
+			return
+		}
+
+		dbContent, err := database.GetContentByShareTokenDB(dbCfg, ctx, shareToken)
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.NotFound("Content not found"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while fetching shared content: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		sendContentDetail(dbCfg, ctx, dbContent)
+	}
+}
+
+// API for generating a new share link of the content, Old link stops working afterwards
+func rotateShareLink(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		contentID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID"))
+			return
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		shareToken, err := database.RotateShareTokenDB(dbCfg, ctx, database.RotateShareTokenParams{
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			ID:     contentID,
+			UserID: user.ID,
+		})
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.NotFound("Content not found"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while generating share link: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"data": gin.H{"share_url": getShareURL(shareToken)}})
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+const (
+	maxContentTags = 10
+	maxTagLength   = 50
+	maxTagFacets   = 20
+)
+
+// Lower case, Trim & de-duplicate the given tags
+//
+// False is returned if there are too many tags or any of them is too long
+func normalizeTags(tags []string) ([]string, bool) {
+	if tags == nil {
+		return nil, true
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool)
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, false
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > maxContentTags {
+		return nil, false
+	}
+	return normalized, true
+}
+
+// Check weather or not all the given genres are present in the curated genres
+func isValidGenres(dbCfg *database.Config, ctx *gin.Context, genres []string) (bool, error) {
+	if len(genres) == 0 {
+		return true, nil
+	}
+
+	dbGenres, err := database.GetGenresDB(dbCfg, ctx)
+	if err != nil {
+		return false, err
+	}
+
+	slugs := make(map[string]bool, len(dbGenres))
+	for _, genre := range dbGenres {
+		slugs[genre.Slug] = true
+	}
+
+	for _, genre := range genres {
+		if !slugs[genre] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Validate the genres & tags given in the request, Response is sent if they are not valid
+func parseContentLabels(dbCfg *database.Config, ctx *gin.Context, genres []string, tags []string) ([]string, []string, bool) {
+	tags, ok := normalizeTags(tags)
+	if !ok {
+		apierror.Respond(ctx, apierror.InvalidArgument("Content can have up to 10 tags of maximum 50 characters"))
+		return nil, nil, false
+	}
+
+	isValid, err := isValidGenres(dbCfg, ctx, genres)
+	if err != nil {
+		log.Errorln("error caught while fetching genres: ", err)
+		apierror.Respond(ctx, err)
+		return nil, nil, false
+	}
+
+	if !isValid {
+		apierror.Respond(ctx, apierror.InvalidArgument("Invalid genre"))
+		return nil, nil, false
+	}
+	return genres, tags, true
+}
+
+// API for getting the curated genres
+// Non-auth API: Anyone can view the genres
+func getGenres(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		dbGenres, err := database.GetGenresDB(dbCfg, ctx)
+		if err != nil {
+			log.Errorln("error caught while fetching genres: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"results": databaseGenresToGenres(dbGenres)})
+	}
+}
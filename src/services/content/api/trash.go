@@ -0,0 +1,93 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
+)
+
+// API for getting the deleted contents of current user, Most recently deleted first
+func getTrash(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		p, ok := getPage(ctx, "newest")
+		if !ok {
+			return
+		}
+
+		dbContents, err := database.GetUserTrashDB(dbCfg, ctx, database.GetUserTrashParams{
+			UserID:          user.ID,
+			CursorID:        p.cursorID(),
+			Backward:        p.backward(),
+			CursorDeletedAt: p.timeKey(),
+			Limit:           p.limit(),
+		})
+		if err != nil {
+			log.Errorln("error caught while fetching user trash: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbContents, cursors := paginate(p, dbContents, func(row database.GetUserTrashRow) (string, uuid.UUID) {
+			return timeKey(row.DeletedAt.Time), row.ID
+		})
+
+		ctx.SecureJSON(http.StatusOK, pageResponse(databaseTrashToTrashedContent(dbContents, internal.GetTrashRetention()), cursors))
+	}
+}
+
+// API for restoring a deleted content from trash
+func restoreContent(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		contentID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID"))
+			return
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbContent, err := database.RestoreContentDB(dbCfg, ctx, database.RestoreContentParams{
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			ID:     contentID,
+			UserID: user.ID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.NotFound("Content not found in trash"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while restoring content: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		content := databaseContentToContent(dbContent)
+		shareURL := getShareURL(dbContent.ShareToken)
+		content.ShareURL = &shareURL
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"data": content})
+	}
+}
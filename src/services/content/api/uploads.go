@@ -0,0 +1,335 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
+)
+
+const (
+	uploadSessionTTL = 24 * time.Hour
+
+	// Recommended size of each part, s3 requires at least 5 MB for all the parts except the last one
+	uploadPartSize = 64 << 20
+
+	maxUploadParts       = 10000
+	maxPresignedURLBatch = 100
+)
+
+// Return the active upload session of current user passed in request path, Sends the error response if it's not found or not active
+func getActiveUploadSession(dbCfg *database.Config, ctx *gin.Context) (*database.UploadSession, bool) {
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		apierror.Respond(ctx, apierror.InvalidArgument("Invalid upload ID"))
+		return nil, false
+	}
+
+	user, err := getUser(ctx)
+	if err != nil {
+		log.Errorln(err)
+		apierror.Respond(ctx, err)
+		return nil, false
+	}
+
+	session, err := database.GetUploadSessionDB(dbCfg, ctx, database.GetUploadSessionParams{
+		ID:     sessionID,
+		UserID: user.ID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		apierror.Respond(ctx, apierror.NotFound("Upload not found"))
+		return nil, false
+	} else if err != nil {
+		log.Errorln("error caught while fetching upload session: ", err)
+		apierror.Respond(ctx, err)
+		return nil, false
+	}
+
+	if session.Status != database.UploadSessionStatusActive {
+		apierror.Respond(ctx, apierror.FailedPrecondition("Upload is already "+string(session.Status)))
+		return nil, false
+	}
+
+	if !session.ExpiresAt.Time.After(time.Now().UTC()) {
+		apierror.Respond(ctx, apierror.FailedPrecondition("Upload has expired"))
+		return nil, false
+	}
+	return session, true
+}
+
+// API for starting a multipart upload of a large media file
+//
+// Parts are uploaded using the pre-signed URLs, Uploaded parts can be listed to resume the upload after a network drop.
+func initiateUpload(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		type Parameters struct {
+			ContentID   string `json:"content_id" binding:"required"`
+			FileName    string `json:"filename" binding:"required"`
+			IsAudioFile bool   `json:"is_audio_file"`
+		}
+		var params Parameters
+
+		err := ctx.ShouldBindJSON(&params)
+		if err != nil {
+			log.Errorln("error while parsing request data: ", err)
+			apierror.Respond(ctx, apierror.FromBinding(err))
+			return
+		}
+
+		contentID, err := uuid.Parse(params.ContentID)
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid content ID"))
+			return
+		}
+
+		s3Key, ok := getUniqueFilename(contentID.String(), params.FileName, params.IsAudioFile)
+		if !ok {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid filename").WithField("filename", "Unsupported file extension"))
+			return
+		}
+
+		user, err := getUser(ctx)
+		if err != nil {
+			log.Errorln(err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		// Media files can be uploaded only by the owner of the content
+		if _, ok := getOwnedContent(dbCfg, ctx, contentID, user.ID); !ok {
+			return
+		}
+
+		uploadID, err := internal.CreateMultipartUpload(ctx, s3Key)
+		if err != nil {
+			log.Errorln("error caught while creating multipart upload: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		now := time.Now().UTC()
+		session, err := database.CreateUploadSessionDB(dbCfg, ctx, database.CreateUploadSessionParams{
+			ID: uuid.New(),
+			CreatedAt: pgtype.Timestamp{
+				Time:  now,
+				Valid: true,
+			},
+			ModifiedAt: pgtype.Timestamp{
+				Time:  now,
+				Valid: true,
+			},
+			ExpiresAt: pgtype.Timestamp{
+				Time:  now.Add(uploadSessionTTL),
+				Valid: true,
+			},
+			UserID:      user.ID,
+			ContentID:   contentID,
+			S3Key:       s3Key,
+			S3UploadID:  uploadID,
+			IsAudioFile: params.IsAudioFile,
+		})
+		if err != nil {
+			log.Errorln("error caught while adding upload session to DB: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusCreated, gin.H{"data": databaseUploadSessionToUploadSession(session)})
+	}
+}
+
+// API for getting the pre-signed URLs for uploading the given parts
+func getUploadPartURLs(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		type Parameters struct {
+			PartNumbers []int32 `json:"part_numbers" binding:"required,min=1"`
+		}
+		var params Parameters
+
+		err := ctx.ShouldBindJSON(&params)
+		if err != nil {
+			log.Errorln("error while parsing request data: ", err)
+			apierror.Respond(ctx, apierror.FromBinding(err))
+			return
+		}
+
+		if len(params.PartNumbers) > maxPresignedURLBatch {
+			apierror.Respond(ctx, apierror.InvalidArgument("Too many parts requested"))
+			return
+		}
+
+		for _, partNumber := range params.PartNumbers {
+			if partNumber < 1 || partNumber > maxUploadParts {
+				apierror.Respond(ctx, apierror.InvalidArgument("Invalid part number"))
+				return
+			}
+		}
+
+		session, ok := getActiveUploadSession(dbCfg, ctx)
+		if !ok {
+			return
+		}
+
+		urls, err := internal.PresignUploadParts(ctx, session, params.PartNumbers)
+		if err != nil {
+			log.Errorln("error caught while generating pre-signed part URLs: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		parts := make([]UploadPartURL, 0, len(urls))
+		for _, partNumber := range params.PartNumbers {
+			parts = append(parts, UploadPartURL{
+				PartNumber: partNumber,
+				Url:        urls[partNumber],
+			})
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"results": parts})
+	}
+}
+
+// API for listing the parts which are already uploaded, Used for resuming an upload
+func getUploadedParts(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		session, ok := getActiveUploadSession(dbCfg, ctx)
+		if !ok {
+			return
+		}
+
+		parts, err := internal.ListUploadedParts(ctx, session)
+		if err != nil {
+			log.Errorln("error caught while listing uploaded parts: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"results": parts})
+	}
+}
+
+// API for completing a multipart upload
+//
+// All the uploaded parts are used if no parts are given. Once completed the key is passed to PUT /:id/ like a single file upload.
+func completeUpload(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		type Part struct {
+			PartNumber int32  `json:"part_number" binding:"required"`
+			ETag       string `json:"etag" binding:"required"`
+		}
+		type Parameters struct {
+			Parts []Part `json:"parts" binding:"dive"`
+		}
+		var params Parameters
+
+		// Request body is optional
+		if ctx.Request.ContentLength != 0 {
+			if err := ctx.ShouldBindJSON(&params); err != nil {
+				log.Errorln("error while parsing request data: ", err)
+				apierror.Respond(ctx, apierror.FromBinding(err))
+				return
+			}
+		}
+
+		session, ok := getActiveUploadSession(dbCfg, ctx)
+		if !ok {
+			return
+		}
+
+		uploadedParts, err := internal.ListUploadedParts(ctx, session)
+		if err != nil {
+			log.Errorln("error caught while listing uploaded parts: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		parts := uploadedParts
+		if len(params.Parts) != 0 {
+			parts = make([]internal.UploadedPart, 0, len(params.Parts))
+
+			// Given parts should match the uploaded ones
+			for _, part := range params.Parts {
+				idx := slices.IndexFunc(uploadedParts, func(uploadedPart internal.UploadedPart) bool {
+					return uploadedPart.PartNumber == part.PartNumber && uploadedPart.ETag == part.ETag
+				})
+				if idx == -1 {
+					apierror.Respond(ctx, apierror.InvalidArgument("Invalid part").WithField("parts", fmt.Sprintf("Part %d is not uploaded", part.PartNumber)))
+					return
+				}
+				parts = append(parts, uploadedParts[idx])
+			}
+
+			slices.SortFunc(parts, func(a, b internal.UploadedPart) int {
+				return int(a.PartNumber - b.PartNumber)
+			})
+		}
+
+		if len(parts) == 0 {
+			apierror.Respond(ctx, apierror.InvalidArgument("No parts uploaded"))
+			return
+		}
+
+		if err := internal.CompleteMultipartUpload(ctx, session, parts); err != nil {
+			log.Errorln("error caught while completing multipart upload: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		session.Status = database.UploadSessionStatusCompleted
+		if err := database.UpdateUploadSessionStatusDB(dbCfg, ctx, database.UpdateUploadSessionStatusParams{
+			Status: session.Status,
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			ID: session.ID,
+		}); err != nil {
+			log.Errorln("error caught while updating upload session status: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"data": databaseUploadSessionToUploadSession(session)})
+	}
+}
+
+// API for aborting a multipart upload, Uploaded parts are removed from s3
+func abortUpload(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		session, ok := getActiveUploadSession(dbCfg, ctx)
+		if !ok {
+			return
+		}
+
+		if err := internal.AbortMultipartUpload(ctx, session); err != nil {
+			log.Errorln("error caught while aborting multipart upload: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if err := database.UpdateUploadSessionStatusDB(dbCfg, ctx, database.UpdateUploadSessionStatusParams{
+			Status: database.UploadSessionStatusAborted,
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			ID: session.ID,
+		}); err != nil {
+			log.Errorln("error caught while updating upload session status: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"message": "Upload aborted successfully"})
+	}
+}
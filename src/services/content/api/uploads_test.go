@@ -0,0 +1,199 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+func TestGetUniqueFilename(t *testing.T) {
+	contentID := uuid.NewString()
+
+	tests := []struct {
+		filename    string
+		isAudioFile bool
+		prefix      string
+		ext         string
+	}{
+		{filename: "song.mp3", isAudioFile: true, prefix: "audio/", ext: ".mp3"},
+		{filename: "my.song.FLAC", isAudioFile: true, prefix: "audio/", ext: ".flac"},
+		{filename: "video.mp4", prefix: "video/", ext: ".mp4"},
+		{filename: "song"},
+		{filename: "song.", isAudioFile: true},
+		{filename: "video.mp4", isAudioFile: true},
+		{filename: "song.mp3"},
+		{filename: "archive.tar.gz", isAudioFile: true},
+	}
+
+	for _, tc := range tests {
+		key, ok := getUniqueFilename(contentID, tc.filename, tc.isAudioFile)
+		if ok != (tc.prefix != "") {
+			t.Errorf("%s: expected valid %v, got %v", tc.filename, tc.prefix != "", ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		if !strings.HasPrefix(key, tc.prefix+contentID+"_") || !strings.HasSuffix(key, tc.ext) {
+			t.Errorf("%s: unexpected key %s", tc.filename, key)
+		}
+		if !isContentMediaKey(uuid.MustParse(contentID), key) {
+			t.Errorf("%s: key %s is not parsed back to the content", tc.filename, key)
+		}
+	}
+}
+
+func TestPresignedURLOwnership(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_BUCKET_NAME", "media")
+
+	f := newContentFixture(t)
+	other := uuid.New()
+
+	var issued []database.MediaUpload
+	f.db.on("CreateMediaUpload", func(args []any) ([][]any, error) {
+		issued = append(issued, database.MediaUpload{
+			S3Key:       args[0].(string),
+			UserID:      args[2].(uuid.UUID),
+			ContentID:   args[3].(uuid.UUID),
+			IsAudioFile: args[4].(bool),
+		})
+		return nil, nil
+	})
+
+	tests := []struct {
+		name      string
+		userID    uuid.UUID
+		contentID string
+		filename  string
+		status    int
+	}{
+		{name: "owner public", userID: f.owner, contentID: f.contents[database.ContentVisibilityPublic].ID.String(), filename: "song.mp3", status: http.StatusOK},
+		{name: "owner private", userID: f.owner, contentID: f.contents[database.ContentVisibilityPrivate].ID.String(), filename: "song.mp3", status: http.StatusOK},
+		{name: "non-owner public", userID: other, contentID: f.contents[database.ContentVisibilityPublic].ID.String(), filename: "song.mp3", status: http.StatusForbidden},
+		{name: "non-owner private", userID: other, contentID: f.contents[database.ContentVisibilityPrivate].ID.String(), filename: "song.mp3", status: http.StatusNotFound},
+		{name: "missing", userID: f.owner, contentID: uuid.NewString(), filename: "song.mp3", status: http.StatusNotFound},
+		{name: "malformed", userID: f.owner, contentID: "not-a-uuid", filename: "song.mp3", status: http.StatusBadRequest},
+		{name: "without extension", userID: f.owner, contentID: f.contents[database.ContentVisibilityPublic].ID.String(), filename: "song", status: http.StatusBadRequest},
+		{name: "unsupported extension", userID: f.owner, contentID: f.contents[database.ContentVisibilityPublic].ID.String(), filename: "song.exe", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			issued = nil
+			body := `{"content_id":"` + tc.contentID + `","filename":"` + tc.filename + `","is_audio_file":true}`
+
+			rec := f.request(t, http.MethodPost, "/api/v1/upload-url/", body, &tc.userID)
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+
+			if tc.status != http.StatusOK {
+				if len(issued) != 0 {
+					t.Errorf("key was issued: %v", issued)
+				}
+				return
+			}
+
+			var res struct {
+				Data struct {
+					Key string `json:"key"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+
+			// Issued key is recorded for the owner, So that its storage notification can be ingested
+			if len(issued) != 1 || issued[0].S3Key != res.Data.Key || issued[0].UserID != f.owner ||
+				issued[0].ContentID.String() != tc.contentID || !issued[0].IsAudioFile {
+				t.Errorf("issued key is not recorded for the owner: %+v, key: %s", issued, res.Data.Key)
+			}
+		})
+	}
+}
+
+func TestIngestIssuedUploads(t *testing.T) {
+	t.Setenv("AWS_BUCKET_NAME", "media")
+	t.Setenv("STORAGE_WEBHOOK_SECRET", "secret")
+
+	f := newContentFixture(t)
+	content := f.contents[database.ContentVisibilityPublic]
+
+	issuedKey := "audio/" + content.ID.String() + "_issued.mp3"
+	f.db.on("GetIssuedUpload", func(args []any) ([][]any, error) {
+		if args[0] != issuedKey {
+			return nil, nil
+		}
+		return [][]any{rowOf(database.GetIssuedUploadRow{
+			ContentID:   content.ID,
+			UserID:      f.owner,
+			IsAudioFile: true,
+		})}, nil
+	})
+
+	// Media version of the issued key is reported as already existing, So that the conversion is not started
+	var added []database.AddMediaVersionParams
+	f.db.on("AddMediaVersion", func(args []any) ([][]any, error) {
+		added = append(added, database.AddMediaVersionParams{
+			SourceKey: args[2].(string),
+			ContentID: args[3].(uuid.UUID),
+			UserID:    args[4].(pgtype.UUID),
+		})
+		return nil, nil
+	})
+	f.db.on("GetMediaVersionBySourceKey", func(args []any) ([][]any, error) {
+		return [][]any{rowOf(database.ContentMediaVersion{
+			ID:        uuid.New(),
+			ContentID: args[0].(uuid.UUID),
+			SourceKey: args[1].(string),
+			Status:    database.MediaVersionStatusProcessing,
+		})}, nil
+	})
+
+	keys := []string{
+		issuedKey,
+		// Key with the content ID of another user's content which was never issued
+		"audio/" + content.ID.String() + "_forged.mp3",
+		"video/" + content.ID.String() + ".m3u8",
+	}
+
+	records := make([]map[string]any, 0, len(keys))
+	for _, key := range keys {
+		records = append(records, map[string]any{
+			"eventName": "ObjectCreated:Put",
+			"s3": map[string]any{
+				"bucket": map[string]string{"name": "media"},
+				"object": map[string]string{"key": key},
+			},
+		})
+	}
+	body, err := json.Marshal(map[string]any{"Records": records})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/storage/events/", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := serve(t, f.engine, req, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	if len(added) != 1 {
+		t.Fatalf("expected a media version only for the issued key, got %+v", added)
+	}
+	if added[0].SourceKey != issuedKey || added[0].ContentID != content.ID || !added[0].UserID.Valid || added[0].UserID.Bytes != f.owner {
+		t.Errorf("media version is not added for the owner: %+v", added[0])
+	}
+}
@@ -1,12 +1,87 @@
 package api
 
-import "strings"
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
-func getUniqueFilename(contentID, srcFilename string, isAudioFile bool) string {
-	filename := contentID + "." + strings.Split(srcFilename, ".")[1]
+	"github.com/google/uuid"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
+)
+
+// Extensions of the media files which can be uploaded for conversion
+var mediaFileExtensions = map[bool][]string{
+	true:  {".mp3", ".m4a", ".aac", ".wav", ".flac", ".ogg"},
+	false: {".mp4", ".mov", ".mkv", ".webm"},
+}
+
+// Return the s3 key for an uploaded media file of the content,
+// Every upload gets a new key so the current media version is not overwritten.
+//
+// Returns false if the file doesn't have one of the supported extensions of its media type.
+func getUniqueFilename(contentID, srcFilename string, isAudioFile bool) (string, bool) {
+	ext := strings.ToLower(path.Ext(srcFilename))
+	if !slices.Contains(mediaFileExtensions[isAudioFile], ext) {
+		return "", false
+	}
+
+	suffix := strconv.FormatInt(time.Now().UnixMilli(), 36)
+	filename := contentID + "_" + suffix + ext
 
 	if isAudioFile {
-		return "audio/" + filename
+		return "audio/" + filename, true
+	}
+	return "video/" + filename, true
+}
+
+// Check weather or not the given s3 key is of a media file uploaded for the content
+func isContentMediaKey(contentID uuid.UUID, key string) bool {
+	keyContentID, _, ok := internal.ParseMediaKey(key)
+	return ok && keyContentID == contentID
+}
+
+var (
+	upcRegex  = regexp.MustCompile(`^\d{12,13}$`)
+	isrcRegex = regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}\d{7}$`)
+)
+
+// Check weather or not the given value is a valid UPC-A or EAN-13 code
+func isValidUPC(value string) bool {
+	return upcRegex.MatchString(value)
+}
+
+// Check weather or not the given value is a valid ISRC code, Without hyphens
+func isValidISRC(value string) bool {
+	return isrcRegex.MatchString(value)
+}
+
+// Check weather or not the given value is a valid album type
+func isValidAlbumType(value string) bool {
+	switch database.AlbumType(value) {
+	case database.AlbumTypeA, database.AlbumTypeS, database.AlbumTypeE:
+		return true
+	}
+	return false
+}
+
+// Check weather or not the given value is a valid play event type
+func isValidPlayEventType(value string) bool {
+	switch database.PlayEventType(value) {
+	case database.PlayEventTypeS, database.PlayEventTypeP, database.PlayEventTypeE:
+		return true
+	}
+	return false
+}
+
+// Check weather or not the given value is a valid content visibility
+func isValidVisibility(value string) bool {
+	switch database.ContentVisibility(value) {
+	case database.ContentVisibilityPublic, database.ContentVisibilityUnlisted, database.ContentVisibilityPrivate:
+		return true
 	}
-	return "video/" + filename
+	return false
 }
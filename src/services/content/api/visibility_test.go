@@ -0,0 +1,291 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apidocs/apitest"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
+)
+
+var visibilities = []database.ContentVisibility{
+	database.ContentVisibilityPublic,
+	database.ContentVisibilityUnlisted,
+	database.ContentVisibilityPrivate,
+}
+
+// Contents of the same owner with each visibility, Served by the fake DB behind the API routes
+type contentFixture struct {
+	owner    uuid.UUID
+	contents map[database.ContentVisibility]database.Content
+	db       *fakeDB
+	attempts *atomic.Int32
+	engine   *gin.Engine
+}
+
+func (f *contentFixture) find(match func(content database.Content) bool) [][]any {
+	for _, content := range f.contents {
+		if match(content) {
+			return [][]any{rowOf(content)}
+		}
+	}
+	return nil
+}
+
+// Mirror of the visibility check done by the queries which filter the contents for the user
+func (f *contentFixture) canView(content database.Content, userID uuid.UUID) bool {
+	return (content.Visibility != database.ContentVisibilityPrivate && content.Released) || content.UserID == userID
+}
+
+func newContentFixture(t *testing.T) *contentFixture {
+	f := &contentFixture{
+		owner:    uuid.New(),
+		contents: make(map[database.ContentVisibility]database.Content),
+		db:       newFakeDB(),
+	}
+
+	createdAt := pgtype.Timestamp{Time: time.Now().UTC().Add(-time.Hour), Valid: true}
+	for _, visibility := range visibilities {
+		f.contents[visibility] = database.Content{
+			ID:          uuid.New(),
+			CreatedAt:   createdAt,
+			ModifiedAt:  createdAt,
+			UserID:      f.owner,
+			Title:       string(visibility) + " content",
+			Description: "Description",
+			Type:        database.ContentTypeM,
+			Visibility:  visibility,
+			ShareToken:  uuid.New(),
+			Released:    true,
+		}
+	}
+
+	f.db.on("GetContentById", func(args []any) ([][]any, error) {
+		return f.find(func(content database.Content) bool { return content.ID == args[0] }), nil
+	})
+	f.db.on("GetContentByShareToken", func(args []any) ([][]any, error) {
+		return f.find(func(content database.Content) bool { return content.ShareToken == args[0] }), nil
+	})
+	f.db.on("GetContentList", func(args []any) ([][]any, error) {
+		var rows [][]any
+		for _, content := range f.contents {
+			if content.Visibility == database.ContentVisibilityPublic && content.Released {
+				rows = append(rows, rowOf(database.GetContentListRow{
+					ID:          content.ID,
+					CreatedAt:   content.CreatedAt,
+					UserID:      content.UserID,
+					Title:       content.Title,
+					Description: content.Description,
+					Type:        content.Type,
+				}))
+			}
+		}
+		return rows, nil
+	})
+	f.db.on("GetViewableContentIDs", func(args []any) ([][]any, error) {
+		var rows [][]any
+		for _, content := range f.contents {
+			if slices.Contains(args[0].([]uuid.UUID), content.ID) && f.canView(content, args[1].(uuid.UUID)) {
+				rows = append(rows, []any{content.ID})
+			}
+		}
+		return rows, nil
+	})
+	f.db.on("GetPlaybackPosition", func(args []any) ([][]any, error) {
+		return [][]any{rowOf(database.PlaybackPosition{
+			UserID:          args[0].(uuid.UUID),
+			ContentID:       args[1].(uuid.UUID),
+			Position:        10,
+			ClientUpdatedAt: pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+			ModifiedAt:      pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+		})}, nil
+	})
+	f.db.on("GetContentLikeCount", func(args []any) ([][]any, error) {
+		return [][]any{{int64(0)}}, nil
+	})
+	for _, name := range []string{"GetGenreFacets", "GetTagFacets", "GetContentArtists", "GetContentGenres", "GetContentTags", "GetSimilarContent"} {
+		f.db.on(name, func(args []any) ([][]any, error) {
+			return nil, nil
+		})
+	}
+
+	// Creator details are cached, So that the user service is not called
+	creator, err := internal.CreatorToByte(internal.Creator{ID: f.owner, Name: "Owner"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testRedis.Set("creator:"+f.owner.String(), string(creator))
+
+	pool, attempts := newTestPool(t)
+	f.attempts = attempts
+
+	// Requests & responses of the API tests are validated against the OpenAPI spec
+	f.engine = gin.New()
+	f.engine.Use(apitest.LoadSpec(t, openAPISpec).Validator(t))
+	Routes(f.engine, &database.Config{DB: pool, Queries: database.New(f.db)})
+
+	return f
+}
+
+// Send a request to the API routes as the given user, Anonymous if it's nil
+func (f *contentFixture) request(t *testing.T, method, path, body string, userID *uuid.UUID) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return serve(t, f.engine, req, userID)
+}
+
+func TestContentVisibility(t *testing.T) {
+	f := newContentFixture(t)
+	other := uuid.New()
+
+	viewers := []struct {
+		name   string
+		userID *uuid.UUID
+	}{
+		{name: "owner", userID: &f.owner},
+		{name: "anonymous"},
+		{name: "other", userID: &other},
+	}
+
+	for _, viewer := range viewers {
+		for _, visibility := range visibilities {
+			content := f.contents[visibility]
+			visible := visibility != database.ContentVisibilityPrivate || viewer.name == "owner"
+
+			t.Run(viewer.name+"/"+string(visibility), func(t *testing.T) {
+				rec := f.request(t, http.MethodGet, "/api/v1/list/", "", viewer.userID)
+				if rec.Code != http.StatusOK {
+					t.Fatalf("list: expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+				}
+
+				var list struct {
+					Results []struct {
+						ID uuid.UUID `json:"id"`
+					} `json:"results"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+					t.Fatal(err)
+				}
+
+				listed := slices.ContainsFunc(list.Results, func(result struct {
+					ID uuid.UUID `json:"id"`
+				}) bool {
+					return result.ID == content.ID
+				})
+				if listed != (visibility == database.ContentVisibilityPublic) {
+					t.Errorf("list: content listed: %v", listed)
+				}
+
+				status := http.StatusNotFound
+				if visible {
+					status = http.StatusOK
+				}
+
+				paths := []string{
+					"/api/v1/" + content.ID.String() + "/",
+					"/api/v1/shared/" + content.ShareToken.String() + "/",
+					"/api/v1/" + content.ID.String() + "/similar/",
+				}
+				for _, path := range paths {
+					if rec := f.request(t, http.MethodGet, path, "", viewer.userID); rec.Code != status {
+						t.Errorf("%s: expected status %d, got %d: %s", path, status, rec.Code, rec.Body.String())
+					}
+				}
+			})
+		}
+	}
+
+	if sql := f.db.sql("GetContentList"); !strings.Contains(sql, "content.visibility='public' AND content.released") {
+		t.Errorf("content list is not filtered by visibility: %s", sql)
+	}
+}
+
+func TestContentVisibilityByID(t *testing.T) {
+	f := newContentFixture(t)
+	other := uuid.New()
+
+	playedAt := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+
+	for _, visibility := range visibilities {
+		content := f.contents[visibility]
+		path := "/api/v1/" + content.ID.String()
+
+		requests := []struct {
+			name   string
+			method string
+			path   string
+			body   string
+
+			// Status of the request which passed the visibility check, Transactions can't be started in the tests
+			status int
+		}{
+			{name: "like", method: http.MethodPut, path: path + "/like/", status: http.StatusInternalServerError},
+			{name: "get position", method: http.MethodGet, path: path + "/position/", status: http.StatusOK},
+			{
+				name:   "update position",
+				method: http.MethodPut,
+				path:   path + "/position/",
+				body:   `{"position":10,"duration":100,"updated_at":"` + playedAt + `"}`,
+				status: http.StatusInternalServerError,
+			},
+			{
+				name:   "plays",
+				method: http.MethodPost,
+				path:   "/api/v1/plays/",
+				body:   `{"events":[{"content_id":"` + content.ID.String() + `","type":"S","client":"web","played_at":"` + playedAt + `"}]}`,
+				status: http.StatusAccepted,
+			},
+		}
+
+		for _, req := range requests {
+			t.Run(req.name+"/"+string(visibility), func(t *testing.T) {
+				for _, userID := range []uuid.UUID{f.owner, other} {
+					visible := visibility != database.ContentVisibilityPrivate || userID == f.owner
+
+					attempts := f.attempts.Load()
+					rec := f.request(t, req.method, req.path, req.body, &userID)
+
+					if !visible {
+						if rec.Code != http.StatusNotFound {
+							t.Errorf("other user: expected status %d, got %d: %s", http.StatusNotFound, rec.Code, rec.Body.String())
+						}
+						if f.attempts.Load() != attempts {
+							t.Errorf("other user: DB transaction was started")
+						}
+						continue
+					}
+
+					if rec.Code != req.status {
+						t.Errorf("user %s: expected status %d, got %d: %s", userID, req.status, rec.Code, rec.Body.String())
+					}
+					if req.status == http.StatusInternalServerError && f.attempts.Load() == attempts {
+						t.Errorf("user %s: DB transaction was not started", userID)
+					}
+				}
+			})
+		}
+
+		t.Run("anonymous/"+string(visibility), func(t *testing.T) {
+			for _, req := range requests {
+				if rec := f.request(t, req.method, req.path, req.body, nil); rec.Code != http.StatusUnauthorized {
+					t.Errorf("%s: expected status %d, got %d", req.name, http.StatusUnauthorized, rec.Code)
+				}
+			}
+		})
+	}
+
+	// Play events of the visible contents are buffered in redis
+	if events, err := testRedis.List("play_events"); err != nil || len(events) == 0 {
+		t.Errorf("play events are not buffered: %v", err)
+	}
+}
@@ -13,9 +13,9 @@ import (
 )
 
 const addContent = `-- name: AddContent :one
-INSERT INTO content (id, created_at, modified_at, user_id, title, description, type) 
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, created_at, modified_at, user_id, title, description, type, s3_key
+INSERT INTO content (id, created_at, modified_at, user_id, title, description, type, visibility, publish_at, unpublish_at, released) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, created_at, modified_at, user_id, title, description, type, s3_key, album_id, disc_number, track_number, isrc, show_id, season_number, episode_number, published_at, show_notes, guid, download_key, download_size, duration, chapters_url, transcript_url, visibility, share_token, publish_at, unpublish_at, released, deleted_at, media_version_id
 `
 
 type AddContentParams struct {
@@ -26,6 +26,10 @@ type AddContentParams struct {
 	Title       string
 	Description string
 	Type        ContentType
+	Visibility  ContentVisibility
+	PublishAt   pgtype.Timestamp
+	UnpublishAt pgtype.Timestamp
+	Released    bool
 }
 
 func (q *Queries) AddContent(ctx context.Context, arg AddContentParams) (Content, error) {
@@ -37,6 +41,10 @@ func (q *Queries) AddContent(ctx context.Context, arg AddContentParams) (Content
 		arg.Title,
 		arg.Description,
 		arg.Type,
+		arg.Visibility,
+		arg.PublishAt,
+		arg.UnpublishAt,
+		arg.Released,
 	)
 	var i Content
 	err := row.Scan(
@@ -48,26 +56,146 @@ func (q *Queries) AddContent(ctx context.Context, arg AddContentParams) (Content
 		&i.Description,
 		&i.Type,
 		&i.S3Key,
+		&i.AlbumID,
+		&i.DiscNumber,
+		&i.TrackNumber,
+		&i.Isrc,
+		&i.ShowID,
+		&i.SeasonNumber,
+		&i.EpisodeNumber,
+		&i.PublishedAt,
+		&i.ShowNotes,
+		&i.Guid,
+		&i.DownloadKey,
+		&i.DownloadSize,
+		&i.Duration,
+		&i.ChaptersUrl,
+		&i.TranscriptUrl,
+		&i.Visibility,
+		&i.ShareToken,
+		&i.PublishAt,
+		&i.UnpublishAt,
+		&i.Released,
+		&i.DeletedAt,
+		&i.MediaVersionID,
 	)
 	return i, err
 }
 
 const deleteContent = `-- name: DeleteContent :exec
-DELETE FROM content WHERE id=$1 AND user_id=$2
+UPDATE content SET deleted_at=$1, modified_at=$1
+WHERE id=$2 AND user_id=$3 AND deleted_at IS NULL
 `
 
 type DeleteContentParams struct {
-	ID     uuid.UUID
-	UserID uuid.UUID
+	DeletedAt pgtype.Timestamp
+	ID        uuid.UUID
+	UserID    uuid.UUID
 }
 
 func (q *Queries) DeleteContent(ctx context.Context, arg DeleteContentParams) error {
-	_, err := q.db.Exec(ctx, deleteContent, arg.ID, arg.UserID)
+	_, err := q.db.Exec(ctx, deleteContent, arg.DeletedAt, arg.ID, arg.UserID)
 	return err
 }
 
+const deleteUserContent = `-- name: DeleteUserContent :many
+WITH deleted AS (
+    DELETE FROM content WHERE user_id=$1
+    RETURNING id, s3_key, download_key
+)
+SELECT deleted.id, deleted.s3_key AS key FROM deleted
+UNION
+SELECT deleted.id, deleted.download_key FROM deleted
+UNION
+SELECT deleted.id, COALESCE(content_media_versions.s3_key, content_media_versions.source_key) FROM content_media_versions
+JOIN deleted ON deleted.id=content_media_versions.content_id
+UNION
+SELECT deleted.id, content_media_versions.download_key FROM content_media_versions
+JOIN deleted ON deleted.id=content_media_versions.content_id
+`
+
+type DeleteUserContentRow struct {
+	ID  uuid.UUID
+	Key pgtype.Text
+}
+
+func (q *Queries) DeleteUserContent(ctx context.Context, userID uuid.UUID) ([]DeleteUserContentRow, error) {
+	rows, err := q.db.Query(ctx, deleteUserContent, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DeleteUserContentRow
+	for rows.Next() {
+		var i DeleteUserContentRow
+		if err := rows.Scan(&i.ID, &i.Key); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllUserContent = `-- name: GetAllUserContent :many
+SELECT id, created_at, modified_at, user_id, title, description, type, s3_key, album_id, disc_number, track_number, isrc, show_id, season_number, episode_number, published_at, show_notes, guid, download_key, download_size, duration, chapters_url, transcript_url, visibility, share_token, publish_at, unpublish_at, released, deleted_at, media_version_id FROM content WHERE user_id=$1 ORDER BY created_at
+`
+
+func (q *Queries) GetAllUserContent(ctx context.Context, userID uuid.UUID) ([]Content, error) {
+	rows, err := q.db.Query(ctx, getAllUserContent, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Content
+	for rows.Next() {
+		var i Content
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.ModifiedAt,
+			&i.UserID,
+			&i.Title,
+			&i.Description,
+			&i.Type,
+			&i.S3Key,
+			&i.AlbumID,
+			&i.DiscNumber,
+			&i.TrackNumber,
+			&i.Isrc,
+			&i.ShowID,
+			&i.SeasonNumber,
+			&i.EpisodeNumber,
+			&i.PublishedAt,
+			&i.ShowNotes,
+			&i.Guid,
+			&i.DownloadKey,
+			&i.DownloadSize,
+			&i.Duration,
+			&i.ChaptersUrl,
+			&i.TranscriptUrl,
+			&i.Visibility,
+			&i.ShareToken,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Released,
+			&i.DeletedAt,
+			&i.MediaVersionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getContentById = `-- name: GetContentById :one
-SELECT id, created_at, modified_at, user_id, title, description, type, s3_key FROM content WHERE id=$1 FOR UPDATE NOWAIT
+SELECT id, created_at, modified_at, user_id, title, description, type, s3_key, album_id, disc_number, track_number, isrc, show_id, season_number, episode_number, published_at, show_notes, guid, download_key, download_size, duration, chapters_url, transcript_url, visibility, share_token, publish_at, unpublish_at, released, deleted_at, media_version_id FROM content WHERE id=$1 AND deleted_at IS NULL FOR UPDATE NOWAIT
 `
 
 func (q *Queries) GetContentById(ctx context.Context, id uuid.UUID) (Content, error) {
@@ -82,29 +210,145 @@ func (q *Queries) GetContentById(ctx context.Context, id uuid.UUID) (Content, er
 		&i.Description,
 		&i.Type,
 		&i.S3Key,
+		&i.AlbumID,
+		&i.DiscNumber,
+		&i.TrackNumber,
+		&i.Isrc,
+		&i.ShowID,
+		&i.SeasonNumber,
+		&i.EpisodeNumber,
+		&i.PublishedAt,
+		&i.ShowNotes,
+		&i.Guid,
+		&i.DownloadKey,
+		&i.DownloadSize,
+		&i.Duration,
+		&i.ChaptersUrl,
+		&i.TranscriptUrl,
+		&i.Visibility,
+		&i.ShareToken,
+		&i.PublishAt,
+		&i.UnpublishAt,
+		&i.Released,
+		&i.DeletedAt,
+		&i.MediaVersionID,
+	)
+	return i, err
+}
+
+const getContentByShareToken = `-- name: GetContentByShareToken :one
+SELECT id, created_at, modified_at, user_id, title, description, type, s3_key, album_id, disc_number, track_number, isrc, show_id, season_number, episode_number, published_at, show_notes, guid, download_key, download_size, duration, chapters_url, transcript_url, visibility, share_token, publish_at, unpublish_at, released, deleted_at, media_version_id FROM content WHERE share_token=$1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetContentByShareToken(ctx context.Context, shareToken uuid.UUID) (Content, error) {
+	row := q.db.QueryRow(ctx, getContentByShareToken, shareToken)
+	var i Content
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.Type,
+		&i.S3Key,
+		&i.AlbumID,
+		&i.DiscNumber,
+		&i.TrackNumber,
+		&i.Isrc,
+		&i.ShowID,
+		&i.SeasonNumber,
+		&i.EpisodeNumber,
+		&i.PublishedAt,
+		&i.ShowNotes,
+		&i.Guid,
+		&i.DownloadKey,
+		&i.DownloadSize,
+		&i.Duration,
+		&i.ChaptersUrl,
+		&i.TranscriptUrl,
+		&i.Visibility,
+		&i.ShareToken,
+		&i.PublishAt,
+		&i.UnpublishAt,
+		&i.Released,
+		&i.DeletedAt,
+		&i.MediaVersionID,
 	)
 	return i, err
 }
 
 const getContentList = `-- name: GetContentList :many
-SELECT id, created_at, title, description, type FROM content ORDER BY created_at DESC LIMIT $1 OFFSET $2
+SELECT content.id, content.created_at, content.user_id, content.title, content.description, content.type,
+    COALESCE(content_play_counts.total_plays, 0)::BIGINT AS plays
+FROM content
+LEFT JOIN content_play_counts ON content_play_counts.content_id=content.id
+WHERE content.visibility='public' AND content.released AND content.deleted_at IS NULL
+AND (cardinality($1::TEXT[]) = 0 OR content.id IN (
+    SELECT content_id FROM content_genres WHERE genre_slug = ANY($1::TEXT[])
+))
+AND (cardinality($2::TEXT[]) = 0 OR content.id IN (
+    SELECT content_id FROM content_tags WHERE tag = ANY($2::TEXT[])
+))
+AND ($3::UUID IS NULL OR CASE
+    WHEN $4::TEXT = 'title' AND $5::BOOLEAN
+        THEN (content.title, content.id) < ($6::TEXT, $3::UUID)
+    WHEN $4::TEXT = 'title'
+        THEN (content.title, content.id) > ($6::TEXT, $3::UUID)
+    WHEN $4::TEXT = 'popularity' AND $5::BOOLEAN
+        THEN (COALESCE(content_play_counts.total_plays, 0), content.id) > ($7::BIGINT, $3::UUID)
+    WHEN $4::TEXT = 'popularity'
+        THEN (COALESCE(content_play_counts.total_plays, 0), content.id) < ($7::BIGINT, $3::UUID)
+    WHEN $5::BOOLEAN
+        THEN (content.created_at, content.id) > ($8::TIMESTAMP, $3::UUID)
+    ELSE (content.created_at, content.id) < ($8::TIMESTAMP, $3::UUID)
+END)
+ORDER BY
+    CASE WHEN $4::TEXT = 'title' AND NOT $5::BOOLEAN THEN content.title END ASC,
+    CASE WHEN $4::TEXT = 'title' AND $5::BOOLEAN THEN content.title END DESC,
+    CASE WHEN $4::TEXT = 'popularity' AND NOT $5::BOOLEAN THEN COALESCE(content_play_counts.total_plays, 0) END DESC,
+    CASE WHEN $4::TEXT = 'popularity' AND $5::BOOLEAN THEN COALESCE(content_play_counts.total_plays, 0) END ASC,
+    CASE WHEN $4::TEXT = 'newest' AND NOT $5::BOOLEAN THEN content.created_at END DESC,
+    CASE WHEN $4::TEXT = 'newest' AND $5::BOOLEAN THEN content.created_at END ASC,
+    CASE WHEN ($4::TEXT = 'title') <> $5::BOOLEAN THEN content.id END ASC,
+    content.id DESC
+LIMIT $9
 `
 
 type GetContentListParams struct {
-	Limit  int32
-	Offset int32
+	Genres          []string
+	Tags            []string
+	CursorID        pgtype.UUID
+	Sort            string
+	Backward        bool
+	CursorTitle     pgtype.Text
+	CursorPlays     pgtype.Int8
+	CursorCreatedAt pgtype.Timestamp
+	Limit           int32
 }
 
 type GetContentListRow struct {
 	ID          uuid.UUID
 	CreatedAt   pgtype.Timestamp
+	UserID      uuid.UUID
 	Title       string
 	Description string
 	Type        ContentType
+	Plays       int64
 }
 
 func (q *Queries) GetContentList(ctx context.Context, arg GetContentListParams) ([]GetContentListRow, error) {
-	rows, err := q.db.Query(ctx, getContentList, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, getContentList,
+		arg.Genres,
+		arg.Tags,
+		arg.CursorID,
+		arg.Sort,
+		arg.Backward,
+		arg.CursorTitle,
+		arg.CursorPlays,
+		arg.CursorCreatedAt,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -115,9 +359,11 @@ func (q *Queries) GetContentList(ctx context.Context, arg GetContentListParams)
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
+			&i.UserID,
 			&i.Title,
 			&i.Description,
 			&i.Type,
+			&i.Plays,
 		); err != nil {
 			return nil, err
 		}
@@ -129,26 +375,102 @@ func (q *Queries) GetContentList(ctx context.Context, arg GetContentListParams)
 	return items, nil
 }
 
+const getMediaKeys = `-- name: GetMediaKeys :many
+SELECT s3_key, download_key FROM content
+WHERE s3_key IS NOT NULL OR download_key IS NOT NULL
+UNION ALL
+SELECT COALESCE(s3_key, source_key), download_key FROM content_media_versions
+WHERE status<>'failed' OR modified_at >= $1
+`
+
+type GetMediaKeysRow struct {
+	S3Key       pgtype.Text
+	DownloadKey pgtype.Text
+}
+
+func (q *Queries) GetMediaKeys(ctx context.Context, failedBefore pgtype.Timestamp) ([]GetMediaKeysRow, error) {
+	rows, err := q.db.Query(ctx, getMediaKeys, failedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMediaKeysRow
+	for rows.Next() {
+		var i GetMediaKeysRow
+		if err := rows.Scan(&i.S3Key, &i.DownloadKey); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserContent = `-- name: GetUserContent :many
-SELECT id, created_at, title, description, type FROM content WHERE user_id=$1 ORDER BY created_at DESC LIMIT $2 OFFSET $3
+SELECT content.id, content.created_at, content.user_id, content.title, content.description, content.type,
+    COALESCE(content_play_counts.total_plays, 0)::BIGINT AS plays
+FROM content
+LEFT JOIN content_play_counts ON content_play_counts.content_id=content.id
+WHERE content.user_id=$1 AND content.deleted_at IS NULL
+AND ($2::UUID IS NULL OR CASE
+    WHEN $3::TEXT = 'title' AND $4::BOOLEAN
+        THEN (content.title, content.id) < ($5::TEXT, $2::UUID)
+    WHEN $3::TEXT = 'title'
+        THEN (content.title, content.id) > ($5::TEXT, $2::UUID)
+    WHEN $3::TEXT = 'popularity' AND $4::BOOLEAN
+        THEN (COALESCE(content_play_counts.total_plays, 0), content.id) > ($6::BIGINT, $2::UUID)
+    WHEN $3::TEXT = 'popularity'
+        THEN (COALESCE(content_play_counts.total_plays, 0), content.id) < ($6::BIGINT, $2::UUID)
+    WHEN $4::BOOLEAN
+        THEN (content.created_at, content.id) > ($7::TIMESTAMP, $2::UUID)
+    ELSE (content.created_at, content.id) < ($7::TIMESTAMP, $2::UUID)
+END)
+ORDER BY
+    CASE WHEN $3::TEXT = 'title' AND NOT $4::BOOLEAN THEN content.title END ASC,
+    CASE WHEN $3::TEXT = 'title' AND $4::BOOLEAN THEN content.title END DESC,
+    CASE WHEN $3::TEXT = 'popularity' AND NOT $4::BOOLEAN THEN COALESCE(content_play_counts.total_plays, 0) END DESC,
+    CASE WHEN $3::TEXT = 'popularity' AND $4::BOOLEAN THEN COALESCE(content_play_counts.total_plays, 0) END ASC,
+    CASE WHEN $3::TEXT = 'newest' AND NOT $4::BOOLEAN THEN content.created_at END DESC,
+    CASE WHEN $3::TEXT = 'newest' AND $4::BOOLEAN THEN content.created_at END ASC,
+    CASE WHEN ($3::TEXT = 'title') <> $4::BOOLEAN THEN content.id END ASC,
+    content.id DESC
+LIMIT $8
 `
 
 type GetUserContentParams struct {
-	UserID uuid.UUID
-	Limit  int32
-	Offset int32
+	UserID          uuid.UUID
+	CursorID        pgtype.UUID
+	Sort            string
+	Backward        bool
+	CursorTitle     pgtype.Text
+	CursorPlays     pgtype.Int8
+	CursorCreatedAt pgtype.Timestamp
+	Limit           int32
 }
 
 type GetUserContentRow struct {
 	ID          uuid.UUID
 	CreatedAt   pgtype.Timestamp
+	UserID      uuid.UUID
 	Title       string
 	Description string
 	Type        ContentType
+	Plays       int64
 }
 
 func (q *Queries) GetUserContent(ctx context.Context, arg GetUserContentParams) ([]GetUserContentRow, error) {
-	rows, err := q.db.Query(ctx, getUserContent, arg.UserID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, getUserContent,
+		arg.UserID,
+		arg.CursorID,
+		arg.Sort,
+		arg.Backward,
+		arg.CursorTitle,
+		arg.CursorPlays,
+		arg.CursorCreatedAt,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -159,9 +481,11 @@ func (q *Queries) GetUserContent(ctx context.Context, arg GetUserContentParams)
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
+			&i.UserID,
 			&i.Title,
 			&i.Description,
 			&i.Type,
+			&i.Plays,
 		); err != nil {
 			return nil, err
 		}
@@ -173,16 +497,70 @@ func (q *Queries) GetUserContent(ctx context.Context, arg GetUserContentParams)
 	return items, nil
 }
 
+const getViewableContentIDs = `-- name: GetViewableContentIDs :many
+SELECT id FROM content
+WHERE id = ANY($1::UUID[]) AND deleted_at IS NULL
+AND ((visibility<>'private' AND released) OR user_id=$2)
+`
+
+type GetViewableContentIDsParams struct {
+	Ids    []uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetViewableContentIDs(ctx context.Context, arg GetViewableContentIDsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getViewableContentIDs, arg.Ids, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rotateShareToken = `-- name: RotateShareToken :one
+UPDATE content SET share_token=gen_random_uuid(), modified_at=$1
+WHERE id=$2 AND user_id=$3 AND deleted_at IS NULL
+RETURNING share_token
+`
+
+type RotateShareTokenParams struct {
+	ModifiedAt pgtype.Timestamp
+	ID         uuid.UUID
+	UserID     uuid.UUID
+}
+
+func (q *Queries) RotateShareToken(ctx context.Context, arg RotateShareTokenParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, rotateShareToken, arg.ModifiedAt, arg.ID, arg.UserID)
+	var share_token uuid.UUID
+	err := row.Scan(&share_token)
+	return share_token, err
+}
+
 const updateContentDetails = `-- name: UpdateContentDetails :one
-UPDATE content SET title=$1, description=$2, type=$3, modified_at=$4
-WHERE id=$5 AND user_id=$6
-RETURNING id, created_at, modified_at, user_id, title, description, type, s3_key
+UPDATE content SET title=$1, description=$2, type=$3, visibility=$4, publish_at=$5, unpublish_at=$6, released=$7, modified_at=$8
+WHERE id=$9 AND user_id=$10 AND deleted_at IS NULL
+RETURNING id, created_at, modified_at, user_id, title, description, type, s3_key, album_id, disc_number, track_number, isrc, show_id, season_number, episode_number, published_at, show_notes, guid, download_key, download_size, duration, chapters_url, transcript_url, visibility, share_token, publish_at, unpublish_at, released, deleted_at, media_version_id
 `
 
 type UpdateContentDetailsParams struct {
 	Title       string
 	Description string
 	Type        ContentType
+	Visibility  ContentVisibility
+	PublishAt   pgtype.Timestamp
+	UnpublishAt pgtype.Timestamp
+	Released    bool
 	ModifiedAt  pgtype.Timestamp
 	ID          uuid.UUID
 	UserID      uuid.UUID
@@ -193,6 +571,10 @@ func (q *Queries) UpdateContentDetails(ctx context.Context, arg UpdateContentDet
 		arg.Title,
 		arg.Description,
 		arg.Type,
+		arg.Visibility,
+		arg.PublishAt,
+		arg.UnpublishAt,
+		arg.Released,
 		arg.ModifiedAt,
 		arg.ID,
 		arg.UserID,
@@ -207,28 +589,28 @@ func (q *Queries) UpdateContentDetails(ctx context.Context, arg UpdateContentDet
 		&i.Description,
 		&i.Type,
 		&i.S3Key,
+		&i.AlbumID,
+		&i.DiscNumber,
+		&i.TrackNumber,
+		&i.Isrc,
+		&i.ShowID,
+		&i.SeasonNumber,
+		&i.EpisodeNumber,
+		&i.PublishedAt,
+		&i.ShowNotes,
+		&i.Guid,
+		&i.DownloadKey,
+		&i.DownloadSize,
+		&i.Duration,
+		&i.ChaptersUrl,
+		&i.TranscriptUrl,
+		&i.Visibility,
+		&i.ShareToken,
+		&i.PublishAt,
+		&i.UnpublishAt,
+		&i.Released,
+		&i.DeletedAt,
+		&i.MediaVersionID,
 	)
 	return i, err
 }
-
-const updateS3Key = `-- name: UpdateS3Key :exec
-UPDATE content SET s3_key=$1, modified_at=$2
-WHERE id=$3 AND user_id=$4
-`
-
-type UpdateS3KeyParams struct {
-	S3Key      pgtype.Text
-	ModifiedAt pgtype.Timestamp
-	ID         uuid.UUID
-	UserID     uuid.UUID
-}
-
-func (q *Queries) UpdateS3Key(ctx context.Context, arg UpdateS3KeyParams) error {
-	_, err := q.db.Exec(ctx, updateS3Key,
-		arg.S3Key,
-		arg.ModifiedAt,
-		arg.ID,
-		arg.UserID,
-	)
-	return err
-}
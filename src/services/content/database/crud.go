@@ -2,12 +2,16 @@ package database
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-// Add content into DB
-func AddContentDB(c *Config, ctx context.Context, params AddContentParams) (*Content, error) {
+// Add content into DB along with its genres & tags
+func AddContentDB(c *Config, ctx context.Context, params AddContentParams, genres []string, tags []string) (*Content, error) {
 	// Begin DB transaction
 	tx, err := c.DB.Begin(ctx)
 	if err != nil {
@@ -23,6 +27,10 @@ func AddContentDB(c *Config, ctx context.Context, params AddContentParams) (*Con
 		return nil, err
 	}
 
+	if err := setContentLabels(qtx, ctx, content.ID, genres, tags); err != nil {
+		return nil, err
+	}
+
 	// Commit the transaction
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
@@ -30,8 +38,8 @@ func AddContentDB(c *Config, ctx context.Context, params AddContentParams) (*Con
 	return &content, nil
 }
 
-// Update content details
-func UpdateContentDetailDB(c *Config, ctx context.Context, params UpdateContentDetailsParams) (*Content, error) {
+// Update content details, Genres & tags are replaced only if they are not nil
+func UpdateContentDetailDB(c *Config, ctx context.Context, params UpdateContentDetailsParams, genres []string, tags []string) (*Content, error) {
 	// Begin DB transaction
 	tx, err := c.DB.Begin(ctx)
 	if err != nil {
@@ -47,6 +55,10 @@ func UpdateContentDetailDB(c *Config, ctx context.Context, params UpdateContentD
 		return nil, err
 	}
 
+	if err := setContentLabels(qtx, ctx, content.ID, genres, tags); err != nil {
+		return nil, err
+	}
+
 	// Commit the transaction
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
@@ -54,8 +66,8 @@ func UpdateContentDetailDB(c *Config, ctx context.Context, params UpdateContentD
 	return &content, nil
 }
 
-// Update content s3 key
-func UpdateContentS3KeyDB(c *Config, ctx context.Context, params UpdateS3KeyParams) error {
+// Move content to trash, It's removed permanently by the retention job
+func DeleteContentDB(c *Config, ctx context.Context, params DeleteContentParams) error {
 	// Begin DB transaction
 	tx, err := c.DB.Begin(ctx)
 	if err != nil {
@@ -65,8 +77,8 @@ func UpdateContentS3KeyDB(c *Config, ctx context.Context, params UpdateS3KeyPara
 
 	qtx := c.Queries.WithTx(tx)
 
-	// update content s3 key
-	if err := qtx.UpdateS3Key(ctx, params); err != nil {
+	// mark content as deleted
+	if err := qtx.DeleteContent(ctx, params); err != nil {
 		return err
 	}
 
@@ -77,8 +89,193 @@ func UpdateContentS3KeyDB(c *Config, ctx context.Context, params UpdateS3KeyPara
 	return nil
 }
 
-// Delete content from DB
-func DeleteContentDB(c *Config, ctx context.Context, params DeleteContentParams) error {
+// Get content detail by ID from DB
+func GetContentDetailDB(c *Config, ctx context.Context, contentID uuid.UUID) (*Content, error) {
+	content, err := c.Queries.GetContentById(ctx, contentID)
+	if err != nil {
+		return nil, err
+	}
+	return &content, nil
+}
+
+// Get content details by its share token
+func GetContentByShareTokenDB(c *Config, ctx context.Context, shareToken uuid.UUID) (*Content, error) {
+	content, err := c.Queries.GetContentByShareToken(ctx, shareToken)
+	if err != nil {
+		return nil, err
+	}
+	return &content, nil
+}
+
+// Generate a new share token for the content, Old share link stops working
+func RotateShareTokenDB(c *Config, ctx context.Context, params RotateShareTokenParams) (uuid.UUID, error) {
+	return c.Queries.RotateShareToken(ctx, params)
+}
+
+// Get content posted by a user
+func GetUserContentDB(c *Config, ctx context.Context, params GetUserContentParams) ([]GetUserContentRow, error) {
+	contents, err := c.Queries.GetUserContent(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return contents, nil
+}
+
+// Get contents from DB
+func GetContentListDB(c *Config, ctx context.Context, params GetContentListParams) ([]GetContentListRow, error) {
+	contents, err := c.Queries.GetContentList(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return contents, nil
+}
+
+// Delete all the contents posted by a user along with their shows, Artist links, Library & history,
+// Returns the s3 keys of the media & download files of every deleted content along with its ID.
+func DeleteUserContentDB(c *Config, ctx context.Context, userID uuid.UUID) ([]DeleteUserContentRow, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// delete user contents from DB
+	keys, err := qtx.DeleteUserContent(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// unlink the user from the artists, Artists themselves are kept since they can be managed by other users
+	if err := qtx.DeleteUserArtistLinks(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// delete user podcast shows
+	if err := qtx.DeleteUserShows(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// delete the liked contents & saved albums of the user
+	if err := qtx.DeleteUserLibrary(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// delete the listening history & playback positions of the user
+	if err := qtx.DeleteUserPlayEvents(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := qtx.DeleteUserPlaybackPositions(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Get all the contents posted by a user, Used for exporting the user data
+func GetAllUserContentDB(c *Config, ctx context.Context, userID uuid.UUID) ([]Content, error) {
+	contents, err := c.Queries.GetAllUserContent(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return contents, nil
+}
+
+// Return the IDs of the given contents which the user can view, Missing and deleted contents are skipped
+func GetViewableContentIDsDB(c *Config, ctx context.Context, params GetViewableContentIDsParams) ([]uuid.UUID, error) {
+	contentIDs, err := c.Queries.GetViewableContentIDs(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return contentIDs, nil
+}
+
+// Fetch all the play events of the given user
+func GetAllUserPlayEventsDB(c *Config, ctx context.Context, userID uuid.UUID) ([]PlayEvent, error) {
+	events, err := c.Queries.GetAllUserPlayEvents(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Fetch all the content likes of the given user
+func GetAllUserLikesDB(c *Config, ctx context.Context, userID uuid.UUID) ([]ContentLike, error) {
+	likes, err := c.Queries.GetAllUserLikes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return likes, nil
+}
+
+// Fetch all the saved albums of the given user
+func GetAllUserSavedAlbumsDB(c *Config, ctx context.Context, userID uuid.UUID) ([]SavedAlbum, error) {
+	albums, err := c.Queries.GetAllUserSavedAlbums(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return albums, nil
+}
+
+// Fetch all the playback positions of the given user
+func GetAllUserPlaybackPositionsDB(c *Config, ctx context.Context, userID uuid.UUID) ([]PlaybackPosition, error) {
+	positions, err := c.Queries.GetAllUserPlaybackPositions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// Create an artist and link it with the user who created it
+func CreateArtistDB(c *Config, ctx context.Context, params CreateArtistParams, userID uuid.UUID) (*Artist, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// add artist into DB
+	artist, err := qtx.CreateArtist(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// link the artist with the user
+	if err := qtx.AddArtistUser(ctx, AddArtistUserParams{
+		ArtistID: artist.ID,
+		UserID:   userID,
+	}); err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &artist, nil
+}
+
+// Get artist detail by ID from DB
+func GetArtistDetailDB(c *Config, ctx context.Context, artistID uuid.UUID) (*Artist, error) {
+	artist, err := c.Queries.GetArtistById(ctx, artistID)
+	if err != nil {
+		return nil, err
+	}
+	return &artist, nil
+}
+
+// Link a user with the artist
+func AddArtistUserDB(c *Config, ctx context.Context, params AddArtistUserParams) error {
 	// Begin DB transaction
 	tx, err := c.DB.Begin(ctx)
 	if err != nil {
@@ -88,8 +285,8 @@ func DeleteContentDB(c *Config, ctx context.Context, params DeleteContentParams)
 
 	qtx := c.Queries.WithTx(tx)
 
-	// delete content from DB
-	if err := qtx.DeleteContent(ctx, params); err != nil {
+	// link user with the artist
+	if err := qtx.AddArtistUser(ctx, params); err != nil {
 		return err
 	}
 
@@ -100,29 +297,897 @@ func DeleteContentDB(c *Config, ctx context.Context, params DeleteContentParams)
 	return nil
 }
 
-// Get content detail by ID from DB
-func GetContentDetailDB(c *Config, ctx context.Context, contentID uuid.UUID) (*Content, error) {
-	content, err := c.Queries.GetContentById(ctx, contentID)
+// Check weather or not the user is linked with the artist
+func IsArtistUserDB(c *Config, ctx context.Context, params IsArtistUserParams) (bool, error) {
+	return c.Queries.IsArtistUser(ctx, params)
+}
+
+// Add album into DB
+func CreateAlbumDB(c *Config, ctx context.Context, params CreateAlbumParams) (*Album, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// add album into DB
+	album, err := qtx.CreateAlbum(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+// Get album detail by ID from DB
+func GetAlbumDetailDB(c *Config, ctx context.Context, albumID uuid.UUID) (*Album, error) {
+	album, err := c.Queries.GetAlbumById(ctx, albumID)
+	if err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+// Get albums released by an artist
+func GetArtistAlbumsDB(c *Config, ctx context.Context, params GetArtistAlbumsParams) ([]Album, error) {
+	albums, err := c.Queries.GetArtistAlbums(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return albums, nil
+}
+
+// Add content into the album tracklist and replace its artist credits
+//
+// Album artist is credited as the primary artist and the given artists are credited as featured artists
+func AddAlbumTrackDB(c *Config, ctx context.Context, params AddAlbumTrackParams, primaryArtistID uuid.UUID, featuredArtistIDs []uuid.UUID) (*Content, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// update the track details of the content
+	content, err := qtx.AddAlbumTrack(ctx, params)
 	if err != nil {
 		return nil, err
 	}
+
+	// remove the existing artist credits
+	if err := qtx.DeleteContentArtists(ctx, content.ID); err != nil {
+		return nil, err
+	}
+
+	for _, artistID := range featuredArtistIDs {
+		if err := qtx.AddContentArtist(ctx, AddContentArtistParams{
+			ContentID: content.ID,
+			ArtistID:  artistID,
+			Role:      ArtistRoleF,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Primary artist is added last, So that it is never overridden by a featured credit
+	if err := qtx.AddContentArtist(ctx, AddContentArtistParams{
+		ContentID: content.ID,
+		ArtistID:  primaryArtistID,
+		Role:      ArtistRoleP,
+	}); err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 	return &content, nil
 }
 
-// Get content posted by a user
-func GetUserContentDB(c *Config, ctx context.Context, params GetUserContentParams) ([]GetUserContentRow, error) {
-	contents, err := c.Queries.GetUserContent(ctx, params)
+// Get the tracklist of an album
+func GetAlbumTracksDB(c *Config, ctx context.Context, albumID uuid.UUID) ([]GetAlbumTracksRow, error) {
+	tracks, err := c.Queries.GetAlbumTracks(ctx, pgtype.UUID{
+		Bytes: albumID,
+		Valid: true,
+	})
 	if err != nil {
 		return nil, err
 	}
-	return contents, nil
+	return tracks, nil
 }
 
-// Get contents from DB
-func GetContentListDB(c *Config, ctx context.Context, params GetContentListParams) ([]GetContentListRow, error) {
-	contents, err := c.Queries.GetContentList(ctx, params)
+// Get the artists credited on the given contents
+func GetContentArtistsDB(c *Config, ctx context.Context, contentIDs []uuid.UUID) ([]GetContentArtistsRow, error) {
+	artists, err := c.Queries.GetContentArtists(ctx, contentIDs)
 	if err != nil {
 		return nil, err
 	}
-	return contents, nil
+	return artists, nil
+}
+
+// Add podcast show into DB
+func CreateShowDB(c *Config, ctx context.Context, params CreateShowParams) (*PodcastShow, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// add show into DB
+	show, err := qtx.CreateShow(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &show, nil
+}
+
+// Get podcast show detail by ID from DB
+func GetShowDetailDB(c *Config, ctx context.Context, showID uuid.UUID) (*PodcastShow, error) {
+	show, err := c.Queries.GetShowById(ctx, showID)
+	if err != nil {
+		return nil, err
+	}
+	return &show, nil
+}
+
+// Add podcast episode into DB
+//
+// pgx.ErrNoRows is returned if an episode with the same title or GUID already exists
+func AddEpisodeDB(c *Config, ctx context.Context, params AddEpisodeParams) (*Content, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// add episode into DB
+	episode, err := qtx.AddEpisode(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &episode, nil
+}
+
+// Add an imported podcast show along with its episodes into DB
+//
+// Episodes conflicting with the existing content are skipped, Only the added episodes are returned.
+// Media imports of the added episodes are recorded in the same transaction, So that they can be resumed after a restart.
+func ImportShowDB(c *Config, ctx context.Context, showParams CreateShowParams, episodesParams []AddEpisodeParams, mediaImportsParams map[uuid.UUID]AddEpisodeMediaImportParams) (*PodcastShow, []Content, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// add show into DB
+	show, err := qtx.CreateShow(ctx, showParams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var episodes []Content
+	for _, params := range episodesParams {
+		params.ShowID = pgtype.UUID{
+			Bytes: show.ID,
+			Valid: true,
+		}
+
+		episode, err := qtx.AddEpisode(ctx, params)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		if importParams, exists := mediaImportsParams[episode.ID]; exists {
+			if err := qtx.AddEpisodeMediaImport(ctx, importParams); err != nil {
+				return nil, nil, err
+			}
+		}
+		episodes = append(episodes, episode)
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+	return &show, episodes, nil
+}
+
+// Get the episode media imports which are not completed yet, Imports failed the given number of times are skipped
+func GetPendingEpisodeMediaImportsDB(c *Config, ctx context.Context, attempts int32) ([]EpisodeMediaImport, error) {
+	imports, err := c.Queries.GetPendingEpisodeMediaImports(ctx, attempts)
+	if err != nil {
+		return nil, err
+	}
+	return imports, nil
+}
+
+// Count a failed attempt of an episode media import
+func FailEpisodeMediaImportDB(c *Config, ctx context.Context, contentID uuid.UUID) error {
+	return c.Queries.MarkEpisodeMediaImportFailed(ctx, contentID)
+}
+
+// Delete the episode media import once its media version is added
+func DeleteEpisodeMediaImportDB(c *Config, ctx context.Context, contentID uuid.UUID) error {
+	return c.Queries.DeleteEpisodeMediaImport(ctx, contentID)
+}
+
+// Get episodes of a podcast show
+func GetShowEpisodesDB(c *Config, ctx context.Context, params GetShowEpisodesParams) ([]GetShowEpisodesRow, error) {
+	episodes, err := c.Queries.GetShowEpisodes(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return episodes, nil
+}
+
+// Get the published episodes of a podcast show whose media files are ready
+func GetShowFeedEpisodesDB(c *Config, ctx context.Context, params GetShowFeedEpisodesParams) ([]GetShowFeedEpisodesRow, error) {
+	episodes, err := c.Queries.GetShowFeedEpisodes(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return episodes, nil
+}
+
+// Add content into the liked contents of a user, Liking an already liked content does nothing
+func LikeContentDB(c *Config, ctx context.Context, params LikeContentParams) error {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// add like into DB
+	if err := qtx.LikeContent(ctx, params); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Remove content from the liked contents of a user
+func UnlikeContentDB(c *Config, ctx context.Context, params UnlikeContentParams) error {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// remove like from DB
+	if err := qtx.UnlikeContent(ctx, params); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get number of users who liked the content
+func GetContentLikeCountDB(c *Config, ctx context.Context, contentID uuid.UUID) (int64, error) {
+	count, err := c.Queries.GetContentLikeCount(ctx, contentID)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Add album into the library of a user, Saving an already saved album does nothing
+func SaveAlbumDB(c *Config, ctx context.Context, params SaveAlbumParams) error {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// add saved album into DB
+	if err := qtx.SaveAlbum(ctx, params); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Remove album from the library of a user
+func UnsaveAlbumDB(c *Config, ctx context.Context, params UnsaveAlbumParams) error {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// remove saved album from DB
+	if err := qtx.UnsaveAlbum(ctx, params); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get the liked contents & saved albums of a user
+func GetUserLibraryDB(c *Config, ctx context.Context, params GetUserLibraryParams) ([]GetUserLibraryRow, error) {
+	items, err := c.Queries.GetUserLibrary(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Add a batch of play events into DB
+func AddPlayEventsDB(c *Config, ctx context.Context, params []AddPlayEventsParams) (int64, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// copy play events into DB
+	count, err := qtx.AddPlayEvents(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Create the monthly partition of play events which holds the given time
+func CreatePlayEventsPartitionDB(c *Config, ctx context.Context, monthStart time.Time) error {
+	return c.Queries.CreatePlayEventsPartition(ctx, pgtype.Timestamp{
+		Time:  monthStart,
+		Valid: true,
+	})
+}
+
+// Get the recently played contents of a user
+func GetUserPlayHistoryDB(c *Config, ctx context.Context, params GetUserPlayHistoryParams) ([]GetUserPlayHistoryRow, error) {
+	history, err := c.Queries.GetUserPlayHistory(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// Save the playback position of a content and return the latest one
+//
+// Positions are synced using last-write-wins by the client timestamp,
+// So the saved position is not updated if the given one is older than it
+func SavePlaybackPositionDB(c *Config, ctx context.Context, params UpsertPlaybackPositionParams) (*PlaybackPosition, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// add or update playback position in DB
+	if err := qtx.UpsertPlaybackPosition(ctx, params); err != nil {
+		return nil, err
+	}
+
+	position, err := qtx.GetPlaybackPosition(ctx, GetPlaybackPositionParams{
+		UserID:    params.UserID,
+		ContentID: params.ContentID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &position, nil
+}
+
+// Get the playback position of a content for a user
+func GetPlaybackPositionDB(c *Config, ctx context.Context, params GetPlaybackPositionParams) (*PlaybackPosition, error) {
+	position, err := c.Queries.GetPlaybackPosition(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &position, nil
+}
+
+// Add the plays received in between the last run and given time into the play counts,
+// And recompute the plays of the week ending at the given time
+//
+// A play is counted only if it's long enough and the user has not played the same content in the previous 30 minutes,
+// So that the repeated short plays and the progress events of the same session are counted once.
+// Checkpoint row is locked during the update, So that the same plays are not counted twice by the concurrent runs.
+func UpdatePlayCountsDB(c *Config, ctx context.Context, jobName string, processedUntil time.Time, minDuration int32) error {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// add the checkpoint for the first run
+	if err := qtx.AddJobCheckpoint(ctx, AddJobCheckpointParams{
+		Name: jobName,
+		ProcessedUntil: pgtype.Timestamp{
+			Time:  time.Time{},
+			Valid: true,
+		},
+	}); err != nil {
+		return err
+	}
+
+	processedFrom, err := qtx.GetJobCheckpoint(ctx, jobName)
+	if err != nil {
+		return err
+	}
+
+	// Nothing to process if the concurrent run has already processed the plays
+	if !processedFrom.Time.Before(processedUntil) {
+		return nil
+	}
+
+	modifiedAt := pgtype.Timestamp{
+		Time:  time.Now().UTC(),
+		Valid: true,
+	}
+	until := pgtype.Timestamp{
+		Time:  processedUntil,
+		Valid: true,
+	}
+
+	// add the new plays into the total play counts
+	if err := qtx.AddPlayCounts(ctx, AddPlayCountsParams{
+		ModifiedAt:     modifiedAt,
+		ProcessedFrom:  processedFrom,
+		ProcessedUntil: until,
+		MinDuration:    minDuration,
+	}); err != nil {
+		return err
+	}
+
+	// recompute the weekly play counts
+	if err := qtx.RefreshWeeklyPlayCounts(ctx, RefreshWeeklyPlayCountsParams{
+		WeekStart: pgtype.Timestamp{
+			Time:  processedUntil.AddDate(0, 0, -7),
+			Valid: true,
+		},
+		ProcessedUntil: until,
+		MinDuration:    minDuration,
+		ModifiedAt:     modifiedAt,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.UpdateJobCheckpoint(ctx, UpdateJobCheckpointParams{
+		ProcessedUntil: until,
+		Name:           jobName,
+	}); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get the most played contents of the last week
+func GetTrendingContentDB(c *Config, ctx context.Context, limit int32) ([]GetTrendingContentRow, error) {
+	contents, err := c.Queries.GetTrendingContent(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return contents, nil
+}
+
+// Get the most played contents of all time of the given type
+func GetTopContentDB(c *Config, ctx context.Context, params GetTopContentParams) ([]GetTopContentRow, error) {
+	contents, err := c.Queries.GetTopContent(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return contents, nil
+}
+
+// Replace the precomputed similar contents with the newly computed ones
+func RefreshContentSimilaritiesDB(c *Config, ctx context.Context, params AddContentSimilaritiesParams) error {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// delete the old similarities
+	if err := qtx.DeleteContentSimilarities(ctx); err != nil {
+		return err
+	}
+
+	// add the new similarities into DB
+	if err := qtx.AddContentSimilarities(ctx, params); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get the contents similar to the given content, Most similar first
+func GetSimilarContentDB(c *Config, ctx context.Context, params GetSimilarContentParams) ([]GetSimilarContentRow, error) {
+	contents, err := c.Queries.GetSimilarContent(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return contents, nil
+}
+
+// Get the recommended contents for a user based on their recent plays & likes
+func GetUserRecommendationsDB(c *Config, ctx context.Context, params GetUserRecommendationsParams) ([]GetUserRecommendationsRow, error) {
+	contents, err := c.Queries.GetUserRecommendations(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return contents, nil
+}
+
+// Replace the genres & tags of a content, Nil values are left unchanged
+func setContentLabels(qtx *Queries, ctx context.Context, contentID uuid.UUID, genres []string, tags []string) error {
+	if genres != nil {
+		if err := qtx.DeleteContentGenres(ctx, contentID); err != nil {
+			return err
+		}
+
+		if err := qtx.AddContentGenres(ctx, AddContentGenresParams{
+			ContentID:  contentID,
+			GenreSlugs: genres,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if tags != nil {
+		if err := qtx.DeleteContentTags(ctx, contentID); err != nil {
+			return err
+		}
+
+		if err := qtx.AddContentTags(ctx, AddContentTagsParams{
+			ContentID: contentID,
+			Tags:      tags,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get the curated genres
+func GetGenresDB(c *Config, ctx context.Context) ([]Genre, error) {
+	genres, err := c.Queries.GetGenres(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return genres, nil
+}
+
+// Get the genres & tags of a content
+func GetContentLabelsDB(c *Config, ctx context.Context, contentID uuid.UUID) ([]string, []string, error) {
+	genres, err := c.Queries.GetContentGenres(ctx, contentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tags, err := c.Queries.GetContentTags(ctx, contentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return genres, tags, nil
+}
+
+// Get the genre & tag counts of the contents matching the given filters
+func GetContentFacetsDB(c *Config, ctx context.Context, params GetTagFacetsParams) ([]GetGenreFacetsRow, []GetTagFacetsRow, error) {
+	genreFacets, err := c.Queries.GetGenreFacets(ctx, GetGenreFacetsParams{
+		Genres: params.Genres,
+		Tags:   params.Tags,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tagFacets, err := c.Queries.GetTagFacets(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return genreFacets, tagFacets, nil
+}
+
+// Release the scheduled contents which are due at the given time, Returns the number of released contents
+//
+// Published events of the released contents are added to the outbox by the same statement
+func ReleaseScheduledContentDB(c *Config, ctx context.Context, now time.Time) (int64, error) {
+	return c.Queries.ReleaseScheduledContent(ctx, pgtype.Timestamp{
+		Time:  now,
+		Valid: true,
+	})
+}
+
+// Send the pending release events from the outbox, Events returned by the send function are removed
+//
+// Events are locked till they are sent, So that the other instances don't send them again
+func SendReleaseEventsDB(c *Config, ctx context.Context, limit int32, send func([]ContentReleaseEvent) []uuid.UUID) (int, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	events, err := qtx.GetPendingReleaseEvents(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	sentIDs := send(events)
+	if len(sentIDs) == 0 {
+		return len(events), nil
+	}
+
+	if err := qtx.DeleteReleaseEvents(ctx, sentIDs); err != nil {
+		return 0, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+// Hide the released contents whose embargo has started at the given time
+func UnpublishExpiredContentDB(c *Config, ctx context.Context, now time.Time) (int64, error) {
+	return c.Queries.UnpublishExpiredContent(ctx, pgtype.Timestamp{
+		Time:  now,
+		Valid: true,
+	})
+}
+
+// Get the deleted contents of a user which are still in trash
+func GetUserTrashDB(c *Config, ctx context.Context, params GetUserTrashParams) ([]GetUserTrashRow, error) {
+	contents, err := c.Queries.GetUserTrash(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return contents, nil
+}
+
+// Restore a deleted content from trash
+func RestoreContentDB(c *Config, ctx context.Context, params RestoreContentParams) (*Content, error) {
+	content, err := c.Queries.RestoreContent(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &content, nil
+}
+
+// Permanently delete the contents which were deleted before the given time and return the s3 keys of their media & download files
+func PurgeDeletedContentDB(c *Config, ctx context.Context, deletedBefore time.Time) ([]pgtype.Text, error) {
+	return c.Queries.PurgeDeletedContent(ctx, pgtype.Timestamp{
+		Time:  deletedBefore,
+		Valid: true,
+	})
+}
+
+// Get the s3 keys of all the contents including the ones in trash,
+// Files of the media versions which failed before the given time are not included
+func GetMediaKeysDB(c *Config, ctx context.Context, failedBefore time.Time) ([]GetMediaKeysRow, error) {
+	keys, err := c.Queries.GetMediaKeys(ctx, pgtype.Timestamp{
+		Time:  failedBefore,
+		Valid: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Add a new media version for the uploaded file of the content, Returns ErrNoRows if the user doesn't own the content
+func AddMediaVersionDB(c *Config, ctx context.Context, params AddMediaVersionParams) (*ContentMediaVersion, error) {
+	version, err := c.Queries.AddMediaVersion(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// Save the converted files of a media version and switch the content to it,
+// Content is switched only if it's not serving a newer version already. Returns weather or not the content was switched.
+func CompleteMediaVersionDB(c *Config, ctx context.Context, params MarkMediaVersionReadyParams) (bool, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	// save the details of the converted files
+	if err := qtx.MarkMediaVersionReady(ctx, params); err != nil {
+		return false, err
+	}
+
+	// serve the new version
+	rows, err := qtx.PromoteMediaVersion(ctx, PromoteMediaVersionParams{
+		ModifiedAt: params.ModifiedAt,
+		VersionID:  params.ID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// Mark a media version as failed, Content keeps serving its current version
+func FailMediaVersionDB(c *Config, ctx context.Context, params MarkMediaVersionFailedParams) error {
+	return c.Queries.MarkMediaVersionFailed(ctx, params)
+}
+
+// Switch the content back to one of its previous media versions
+func RollbackMediaVersionDB(c *Config, ctx context.Context, params RollbackMediaVersionParams) (int64, error) {
+	return c.Queries.RollbackMediaVersion(ctx, params)
+}
+
+// Get the media versions of a content, Latest first
+func GetContentMediaVersionsDB(c *Config, ctx context.Context, contentID uuid.UUID) ([]ContentMediaVersion, error) {
+	versions, err := c.Queries.GetContentMediaVersions(ctx, contentID)
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// Delete the superseded media versions of a content and return their s3 keys
+func DeleteSupersededMediaVersionsDB(c *Config, ctx context.Context, params DeleteSupersededMediaVersionsParams) ([]string, error) {
+	keys, err := c.Queries.DeleteSupersededMediaVersions(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Add a multipart upload session into DB
+func CreateUploadSessionDB(c *Config, ctx context.Context, params CreateUploadSessionParams) (*UploadSession, error) {
+	session, err := c.Queries.CreateUploadSession(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Get a multipart upload session of the user
+func GetUploadSessionDB(c *Config, ctx context.Context, params GetUploadSessionParams) (*UploadSession, error) {
+	session, err := c.Queries.GetUploadSession(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Mark a multipart upload session as completed or aborted
+func UpdateUploadSessionStatusDB(c *Config, ctx context.Context, params UpdateUploadSessionStatusParams) error {
+	return c.Queries.UpdateUploadSessionStatus(ctx, params)
+}
+
+// Get the active multipart upload sessions which have expired
+func GetExpiredUploadSessionsDB(c *Config, ctx context.Context, params GetExpiredUploadSessionsParams) ([]UploadSession, error) {
+	sessions, err := c.Queries.GetExpiredUploadSessions(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Get the media version of a content created for the given uploaded file
+func GetMediaVersionBySourceKeyDB(c *Config, ctx context.Context, params GetMediaVersionBySourceKeyParams) (*ContentMediaVersion, error) {
+	version, err := c.Queries.GetMediaVersionBySourceKey(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// Record the key of a single file upload issued to the user
+func CreateMediaUploadDB(c *Config, ctx context.Context, params CreateMediaUploadParams) error {
+	return c.Queries.CreateMediaUpload(ctx, params)
+}
+
+// Get the content & user for which the given key was issued, Either by a pre-signed URL or by a multipart upload
+func GetIssuedUploadDB(c *Config, ctx context.Context, s3Key string) (*GetIssuedUploadRow, error) {
+	upload, err := c.Queries.GetIssuedUpload(ctx, s3Key)
+	if err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// Remove the issued single file upload keys which were created before the given time
+func DeleteExpiredMediaUploadsDB(c *Config, ctx context.Context, createdAt pgtype.Timestamp) error {
+	return c.Queries.DeleteExpiredMediaUploads(ctx, createdAt)
 }
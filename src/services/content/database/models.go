@@ -12,6 +12,91 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AlbumType string
+
+const (
+	AlbumTypeA AlbumType = "A"
+	AlbumTypeS AlbumType = "S"
+	AlbumTypeE AlbumType = "E"
+)
+
+func (e *AlbumType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AlbumType(s)
+	case string:
+		*e = AlbumType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AlbumType: %T", src)
+	}
+	return nil
+}
+
+type NullAlbumType struct {
+	AlbumType AlbumType
+	Valid     bool // Valid is true if AlbumType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAlbumType) Scan(value interface{}) error {
+	if value == nil {
+		ns.AlbumType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AlbumType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAlbumType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AlbumType), nil
+}
+
+type ArtistRole string
+
+const (
+	ArtistRoleP ArtistRole = "P"
+	ArtistRoleF ArtistRole = "F"
+)
+
+func (e *ArtistRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ArtistRole(s)
+	case string:
+		*e = ArtistRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ArtistRole: %T", src)
+	}
+	return nil
+}
+
+type NullArtistRole struct {
+	ArtistRole ArtistRole
+	Valid      bool // Valid is true if ArtistRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullArtistRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.ArtistRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ArtistRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullArtistRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ArtistRole), nil
+}
+
 type ContentType string
 
 const (
@@ -54,13 +139,376 @@ func (ns NullContentType) Value() (driver.Value, error) {
 	return string(ns.ContentType), nil
 }
 
+type ContentVisibility string
+
+const (
+	ContentVisibilityPublic   ContentVisibility = "public"
+	ContentVisibilityUnlisted ContentVisibility = "unlisted"
+	ContentVisibilityPrivate  ContentVisibility = "private"
+)
+
+func (e *ContentVisibility) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ContentVisibility(s)
+	case string:
+		*e = ContentVisibility(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ContentVisibility: %T", src)
+	}
+	return nil
+}
+
+type NullContentVisibility struct {
+	ContentVisibility ContentVisibility
+	Valid             bool // Valid is true if ContentVisibility is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullContentVisibility) Scan(value interface{}) error {
+	if value == nil {
+		ns.ContentVisibility, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ContentVisibility.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullContentVisibility) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ContentVisibility), nil
+}
+
+type MediaVersionStatus string
+
+const (
+	MediaVersionStatusProcessing MediaVersionStatus = "processing"
+	MediaVersionStatusReady      MediaVersionStatus = "ready"
+	MediaVersionStatusFailed     MediaVersionStatus = "failed"
+)
+
+func (e *MediaVersionStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MediaVersionStatus(s)
+	case string:
+		*e = MediaVersionStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MediaVersionStatus: %T", src)
+	}
+	return nil
+}
+
+type NullMediaVersionStatus struct {
+	MediaVersionStatus MediaVersionStatus
+	Valid              bool // Valid is true if MediaVersionStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMediaVersionStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.MediaVersionStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MediaVersionStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMediaVersionStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MediaVersionStatus), nil
+}
+
+type PlayEventType string
+
+const (
+	PlayEventTypeS PlayEventType = "S"
+	PlayEventTypeP PlayEventType = "P"
+	PlayEventTypeE PlayEventType = "E"
+)
+
+func (e *PlayEventType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PlayEventType(s)
+	case string:
+		*e = PlayEventType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PlayEventType: %T", src)
+	}
+	return nil
+}
+
+type NullPlayEventType struct {
+	PlayEventType PlayEventType
+	Valid         bool // Valid is true if PlayEventType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPlayEventType) Scan(value interface{}) error {
+	if value == nil {
+		ns.PlayEventType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PlayEventType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPlayEventType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PlayEventType), nil
+}
+
+type UploadSessionStatus string
+
+const (
+	UploadSessionStatusActive    UploadSessionStatus = "active"
+	UploadSessionStatusCompleted UploadSessionStatus = "completed"
+	UploadSessionStatusAborted   UploadSessionStatus = "aborted"
+)
+
+func (e *UploadSessionStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = UploadSessionStatus(s)
+	case string:
+		*e = UploadSessionStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for UploadSessionStatus: %T", src)
+	}
+	return nil
+}
+
+type NullUploadSessionStatus struct {
+	UploadSessionStatus UploadSessionStatus
+	Valid               bool // Valid is true if UploadSessionStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullUploadSessionStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.UploadSessionStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.UploadSessionStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullUploadSessionStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.UploadSessionStatus), nil
+}
+
+type Album struct {
+	ID          uuid.UUID
+	CreatedAt   pgtype.Timestamp
+	ModifiedAt  pgtype.Timestamp
+	ArtistID    uuid.UUID
+	Title       string
+	Type        AlbumType
+	ReleaseDate pgtype.Date
+	Upc         pgtype.Text
+}
+
+type Artist struct {
+	ID         uuid.UUID
+	CreatedAt  pgtype.Timestamp
+	ModifiedAt pgtype.Timestamp
+	Name       string
+	Bio        string
+}
+
+type ArtistUser struct {
+	ArtistID uuid.UUID
+	UserID   uuid.UUID
+}
+
 type Content struct {
+	ID             uuid.UUID
+	CreatedAt      pgtype.Timestamp
+	ModifiedAt     pgtype.Timestamp
+	UserID         uuid.UUID
+	Title          string
+	Description    string
+	Type           ContentType
+	S3Key          pgtype.Text
+	AlbumID        pgtype.UUID
+	DiscNumber     pgtype.Int4
+	TrackNumber    pgtype.Int4
+	Isrc           pgtype.Text
+	ShowID         pgtype.UUID
+	SeasonNumber   pgtype.Int4
+	EpisodeNumber  pgtype.Int4
+	PublishedAt    pgtype.Timestamp
+	ShowNotes      pgtype.Text
+	Guid           pgtype.Text
+	DownloadKey    pgtype.Text
+	DownloadSize   pgtype.Int8
+	Duration       pgtype.Int4
+	ChaptersUrl    pgtype.Text
+	TranscriptUrl  pgtype.Text
+	Visibility     ContentVisibility
+	ShareToken     uuid.UUID
+	PublishAt      pgtype.Timestamp
+	UnpublishAt    pgtype.Timestamp
+	Released       bool
+	DeletedAt      pgtype.Timestamp
+	MediaVersionID pgtype.UUID
+}
+
+type ContentArtist struct {
+	ContentID uuid.UUID
+	ArtistID  uuid.UUID
+	Role      ArtistRole
+}
+
+type ContentGenre struct {
+	ContentID uuid.UUID
+	GenreSlug string
+}
+
+type ContentLike struct {
+	ID        uuid.UUID
+	CreatedAt pgtype.Timestamp
+	UserID    uuid.UUID
+	ContentID uuid.UUID
+}
+
+type ContentMediaVersion struct {
+	ID           uuid.UUID
+	CreatedAt    pgtype.Timestamp
+	ModifiedAt   pgtype.Timestamp
+	ContentID    uuid.UUID
+	Version      int32
+	SourceKey    string
+	S3Key        pgtype.Text
+	DownloadKey  pgtype.Text
+	DownloadSize pgtype.Int8
+	Duration     pgtype.Int4
+	Status       MediaVersionStatus
+}
+
+type ContentPlayCount struct {
+	ContentID   uuid.UUID
+	TotalPlays  int64
+	WeeklyPlays int64
+	ModifiedAt  pgtype.Timestamp
+}
+
+type ContentReleaseEvent struct {
+	ID          uuid.UUID
+	CreatedAt   pgtype.Timestamp
+	ContentID   uuid.UUID
+	UserID      uuid.UUID
+	Title       string
+	Type        ContentType
+	PublishedAt pgtype.Timestamp
+}
+
+type ContentSimilarity struct {
+	ContentID        uuid.UUID
+	SimilarContentID uuid.UUID
+	Score            float64
+	CreatedAt        pgtype.Timestamp
+}
+
+type ContentTag struct {
+	ContentID uuid.UUID
+	Tag       string
+}
+
+type EpisodeMediaImport struct {
+	ContentID     uuid.UUID
+	CreatedAt     pgtype.Timestamp
+	UserID        uuid.UUID
+	EnclosureUrl  string
+	EnclosureType string
+	Attempts      int32
+}
+
+type Genre struct {
+	Slug string
+	Name string
+	Type ContentType
+}
+
+type JobCheckpoint struct {
+	Name           string
+	ProcessedUntil pgtype.Timestamp
+}
+
+type MediaUpload struct {
+	S3Key       string
+	CreatedAt   pgtype.Timestamp
+	UserID      uuid.UUID
+	ContentID   uuid.UUID
+	IsAudioFile bool
+}
+
+type PlayEvent struct {
+	ID             uuid.UUID
+	ReceivedAt     pgtype.Timestamp
+	PlayedAt       pgtype.Timestamp
+	UserID         uuid.UUID
+	ContentID      uuid.UUID
+	Type           PlayEventType
+	Position       int32
+	DurationPlayed int32
+	Client         string
+}
+
+type PlaybackPosition struct {
+	UserID          uuid.UUID
+	ContentID       uuid.UUID
+	Position        int32
+	Played          bool
+	ClientUpdatedAt pgtype.Timestamp
+	ModifiedAt      pgtype.Timestamp
+}
+
+type PodcastShow struct {
 	ID          uuid.UUID
 	CreatedAt   pgtype.Timestamp
 	ModifiedAt  pgtype.Timestamp
 	UserID      uuid.UUID
 	Title       string
+	Author      string
 	Description string
-	Type        ContentType
-	S3Key       pgtype.Text
+	ArtworkUrl  pgtype.Text
+	Categories  []string
+	Explicit    bool
+	FeedUrl     pgtype.Text
+	Language    string
+}
+
+type SavedAlbum struct {
+	ID        uuid.UUID
+	CreatedAt pgtype.Timestamp
+	UserID    uuid.UUID
+	AlbumID   uuid.UUID
+}
+
+type UploadSession struct {
+	ID          uuid.UUID
+	CreatedAt   pgtype.Timestamp
+	ModifiedAt  pgtype.Timestamp
+	ExpiresAt   pgtype.Timestamp
+	UserID      uuid.UUID
+	ContentID   uuid.UUID
+	S3Key       string
+	S3UploadID  string
+	IsAudioFile bool
+	Status      UploadSessionStatus
 }
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	pb "github.com/thejasmeetsingh/spotify-clone/src/services/common/contentpb"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type server struct {
+	pb.UnimplementedContentServiceServer
+	dbCfg *database.Config
+}
+
+func valid(authorization []string) bool {
+	if len(authorization) < 1 {
+		return false
+	}
+	token := strings.TrimPrefix(authorization[0], "Bearer ")
+	return token == os.Getenv("GRPC_AUTH_KEY")
+}
+
+func ensureValidToken(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "missing metadata")
+	}
+	if !valid(md["authorization"]) {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+	// Continue execution of handler after ensuring a valid token.
+	return handler(ctx, req)
+}
+
+// Start the gRPC server which is used by other services for sending events to the content service
+func serveGRPC(dbConfig *database.Config) {
+	lis, err := net.Listen("tcp", ":"+os.Getenv("GRPC_PORT"))
+	if err != nil {
+		log.Fatalln("failed to listen gRPC: ", err)
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(ensureValidToken, apierror.UnaryServerInterceptor),
+	}
+
+	grpcServer := grpc.NewServer(opts...)
+	pb.RegisterContentServiceServer(grpcServer, &server{dbCfg: dbConfig})
+
+	log.Infoln("gRPC service is up & running")
+
+	if err := grpcServer.Serve(lis); err != nil {
+		log.Fatalln("failed to serve gRPC: ", err)
+	}
+}
+
+// gRPC request handler, Called by the user service once a user account is deleted permanently
+func (s *server) UserDeleted(ctx context.Context, in *pb.UserDeletedRequest) (*pb.UserDeletedResponse, error) {
+	userID, err := uuid.Parse(in.GetUserId())
+	if err != nil {
+		return nil, apierror.InvalidArgument("Invalid user ID").WithField("user_id", "Invalid ID")
+	}
+
+	deletedContent, err := internal.DeleteUserData(s.dbCfg, ctx, userID)
+	if err != nil {
+		log.Errorln("error caught while deleting user data: ", err)
+		return nil, err
+	}
+
+	log.Infof("%d contents deleted for user %s", deletedContent, userID)
+
+	return &pb.UserDeletedResponse{DeletedContent: deletedContent}, nil
+}
+
+// gRPC request handler, Called by the user service once a user account is scheduled for deletion
+func (s *server) UserDeactivated(ctx context.Context, in *pb.UserDeactivatedRequest) (*pb.UserDeactivatedResponse, error) {
+	userID, err := uuid.Parse(in.GetUserId())
+	if err != nil {
+		return nil, apierror.InvalidArgument("Invalid user ID").WithField("user_id", "Invalid ID")
+	}
+
+	// Account can be restored, So the user is revoked until then
+	if err = internal.RevokeUser(ctx, userID, 0); err != nil {
+		log.Errorln("error caught while revoking user: ", err)
+		return nil, err
+	}
+
+	return &pb.UserDeactivatedResponse{}, nil
+}
+
+// gRPC request handler, Called by the user service once a deactivated user account is restored
+func (s *server) UserRestored(ctx context.Context, in *pb.UserRestoredRequest) (*pb.UserRestoredResponse, error) {
+	userID, err := uuid.Parse(in.GetUserId())
+	if err != nil {
+		return nil, apierror.InvalidArgument("Invalid user ID").WithField("user_id", "Invalid ID")
+	}
+
+	if err = internal.RestoreUser(ctx, userID); err != nil {
+		log.Errorln("error caught while restoring user: ", err)
+		return nil, err
+	}
+
+	return &pb.UserRestoredResponse{}, nil
+}
+
+// gRPC request handler, Called by the user service for collecting the user data for a data export request
+func (s *server) ExportUserData(ctx context.Context, in *pb.ExportUserDataRequest) (*pb.ExportUserDataResponse, error) {
+	userID, err := uuid.Parse(in.GetUserId())
+	if err != nil {
+		return nil, apierror.InvalidArgument("Invalid user ID").WithField("user_id", "Invalid ID")
+	}
+
+	dbContents, err := database.GetAllUserContentDB(s.dbCfg, ctx, userID)
+	if err != nil {
+		log.Errorln("error caught while fetching user contents for export: ", err)
+		return nil, err
+	}
+
+	contents := make([]*pb.ExportedContent, 0, len(dbContents))
+	for _, dbContent := range dbContents {
+		contents = append(contents, &pb.ExportedContent{
+			Id:          dbContent.ID.String(),
+			CreatedAt:   dbContent.CreatedAt.Time.Format(time.RFC3339),
+			ModifiedAt:  dbContent.ModifiedAt.Time.Format(time.RFC3339),
+			Title:       dbContent.Title,
+			Description: dbContent.Description,
+			Type:        string(dbContent.Type),
+			MediaKey:    dbContent.S3Key.String,
+		})
+	}
+
+	dbPlayEvents, err := database.GetAllUserPlayEventsDB(s.dbCfg, ctx, userID)
+	if err != nil {
+		log.Errorln("error caught while fetching user play events for export: ", err)
+		return nil, err
+	}
+
+	plays := make([]*pb.ExportedPlay, 0, len(dbPlayEvents))
+	for _, dbPlayEvent := range dbPlayEvents {
+		plays = append(plays, &pb.ExportedPlay{
+			ContentId:      dbPlayEvent.ContentID.String(),
+			PlayedAt:       dbPlayEvent.PlayedAt.Time.Format(time.RFC3339),
+			Type:           string(dbPlayEvent.Type),
+			Position:       dbPlayEvent.Position,
+			DurationPlayed: dbPlayEvent.DurationPlayed,
+			Client:         dbPlayEvent.Client,
+		})
+	}
+
+	dbLikes, err := database.GetAllUserLikesDB(s.dbCfg, ctx, userID)
+	if err != nil {
+		log.Errorln("error caught while fetching user likes for export: ", err)
+		return nil, err
+	}
+
+	likes := make([]*pb.ExportedLike, 0, len(dbLikes))
+	for _, dbLike := range dbLikes {
+		likes = append(likes, &pb.ExportedLike{
+			ContentId: dbLike.ContentID.String(),
+			CreatedAt: dbLike.CreatedAt.Time.Format(time.RFC3339),
+		})
+	}
+
+	dbSavedAlbums, err := database.GetAllUserSavedAlbumsDB(s.dbCfg, ctx, userID)
+	if err != nil {
+		log.Errorln("error caught while fetching user saved albums for export: ", err)
+		return nil, err
+	}
+
+	savedAlbums := make([]*pb.ExportedSavedAlbum, 0, len(dbSavedAlbums))
+	for _, dbSavedAlbum := range dbSavedAlbums {
+		savedAlbums = append(savedAlbums, &pb.ExportedSavedAlbum{
+			AlbumId:   dbSavedAlbum.AlbumID.String(),
+			CreatedAt: dbSavedAlbum.CreatedAt.Time.Format(time.RFC3339),
+		})
+	}
+
+	dbPositions, err := database.GetAllUserPlaybackPositionsDB(s.dbCfg, ctx, userID)
+	if err != nil {
+		log.Errorln("error caught while fetching user playback positions for export: ", err)
+		return nil, err
+	}
+
+	positions := make([]*pb.ExportedPlaybackPosition, 0, len(dbPositions))
+	for _, dbPosition := range dbPositions {
+		positions = append(positions, &pb.ExportedPlaybackPosition{
+			ContentId: dbPosition.ContentID.String(),
+			Position:  dbPosition.Position,
+			Played:    dbPosition.Played,
+			UpdatedAt: dbPosition.ClientUpdatedAt.Time.Format(time.RFC3339),
+		})
+	}
+
+	return &pb.ExportUserDataResponse{
+		Content:           contents,
+		Plays:             plays,
+		Likes:             likes,
+		SavedAlbums:       savedAlbums,
+		PlaybackPositions: positions,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+// Remove everything related to a deleted user account
+//
+// Contents are deleted from DB first and then their media files are removed from s3,
+// Calling it again for the same user is safe, So the user service can retry on failure.
+func DeleteUserData(dbCfg *database.Config, ctx context.Context, userID uuid.UUID) (int64, error) {
+	// Tokens of the user are rejected before anything is deleted
+	if err := RevokeUser(ctx, userID, deletedUserRevokeExp); err != nil {
+		return 0, err
+	}
+
+	dbKeys, err := database.DeleteUserContentDB(dbCfg, ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	mediaKeys, deletedContent := getDeletedMediaKeys(dbKeys)
+
+	if err = DeleteMediaFiles(ctx, mediaKeys); err != nil {
+		// Rows are already deleted, So the orphaned objects are just logged
+		log.Errorln("error caught while deleting user media files: ", err, "keys: ", mediaKeys)
+	}
+
+	return deletedContent, nil
+}
+
+// Return the media & download file keys of the deleted contents along with the number of contents
+func getDeletedMediaKeys(dbKeys []database.DeleteUserContentRow) ([]string, int64) {
+	var mediaKeys []string
+	contentIDs := make(map[uuid.UUID]bool)
+
+	for _, dbKey := range dbKeys {
+		contentIDs[dbKey.ID] = true
+		if dbKey.Key.Valid {
+			mediaKeys = append(mediaKeys, dbKey.Key.String)
+		}
+	}
+	return mediaKeys, int64(len(contentIDs))
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+func TestGetDeletedMediaKeys(t *testing.T) {
+	song, episode, draft := uuid.New(), uuid.New(), uuid.New()
+
+	key := func(contentID uuid.UUID, key string) database.DeleteUserContentRow {
+		return database.DeleteUserContentRow{ID: contentID, Key: pgtype.Text{String: key, Valid: key != ""}}
+	}
+
+	dbKeys := []database.DeleteUserContentRow{
+		key(song, "audio/"+song.String()+"_v2.m3u8"),
+		key(song, "audio/"+song.String()+"_v2.m4a"),
+		key(song, "audio/"+song.String()+"_v1.m3u8"),
+		key(song, "audio/"+song.String()+"_v1.m4a"),
+		// Download file of an imported episode is named after the enclosure, So it can't be derived from the playlist
+		key(episode, "audio/"+episode.String()+"_hls.m3u8"),
+		key(episode, "audio/"+episode.String()+"_enclosure.m4a"),
+		// Uploaded file of a version which is still being converted
+		key(episode, "audio/"+episode.String()+"_source.mp3"),
+		// Content without any media file
+		key(draft, ""),
+	}
+
+	mediaKeys, deletedContent := getDeletedMediaKeys(dbKeys)
+	if deletedContent != 3 {
+		t.Errorf("expected 3 deleted contents, got %d", deletedContent)
+	}
+
+	var objectKeys []string
+	for _, mediaKey := range mediaKeys {
+		objectKeys = append(objectKeys, getMediaObjectKeys(mediaKey)...)
+	}
+
+	for _, expected := range []string{
+		"audio/" + song.String() + "_v2.m3u8",
+		"audio/" + song.String() + "_v2.ts",
+		"audio/" + song.String() + "_v2.m4a",
+		"audio/" + song.String() + "_v1.ts",
+		"audio/" + song.String() + "_v1.m4a",
+		"audio/" + episode.String() + "_hls.m3u8",
+		"audio/" + episode.String() + "_hls.ts",
+		"audio/" + episode.String() + "_enclosure.m4a",
+		"audio/" + episode.String() + "_source.mp3",
+	} {
+		if !slices.Contains(objectKeys, expected) {
+			t.Errorf("%s is not deleted, deleted keys: %v", expected, objectKeys)
+		}
+	}
+}
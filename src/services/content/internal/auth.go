@@ -0,0 +1,177 @@
+// Verify the JWT access tokens issued by the user service locally
+//
+// Public keys are fetched from the JWKS endpoint of the user service and kept in memory,
+// So that there is no need to make a network call on every request.
+
+package internal
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	jwksCacheTTL        = 10 * time.Minute
+	jwksMinRefreshDelay = 30 * time.Second
+	accessTokenType     = "access"
+)
+
+type Claims struct {
+	Data string `json:"data"`
+	Type string `json:"type"`
+	jwt.RegisteredClaims
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	Kid string `json:"kid"`
+	X   string `json:"x"`
+}
+
+type jwksCache struct {
+	mu          sync.RWMutex
+	keys        map[string]ed25519.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+
+	// Held while fetching the JWKS, So that only one request calls the user service at a time
+	fetchMu sync.Mutex
+}
+
+var publicKeys = &jwksCache{}
+
+// Fetch the JWKS from the user service
+func fetchJWKS(ctx context.Context) (map[string]ed25519.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, os.Getenv("USER_JWKS_URL"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected JWKS response status: %d", res.StatusCode)
+	}
+
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err = json.NewDecoder(res.Body).Decode(&jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]ed25519.PublicKey)
+	for _, key := range jwks.Keys {
+		if key.Kty != "OKP" || key.Crv != "Ed25519" {
+			continue
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(key.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			continue
+		}
+		keys[key.Kid] = ed25519.PublicKey(x)
+	}
+
+	return keys, nil
+}
+
+// Return the public key for the given KID
+//
+// JWKS is re-fetched when the cache is expired or the KID is unknown (Which happens after a key rotation),
+// Fetches are at least jwksMinRefreshDelay apart and the stale keys are used meanwhile,
+// So an unreachable user service is not called on every request.
+func (c *jwksCache) get(ctx context.Context, kid string) (ed25519.PublicKey, error) {
+	c.mu.RLock()
+	key, exists := c.keys[kid]
+	age := time.Since(c.fetchedAt)
+	c.mu.RUnlock()
+
+	if exists && age < jwksCacheTTL {
+		return key, nil
+	}
+
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+
+	// Keys could be fetched by another request while waiting
+	c.mu.RLock()
+	key, exists = c.keys[kid]
+	age = time.Since(c.fetchedAt)
+	lastAttempt := time.Since(c.attemptedAt)
+	c.mu.RUnlock()
+
+	if exists && age < jwksCacheTTL {
+		return key, nil
+	}
+
+	// Avoid hammering the user service with the tokens having an unknown KID or while it's not reachable
+	if lastAttempt < jwksMinRefreshDelay {
+		if exists {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown key ID: %q", kid)
+	}
+
+	keys, err := fetchJWKS(ctx)
+
+	c.mu.Lock()
+	c.attemptedAt = time.Now()
+	if err == nil {
+		c.keys = keys
+		c.fetchedAt = c.attemptedAt
+	}
+	c.mu.Unlock()
+
+	if err != nil {
+		// Keep using the stale key if the user service is not reachable
+		if exists {
+			return key, nil
+		}
+		return nil, err
+	}
+
+	key, exists = keys[kid]
+	if !exists {
+		return nil, fmt.Errorf("unknown key ID: %q", kid)
+	}
+	return key, nil
+}
+
+// Verify the given access token and return the user ID encoded in it
+func VerifyAccessToken(ctx context.Context, tokenString string) (uuid.UUID, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, fmt.Errorf("missing key ID in token header")
+		}
+		return publicKeys.get(ctx, kid)
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}), jwt.WithExpirationRequired())
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid || claims.Type != accessTokenType {
+		return uuid.Nil, fmt.Errorf("invalid access token")
+	}
+
+	return uuid.Parse(claims.Data)
+}
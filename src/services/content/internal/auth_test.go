@@ -0,0 +1,328 @@
+package internal
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// JWKS endpoint of the user service, Keys can be rotated and the endpoint can be taken down by the tests
+type jwksServer struct {
+	mu       sync.Mutex
+	keys     map[string]ed25519.PublicKey
+	down     bool
+	requests atomic.Int32
+}
+
+func newJWKSServer(t *testing.T) *jwksServer {
+	s := &jwksServer{keys: make(map[string]ed25519.PublicKey)}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		keys := []jwk{
+			// Keys which are not Ed25519 are skipped
+			{Kty: "RSA", Kid: "rsa", X: base64.RawURLEncoding.EncodeToString(make([]byte, ed25519.PublicKeySize))},
+			{Kty: "OKP", Crv: "Ed25519", Kid: "short", X: base64.RawURLEncoding.EncodeToString([]byte("short"))},
+		}
+		for kid, key := range s.keys {
+			keys = append(keys, jwk{Kty: "OKP", Crv: "Ed25519", Kid: kid, X: base64.RawURLEncoding.EncodeToString(key)})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("USER_JWKS_URL", server.URL)
+
+	return s
+}
+
+// Generate a new signing key and serve its public key with the given KID, Replacing the rest of the keys
+func (s *jwksServer) rotate(t *testing.T, kid string) ed25519.PrivateKey {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = map[string]ed25519.PublicKey{kid: publicKey}
+	return privateKey
+}
+
+func (s *jwksServer) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.down = down
+}
+
+// Move the last fetch & attempt of the cache back in time, Instead of waiting in the tests
+func (c *jwksCache) backdate(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fetchedAt = c.fetchedAt.Add(-d)
+	c.attemptedAt = c.attemptedAt.Add(-d)
+}
+
+func TestJWKSCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cached keys", func(t *testing.T) {
+		server := newJWKSServer(t)
+		server.rotate(t, "v1")
+		cache := &jwksCache{}
+
+		for i := 0; i < 3; i++ {
+			if _, err := cache.get(ctx, "v1"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if requests := server.requests.Load(); requests != 1 {
+			t.Errorf("expected 1 JWKS request, got %d", requests)
+		}
+
+		// Invalid keys of the JWKS are not cached
+		if _, exists := cache.keys["rsa"]; exists {
+			t.Error("RSA key is cached")
+		}
+		if _, exists := cache.keys["short"]; exists {
+			t.Error("key with invalid size is cached")
+		}
+
+		// Expired cache is fetched again
+		cache.backdate(jwksCacheTTL)
+		if _, err := cache.get(ctx, "v1"); err != nil {
+			t.Fatal(err)
+		}
+		if requests := server.requests.Load(); requests != 2 {
+			t.Errorf("expected the expired JWKS to be fetched again, got %d requests", requests)
+		}
+	})
+
+	t.Run("key rotation", func(t *testing.T) {
+		server := newJWKSServer(t)
+		server.rotate(t, "v1")
+		cache := &jwksCache{}
+
+		if _, err := cache.get(ctx, "v1"); err != nil {
+			t.Fatal(err)
+		}
+
+		// New KID is not fetched until the backoff is over, Even though the cache is not expired
+		rotatedKey := server.rotate(t, "v2")
+		if _, err := cache.get(ctx, "v2"); err == nil {
+			t.Error("expected an error for the new KID within the backoff")
+		}
+		if requests := server.requests.Load(); requests != 1 {
+			t.Errorf("JWKS is fetched again within the backoff, got %d requests", requests)
+		}
+
+		cache.backdate(jwksMinRefreshDelay)
+		key, err := cache.get(ctx, "v2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !key.Equal(rotatedKey.Public()) {
+			t.Error("unexpected key for the rotated KID")
+		}
+		if requests := server.requests.Load(); requests != 2 {
+			t.Errorf("expected 2 JWKS requests, got %d", requests)
+		}
+
+		// Keys removed from the JWKS are dropped from the cache
+		if _, err := cache.get(ctx, "v1"); err == nil {
+			t.Error("expected an error for the rotated out KID")
+		}
+	})
+
+	t.Run("unknown KID backoff", func(t *testing.T) {
+		server := newJWKSServer(t)
+		server.rotate(t, "v1")
+		cache := &jwksCache{}
+
+		for i := 0; i < 5; i++ {
+			if _, err := cache.get(ctx, "unknown"); err == nil {
+				t.Fatal("expected an error for the unknown KID")
+			}
+		}
+		if requests := server.requests.Load(); requests != 1 {
+			t.Errorf("expected 1 JWKS request for the unknown KIDs, got %d", requests)
+		}
+
+		// Known keys are still served from cache
+		if _, err := cache.get(ctx, "v1"); err != nil {
+			t.Fatal(err)
+		}
+
+		cache.backdate(jwksMinRefreshDelay - time.Second)
+		cache.get(ctx, "unknown")
+		if requests := server.requests.Load(); requests != 1 {
+			t.Errorf("JWKS is fetched again before the backoff is over, got %d requests", requests)
+		}
+
+		cache.backdate(time.Second)
+		cache.get(ctx, "unknown")
+		if requests := server.requests.Load(); requests != 2 {
+			t.Errorf("expected the JWKS to be fetched again after the backoff, got %d requests", requests)
+		}
+	})
+
+	t.Run("concurrent refresh", func(t *testing.T) {
+		server := newJWKSServer(t)
+		server.rotate(t, "v1")
+		cache := &jwksCache{}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := cache.get(ctx, "v1"); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if requests := server.requests.Load(); requests != 1 {
+			t.Errorf("expected the concurrent requests to share 1 JWKS request, got %d", requests)
+		}
+	})
+
+	t.Run("user service down", func(t *testing.T) {
+		server := newJWKSServer(t)
+		server.rotate(t, "v1")
+		cache := &jwksCache{}
+
+		if _, err := cache.get(ctx, "v1"); err != nil {
+			t.Fatal(err)
+		}
+
+		// Stale key is used while the JWKS can not be fetched
+		server.setDown(true)
+		cache.backdate(jwksCacheTTL)
+		for i := 0; i < 3; i++ {
+			if _, err := cache.get(ctx, "v1"); err != nil {
+				t.Fatalf("expected the stale key to be used, got %v", err)
+			}
+		}
+		if _, err := cache.get(ctx, "unknown"); err == nil {
+			t.Error("expected an error for the unknown KID")
+		}
+		if requests := server.requests.Load(); requests != 2 {
+			t.Errorf("expected 1 failed JWKS request within the backoff, got %d", requests-1)
+		}
+
+		// Cache is refreshed once the user service is back
+		server.setDown(false)
+		cache.backdate(jwksMinRefreshDelay)
+		if _, err := cache.get(ctx, "v1"); err != nil {
+			t.Fatal(err)
+		}
+		if requests := server.requests.Load(); requests != 3 {
+			t.Errorf("expected 3 JWKS requests, got %d", requests)
+		}
+		if age := time.Since(cache.fetchedAt); age > time.Minute {
+			t.Errorf("cache is not refreshed, age: %s", age)
+		}
+	})
+
+	t.Run("user service down without keys", func(t *testing.T) {
+		server := newJWKSServer(t)
+		server.setDown(true)
+		cache := &jwksCache{}
+
+		if _, err := cache.get(ctx, "v1"); err == nil {
+			t.Error("expected an error without any cached key")
+		}
+	})
+}
+
+func TestVerifyAccessToken(t *testing.T) {
+	ctx := context.Background()
+	server := newJWKSServer(t)
+	signingKey := server.rotate(t, "v1")
+
+	cache := publicKeys
+	publicKeys = &jwksCache{}
+	t.Cleanup(func() { publicKeys = cache })
+
+	userID := uuid.New()
+	sign := func(t *testing.T, method jwt.SigningMethod, key any, kid string, claims Claims) string {
+		token := jwt.NewWithClaims(method, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+
+		signedToken, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signedToken
+	}
+	claims := func(tokenType string, expiresAt time.Time) Claims {
+		return Claims{
+			Data:             userID.String(),
+			Type:             tokenType,
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(expiresAt)},
+		}
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{name: "access token", token: sign(t, jwt.SigningMethodEdDSA, signingKey, "v1", claims(accessTokenType, expiresAt)), valid: true},
+		{name: "refresh token", token: sign(t, jwt.SigningMethodEdDSA, signingKey, "v1", claims("refresh", expiresAt))},
+		{name: "expired", token: sign(t, jwt.SigningMethodEdDSA, signingKey, "v1", claims(accessTokenType, time.Now().Add(-time.Minute)))},
+		{name: "without expiry", token: sign(t, jwt.SigningMethodEdDSA, signingKey, "v1", Claims{Data: userID.String(), Type: accessTokenType})},
+		{name: "missing KID", token: sign(t, jwt.SigningMethodEdDSA, signingKey, "", claims(accessTokenType, expiresAt))},
+		{name: "HMAC", token: sign(t, jwt.SigningMethodHS256, []byte("secret"), "v1", claims(accessTokenType, expiresAt))},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			id, err := VerifyAccessToken(ctx, test.token)
+			if test.valid && (err != nil || id != userID) {
+				t.Errorf("expected user %s, got %s: %v", userID, id, err)
+			}
+			if !test.valid && err == nil {
+				t.Error("expected the token to be rejected")
+			}
+		})
+	}
+
+	// Token signed by another key under the same KID
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyAccessToken(ctx, sign(t, jwt.SigningMethodEdDSA, otherKey, "v1", claims(accessTokenType, expiresAt))); err == nil {
+		t.Error("expected the token signed by another key to be rejected")
+	}
+}
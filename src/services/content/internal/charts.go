@@ -0,0 +1,183 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+const (
+	chartsInterval  = 10 * time.Minute
+	chartsCacheTime = 2 * chartsInterval
+	chartSize       = 50
+	playCountsJob   = "play_counts"
+
+	// Plays shorter than this (In seconds) are not counted
+	minPlayDuration = 30
+
+	// Play events are flushed from the buffer with a delay, So the recent ones are processed in the next run
+	playEventsSettleTime = 5 * time.Minute
+)
+
+// Content listed in a chart along with its number of plays
+type ChartEntry struct {
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title"`
+	Type  string    `json:"type"`
+	Url   *string   `json:"url"`
+	Plays int64     `json:"plays"`
+}
+
+func getChartKey(name string) string {
+	return "charts:" + name
+}
+
+func databaseChartToChart(dbContents []database.GetTopContentRow) []ChartEntry {
+	entries := make([]ChartEntry, 0, len(dbContents))
+
+	for _, dbContent := range dbContents {
+		entry := ChartEntry{
+			ID:    dbContent.ID,
+			Title: dbContent.Title,
+			Type:  string(dbContent.Type),
+			Plays: dbContent.Plays,
+		}
+
+		if len(dbContent.S3Key.String) != 0 {
+			url := os.Getenv("AWS_CDN_BASE_URL") + "/" + dbContent.S3Key.String
+			entry.Url = &url
+		}
+
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func loadTrendingChart(dbCfg *database.Config, ctx context.Context) ([]ChartEntry, error) {
+	dbContents, err := database.GetTrendingContentDB(dbCfg, ctx, chartSize)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]database.GetTopContentRow, 0, len(dbContents))
+	for _, dbContent := range dbContents {
+		rows = append(rows, database.GetTopContentRow(dbContent))
+	}
+	return databaseChartToChart(rows), nil
+}
+
+func loadTopChart(dbCfg *database.Config, ctx context.Context, contentType database.ContentType) ([]ChartEntry, error) {
+	dbContents, err := database.GetTopContentDB(dbCfg, ctx, database.GetTopContentParams{
+		Type:  contentType,
+		Limit: chartSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return databaseChartToChart(dbContents), nil
+}
+
+func cacheChart(ctx context.Context, conn *redis.Client, name string, entries []ChartEntry) error {
+	chartByte, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return conn.Set(ctx, getChartKey(name), chartByte, chartsCacheTime).Err()
+}
+
+// Return the cached chart, Chart is loaded from DB and cached if it's not present in cache
+func getChart(ctx context.Context, name string, load func() ([]ChartEntry, error)) ([]ChartEntry, error) {
+	conn := getConn()
+	defer conn.Close()
+
+	chartByte, err := conn.Get(ctx, getChartKey(name)).Bytes()
+	if err == nil {
+		var entries []ChartEntry
+		if err = json.Unmarshal(chartByte, &entries); err == nil {
+			return entries, nil
+		}
+	}
+
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Errorln("error caught while reading cached chart: ", err)
+	}
+
+	entries, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cacheChart(ctx, conn, name, entries); err != nil {
+		log.Errorln("error caught while caching chart: ", err)
+	}
+	return entries, nil
+}
+
+// Return the most played contents of the last week
+func GetTrendingChart(dbCfg *database.Config, ctx context.Context) ([]ChartEntry, error) {
+	return getChart(ctx, "trending", func() ([]ChartEntry, error) {
+		return loadTrendingChart(dbCfg, ctx)
+	})
+}
+
+// Return the most played contents of all time of the given type
+func GetTopChart(dbCfg *database.Config, ctx context.Context, contentType database.ContentType) ([]ChartEntry, error) {
+	return getChart(ctx, "top:"+string(contentType), func() ([]ChartEntry, error) {
+		return loadTopChart(dbCfg, ctx, contentType)
+	})
+}
+
+// Update the play counts and cache the charts computed from them
+func refreshCharts(dbCfg *database.Config, ctx context.Context) {
+	processedUntil := time.Now().UTC().Add(-playEventsSettleTime)
+
+	if err := database.UpdatePlayCountsDB(dbCfg, ctx, playCountsJob, processedUntil, minPlayDuration); err != nil {
+		log.Errorln("error caught while updating play counts: ", err)
+		return
+	}
+
+	conn := getConn()
+	defer conn.Close()
+
+	charts := map[string]func() ([]ChartEntry, error){
+		"trending": func() ([]ChartEntry, error) {
+			return loadTrendingChart(dbCfg, ctx)
+		},
+		"top:" + string(database.ContentTypeM): func() ([]ChartEntry, error) {
+			return loadTopChart(dbCfg, ctx, database.ContentTypeM)
+		},
+		"top:" + string(database.ContentTypeP): func() ([]ChartEntry, error) {
+			return loadTopChart(dbCfg, ctx, database.ContentTypeP)
+		},
+	}
+
+	for name, load := range charts {
+		entries, err := load()
+		if err != nil {
+			log.Errorln("error caught while computing chart: ", err, "chart: ", name)
+			continue
+		}
+
+		if err := cacheChart(ctx, conn, name, entries); err != nil {
+			log.Errorln("error caught while caching chart: ", err, "chart: ", name)
+		}
+	}
+}
+
+// Refresh the play counts & charts periodically, Should be started in background
+func RefreshCharts(dbCfg *database.Config) {
+	ticker := time.NewTicker(chartsInterval)
+	defer ticker.Stop()
+
+	for {
+		refreshCharts(dbCfg, context.Background())
+		<-ticker.C
+	}
+}
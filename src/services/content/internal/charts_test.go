@@ -0,0 +1,286 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+// Play events & counts of the fake DB
+type playStore struct {
+	mu         sync.Mutex
+	contents   []database.Content
+	events     []database.PlayEvent
+	counts     map[uuid.UUID]*database.ContentPlayCount
+	checkpoint *pgtype.Timestamp
+}
+
+// Mirror of the plays counted by the play count queries,
+// Short plays and the plays of a content by the same user within 30 minutes of their previous play are skipped.
+func (s *playStore) countPlays(from, until time.Time, minDuration int32) map[uuid.UUID]int64 {
+	counts := make(map[uuid.UUID]int64)
+
+	for _, play := range s.events {
+		if play.ReceivedAt.Time.Before(from) || !play.ReceivedAt.Time.Before(until) || play.DurationPlayed < minDuration {
+			continue
+		}
+
+		repeated := slices.ContainsFunc(s.events, func(previous database.PlayEvent) bool {
+			return previous.UserID == play.UserID && previous.ContentID == play.ContentID &&
+				previous.DurationPlayed >= minDuration &&
+				!previous.PlayedAt.Time.Before(play.PlayedAt.Time.Add(-30*time.Minute)) &&
+				(previous.PlayedAt.Time.Before(play.PlayedAt.Time) ||
+					(previous.PlayedAt.Time.Equal(play.PlayedAt.Time) && previous.ID.String() < play.ID.String()))
+		})
+		if !repeated {
+			counts[play.ContentID]++
+		}
+	}
+	return counts
+}
+
+func (s *playStore) register(db *fakeDB) {
+	db.on("AddJobCheckpoint", func(args []any) ([][]any, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.checkpoint == nil {
+			checkpoint := args[1].(pgtype.Timestamp)
+			s.checkpoint = &checkpoint
+		}
+		return nil, nil
+	})
+	db.on("GetJobCheckpoint", func(args []any) ([][]any, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		return [][]any{{*s.checkpoint}}, nil
+	})
+	db.on("UpdateJobCheckpoint", func(args []any) ([][]any, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		checkpoint := args[0].(pgtype.Timestamp)
+		s.checkpoint = &checkpoint
+		return nil, nil
+	})
+	db.on("AddPlayCounts", func(args []any) ([][]any, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		from, until := args[1].(pgtype.Timestamp), args[2].(pgtype.Timestamp)
+		for contentID, plays := range s.countPlays(from.Time, until.Time, args[3].(int32)) {
+			if _, exists := s.counts[contentID]; !exists {
+				s.counts[contentID] = &database.ContentPlayCount{ContentID: contentID}
+			}
+			s.counts[contentID].TotalPlays += plays
+		}
+		return nil, nil
+	})
+	db.on("RefreshWeeklyPlayCounts", func(args []any) ([][]any, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		weekStart, until := args[0].(pgtype.Timestamp), args[1].(pgtype.Timestamp)
+		weekly := s.countPlays(weekStart.Time, until.Time, args[2].(int32))
+		for contentID, count := range s.counts {
+			count.WeeklyPlays = weekly[contentID]
+		}
+		return nil, nil
+	})
+
+	// Contents sorted by the given plays, Contents without any play are skipped
+	chart := func(plays func(count *database.ContentPlayCount) int64, contentType *database.ContentType) [][]any {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		var rows []database.GetTopContentRow
+		for _, content := range s.contents {
+			count, exists := s.counts[content.ID]
+			if !exists || plays(count) == 0 || (contentType != nil && content.Type != *contentType) {
+				continue
+			}
+			rows = append(rows, database.GetTopContentRow{ID: content.ID, Title: content.Title, Type: content.Type, S3Key: content.S3Key, Plays: plays(count)})
+		}
+
+		slices.SortFunc(rows, func(a, b database.GetTopContentRow) int {
+			if a.Plays != b.Plays {
+				return int(b.Plays - a.Plays)
+			}
+			return strings.Compare(a.ID.String(), b.ID.String())
+		})
+
+		result := make([][]any, 0, len(rows))
+		for _, row := range rows {
+			result = append(result, rowOf(row))
+		}
+		return result
+	}
+	db.on("GetTrendingContent", func(args []any) ([][]any, error) {
+		return chart(func(count *database.ContentPlayCount) int64 { return count.WeeklyPlays }, nil), nil
+	})
+	db.on("GetTopContent", func(args []any) ([][]any, error) {
+		contentType := args[0].(database.ContentType)
+		return chart(func(count *database.ContentPlayCount) int64 { return count.TotalPlays }, &contentType), nil
+	})
+}
+
+func TestRefreshCharts(t *testing.T) {
+	m := miniredis.RunT(t)
+	t.Setenv("REDIS_HOST", m.Addr())
+	t.Setenv("AWS_CDN_BASE_URL", "https://cdn.example.com")
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	song := database.Content{ID: uuid.New(), Title: "Song", Type: database.ContentTypeM, S3Key: pgtype.Text{String: "audio/song.m3u8", Valid: true}}
+	otherSong := database.Content{ID: uuid.New(), Title: "Other song", Type: database.ContentTypeM}
+	episode := database.Content{ID: uuid.New(), Title: "Episode", Type: database.ContentTypeP}
+	unplayed := database.Content{ID: uuid.New(), Title: "Unplayed", Type: database.ContentTypeM}
+
+	listener, otherListener := uuid.New(), uuid.New()
+	play := func(userID uuid.UUID, content database.Content, duration int32, ago time.Duration) database.PlayEvent {
+		at := pgtype.Timestamp{Time: now.Add(-ago), Valid: true}
+		return database.PlayEvent{ID: uuid.New(), ReceivedAt: at, PlayedAt: at, UserID: userID, ContentID: content.ID, Type: database.PlayEventTypeE, DurationPlayed: duration}
+	}
+
+	store := &playStore{
+		contents: []database.Content{song, otherSong, episode, unplayed},
+		counts:   make(map[uuid.UUID]*database.ContentPlayCount),
+		events: []database.PlayEvent{
+			play(listener, song, 120, 2*time.Hour),
+			// Replayed within 30 minutes
+			play(listener, song, 120, 2*time.Hour-10*time.Minute),
+			// Replayed after 30 minutes of the previous play
+			play(listener, song, 120, 2*time.Hour-45*time.Minute),
+			// Skipped before the minimum duration
+			play(otherListener, song, minPlayDuration-1, time.Hour),
+			play(otherListener, otherSong, 60, time.Hour),
+			play(listener, episode, 300, 30*time.Minute),
+			// Older than a week, Counted only in the all-time plays
+			play(otherListener, episode, 300, 8*24*time.Hour),
+			// Not flushed from the buffer for sure yet, Processed in a later run
+			play(otherListener, unplayed, 60, time.Minute),
+		},
+	}
+
+	db := newFakeDB()
+	store.register(db)
+	dbCfg := &database.Config{DB: db, Queries: database.New(db)}
+
+	refreshCharts(dbCfg, ctx)
+
+	expectedTotals := map[uuid.UUID]int64{song.ID: 2, otherSong.ID: 1, episode.ID: 2}
+	expectedWeekly := map[uuid.UUID]int64{song.ID: 2, otherSong.ID: 1, episode.ID: 1}
+
+	checkCounts := func(t *testing.T) {
+		for _, content := range store.contents {
+			count, exists := store.counts[content.ID]
+			if !exists {
+				count = &database.ContentPlayCount{}
+			}
+			if count.TotalPlays != expectedTotals[content.ID] || count.WeeklyPlays != expectedWeekly[content.ID] {
+				t.Errorf("%s: expected %d total & %d weekly plays, got %d & %d", content.Title,
+					expectedTotals[content.ID], expectedWeekly[content.ID], count.TotalPlays, count.WeeklyPlays)
+			}
+		}
+	}
+	checkCounts(t)
+
+	if !store.checkpoint.Time.Before(now.Add(-playEventsSettleTime).Add(time.Second)) {
+		t.Errorf("recent play events are processed, checkpoint: %s", store.checkpoint.Time)
+	}
+
+	// Charts are cached with the contents ordered by their plays
+	cachedChart := func(t *testing.T, name string) []ChartEntry {
+		value, err := m.Get(getChartKey(name))
+		if err != nil {
+			t.Fatalf("%s chart is not cached: %v", name, err)
+		}
+
+		var entries []ChartEntry
+		if err := json.Unmarshal([]byte(value), &entries); err != nil {
+			t.Fatal(err)
+		}
+		return entries
+	}
+	chartIDs := func(entries []ChartEntry) []uuid.UUID {
+		ids := make([]uuid.UUID, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+		return ids
+	}
+
+	trending := cachedChart(t, "trending")
+	if trending[0].ID != song.ID || trending[0].Plays != 2 || len(trending) != 3 {
+		t.Errorf("unexpected trending chart: %+v", trending)
+	}
+	if trending[0].Url == nil || *trending[0].Url != "https://cdn.example.com/audio/song.m3u8" {
+		t.Errorf("unexpected URL of the trending content: %v", trending[0].Url)
+	}
+
+	if ids := chartIDs(cachedChart(t, "top:M")); !slices.Equal(ids, []uuid.UUID{song.ID, otherSong.ID}) {
+		t.Errorf("unexpected top songs: %v", ids)
+	}
+	if ids := chartIDs(cachedChart(t, "top:P")); !slices.Equal(ids, []uuid.UUID{episode.ID}) {
+		t.Errorf("unexpected top episodes: %v", ids)
+	}
+
+	// Plays which are already counted are not counted again by the next run
+	refreshCharts(dbCfg, ctx)
+	checkCounts(t)
+
+	if commits := db.commits(); commits != 2 {
+		t.Errorf("expected 2 committed runs, got %d", commits)
+	}
+	if sql := db.sql("AddPlayCounts"); !strings.Contains(sql, "INTERVAL '30 minutes'") {
+		t.Errorf("repeated plays are not skipped by the query: %s", sql)
+	}
+}
+
+func TestGetChart(t *testing.T) {
+	m := miniredis.RunT(t)
+	t.Setenv("REDIS_HOST", m.Addr())
+
+	ctx := context.Background()
+	entries := []ChartEntry{{ID: uuid.New(), Title: "Song", Type: string(database.ContentTypeM), Plays: 10}}
+
+	loads := 0
+	load := func() ([]ChartEntry, error) {
+		loads++
+		return entries, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		chart, err := getChart(ctx, "trending", load)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(chart) != 1 || chart[0].ID != entries[0].ID {
+			t.Fatalf("unexpected chart: %+v", chart)
+		}
+	}
+
+	// Chart is loaded once and then served from cache till it expires
+	if loads != 1 {
+		t.Errorf("expected the chart to be loaded once, got %d", loads)
+	}
+	if ttl := m.TTL(getChartKey("trending")); ttl != chartsCacheTime {
+		t.Errorf("expected the chart to be cached for %s, got %s", chartsCacheTime, ttl)
+	}
+
+	m.FastForward(chartsCacheTime)
+	if _, err := getChart(ctx, "trending", load); err != nil || loads != 2 {
+		t.Errorf("expired chart is not loaded again, loads: %d, error: %v", loads, err)
+	}
+}
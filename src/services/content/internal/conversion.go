@@ -2,37 +2,99 @@ package internal
 
 import (
 	"context"
+	"os"
+	"strconv"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	log "github.com/sirupsen/logrus"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
 )
 
-func UpdateContentS3Key(
-	dbCfg *database.Config,
-	ctx context.Context,
-	params database.UpdateS3KeyParams,
-	key string,
-	isAudioFile bool,
-) {
+const defaultMediaVersionsToKeep = 3
+
+// Return how many ready media versions of a content are kept for rollback
+func getMediaVersionsToKeep() int32 {
+	count, err := strconv.Atoi(os.Getenv("MEDIA_VERSIONS_TO_KEEP"))
+	if err != nil || count <= 0 {
+		count = defaultMediaVersionsToKeep
+	}
+	return int32(count)
+}
+
+// Convert the uploaded file of a media version and switch the content to it once the conversion is done,
+// Content keeps serving its current version until then and if the conversion fails.
+func ConvertMediaVersion(dbCfg *database.Config, ctx context.Context, version *database.ContentMediaVersion, isAudioFile bool) {
 	// Process the media file and retrieve the new s3 key
-	grpcResponse, err := processContentMedia(key, isAudioFile)
+	grpcResponse, err := processContentMedia(version.SourceKey, isAudioFile)
 	if err != nil {
 		log.Errorln("error caught in conversion gRPC response: ", err)
+
+		if err = database.FailMediaVersionDB(dbCfg, ctx, database.MarkMediaVersionFailedParams{
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			ID: version.ID,
+		}); err != nil {
+			log.Errorln("error caught while marking media version as failed: ", err)
+		}
 		return
 	}
 
-	// Update s3 key
-	params.S3Key = pgtype.Text{
-		String: grpcResponse.GetKey(),
-		Valid:  true,
+	// Details of the generated files, Download file is only generated for audio files
+	switched, err := database.CompleteMediaVersionDB(dbCfg, ctx, database.MarkMediaVersionReadyParams{
+		S3Key: pgtype.Text{
+			String: grpcResponse.GetKey(),
+			Valid:  true,
+		},
+		DownloadKey: pgtype.Text{
+			String: grpcResponse.GetDownloadKey(),
+			Valid:  grpcResponse.GetDownloadKey() != "",
+		},
+		DownloadSize: pgtype.Int8{
+			Int64: grpcResponse.GetDownloadSize(),
+			Valid: grpcResponse.GetDownloadKey() != "",
+		},
+		Duration: pgtype.Int4{
+			Int32: grpcResponse.GetDuration(),
+			Valid: grpcResponse.GetDuration() > 0,
+		},
+		ModifiedAt: pgtype.Timestamp{
+			Time:  time.Now().UTC(),
+			Valid: true,
+		},
+		ID: version.ID,
+	})
+	if err != nil {
+		log.Errorln("error caught while updating content media version: ", err)
+		return
 	}
 
-	// Save the updated key in DB
-	if err = database.UpdateContentS3KeyDB(dbCfg, ctx, params); err != nil {
-		log.Errorln("error caught while updating content s3 key: ", err)
+	if !switched {
+		log.Infoln("Content is serving a newer media version, Version is kept for rollback: ", version.ID)
+	} else {
+		log.Infoln("Content s3 key updated successfully")
+	}
+
+	cleanupMediaVersions(dbCfg, ctx, version.ContentID)
+}
+
+// Delete the superseded media versions of a content along with their files,
+// Current version and the latest ready versions are kept so the creator can roll back.
+func cleanupMediaVersions(dbCfg *database.Config, ctx context.Context, contentID uuid.UUID) {
+	keys, err := database.DeleteSupersededMediaVersionsDB(dbCfg, ctx, database.DeleteSupersededMediaVersionsParams{
+		ContentID: contentID,
+		Keep:      getMediaVersionsToKeep(),
+	})
+	if err != nil {
+		log.Errorln("error caught while deleting superseded media versions: ", err)
 		return
 	}
 
-	log.Infoln("Content s3 key updated successfully")
+	if err = DeleteMediaFiles(ctx, keys); err != nil {
+		// Versions are already deleted, So the orphaned objects are just logged
+		log.Errorln("error caught while deleting superseded media files: ", err, "keys: ", keys)
+	}
 }
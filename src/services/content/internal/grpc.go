@@ -15,21 +15,21 @@ import (
 var userAddr = flag.String("userAddr", os.Getenv("USER_GRPC_ADDRESS"), "user gRPC server address")
 var conversionAddr = flag.String("conversionAddr", os.Getenv("CONVERSION_GRPC_ADDRESS"), "conversion gRPC server address")
 
-// gRPC to user service to fetch user details
-func fetchUserDetail(token string) (*userPB.UserDetailResponse, error) {
+// gRPC to conversion service and process the uploaded file
+func processContentMedia(key string, isAudioFile bool) (*conversionPB.ConversionResponse, error) {
 	flag.Parse()
 
-	conn, err := grpc.Dial(*userAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(*conversionAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		return nil, err
 	}
 
-	c := userPB.NewUserServiceClient(conn)
+	c := conversionPB.NewConversionServiceClient(conn)
 	md := metadata.Pairs("authorization", "Bearer "+os.Getenv("GRPC_AUTH_KEY"))
 	ctx := metadata.NewOutgoingContext(context.Background(), md)
 
 	var header, trailer metadata.MD
-	r, err := c.UserDetail(ctx, &userPB.UserDetailRequest{Token: token}, grpc.Header(&header), grpc.Trailer(&trailer))
+	r, err := c.Conversion(ctx, &conversionPB.ConversionRequest{Key: key, IsAudioFile: isAudioFile}, grpc.Header(&header), grpc.Trailer(&trailer))
 	if err != nil {
 		return nil, err
 	}
@@ -37,21 +37,22 @@ func fetchUserDetail(token string) (*userPB.UserDetailResponse, error) {
 	return r, nil
 }
 
-// gRPC to conversion service and process the uploaded file
-func processContentMedia(key string, isAudioFile bool) (*conversionPB.ConversionResponse, error) {
+// gRPC to user service to fetch public details of the given users
+func fetchUsersDetail(userIDs []string) (*userPB.UsersDetailResponse, error) {
 	flag.Parse()
 
-	conn, err := grpc.Dial(*conversionAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(*userAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		return nil, err
 	}
+	defer conn.Close()
 
-	c := conversionPB.NewConversionServiceClient(conn)
+	c := userPB.NewUserServiceClient(conn)
 	md := metadata.Pairs("authorization", "Bearer "+os.Getenv("GRPC_AUTH_KEY"))
 	ctx := metadata.NewOutgoingContext(context.Background(), md)
 
 	var header, trailer metadata.MD
-	r, err := c.Conversion(ctx, &conversionPB.ConversionRequest{Key: key, IsAudioFile: isAudioFile}, grpc.Header(&header), grpc.Trailer(&trailer))
+	r, err := c.UsersDetail(ctx, &userPB.UsersDetailRequest{Ids: userIDs}, grpc.Header(&header), grpc.Trailer(&trailer))
 	if err != nil {
 		return nil, err
 	}
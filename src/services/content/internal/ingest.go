@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+// Parse the content ID from the key of an uploaded media file
+//
+// Uploaded files are stored as audio|video/<content ID>.<ext>, Or with a unique suffix as audio|video/<content ID>_<suffix>.<ext>
+func ParseMediaKey(key string) (uuid.UUID, bool, bool) {
+	for _, prefix := range []string{"audio/", "video/"} {
+		filename, ok := strings.CutPrefix(key, prefix)
+		if !ok || strings.Contains(filename, "/") {
+			continue
+		}
+
+		ext := path.Ext(filename)
+		if ext == "" {
+			return uuid.Nil, false, false
+		}
+
+		name, _, _ := strings.Cut(strings.TrimSuffix(filename, ext), "_")
+		contentID, err := uuid.Parse(name)
+		if err != nil {
+			return uuid.Nil, false, false
+		}
+		return contentID, prefix == "audio/", true
+	}
+	return uuid.Nil, false, false
+}
+
+// Add a media version for an uploaded file of the content and convert it in background,
+// If the file was already reported its existing version is returned along with false.
+//
+// User ID is optional, Content is not checked for its owner if it's not given.
+func AddUploadedMedia(
+	dbCfg *database.Config,
+	ctx context.Context,
+	contentID uuid.UUID,
+	userID pgtype.UUID,
+	key string,
+	isAudioFile bool,
+) (*database.ContentMediaVersion, bool, error) {
+	version, err := database.AddMediaVersionDB(dbCfg, ctx, database.AddMediaVersionParams{
+		ID: uuid.New(),
+		CreatedAt: pgtype.Timestamp{
+			Time:  time.Now().UTC(),
+			Valid: true,
+		},
+		SourceKey: key,
+		ContentID: contentID,
+		UserID:    userID,
+	})
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Either the file was already reported or the content doesn't exist
+		version, err = database.GetMediaVersionBySourceKeyDB(dbCfg, ctx, database.GetMediaVersionBySourceKeyParams{
+			ContentID: contentID,
+			SourceKey: key,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		return version, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	go ConvertMediaVersion(dbCfg, context.Background(), version, isAudioFile)
+	return version, true, nil
+}
+
+// Trigger the conversion of a file reported by the storage notifications, Returns weather or not the conversion was triggered
+//
+// Only the keys issued to the owner of the content by a pre-signed URL or a multipart upload are ingested,
+// Files generated by the conversion service, Unknown keys and the ones already reported are skipped.
+func IngestUploadedMedia(dbCfg *database.Config, ctx context.Context, key string) (bool, error) {
+	upload, err := database.GetIssuedUploadDB(dbCfg, ctx, key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	_, created, err := AddUploadedMedia(dbCfg, ctx, upload.ContentID, pgtype.UUID{
+		Bytes: upload.UserID,
+		Valid: true,
+	}, key, upload.IsAudioFile)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Content is deleted
+		return false, nil
+	}
+	return created, err
+}
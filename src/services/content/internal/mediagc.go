@@ -0,0 +1,186 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+const (
+	mediaGCInterval          = 24 * time.Hour
+	defaultMediaGCGraceHours = 24
+)
+
+// Bucket prefixes under which the media files of the contents are stored
+var mediaPrefixes = []string{"audio/", "video/"}
+
+// Object which is not referenced by any content
+type OrphanedObject struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// Result of a garbage collector run
+type MediaGCReport struct {
+	DryRun         bool             `json:"dry_run"`
+	ScannedObjects int              `json:"scanned_objects"`
+	OrphanedBytes  int64            `json:"orphaned_bytes"`
+	Orphaned       []OrphanedObject `json:"orphaned"`
+	DeletedObjects int              `json:"deleted_objects"`
+}
+
+// Return for how long an unreferenced object is kept, So that the uploads & conversions in progress are not removed
+func getMediaGCGracePeriod() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("MEDIA_GC_GRACE_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = defaultMediaGCGraceHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// Check weather or not the garbage collector should only report the orphaned objects without deleting them
+func isMediaGCDryRun() bool {
+	dryRun, err := strconv.ParseBool(os.Getenv("MEDIA_GC_DRY_RUN"))
+	return err == nil && dryRun
+}
+
+// Return all the s3 object keys which are referenced by the contents
+//
+// Uploaded files of the media versions which failed before the cutoff are not referenced anymore,
+// Failed versions are never served or converted again.
+func getReferencedObjectKeys(dbCfg *database.Config, ctx context.Context, cutoff time.Time) (map[string]bool, error) {
+	dbKeys, err := database.GetMediaKeysDB(dbCfg, ctx, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := make(map[string]bool)
+	for _, dbKey := range dbKeys {
+		if dbKey.S3Key.Valid {
+			for _, key := range getMediaObjectKeys(dbKey.S3Key.String) {
+				referenced[key] = true
+			}
+		}
+
+		if dbKey.DownloadKey.Valid {
+			referenced[dbKey.DownloadKey.String] = true
+		}
+	}
+	return referenced, nil
+}
+
+// Find the media objects which are not referenced by any content and are older than the grace period,
+// Orphaned objects are deleted unless it's a dry run.
+func collectOrphanedMedia(dbCfg *database.Config, ctx context.Context, gracePeriod time.Duration, dryRun bool) (*MediaGCReport, error) {
+	client, err := getS3Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MediaGCReport{
+		DryRun:   dryRun,
+		Orphaned: []OrphanedObject{},
+	}
+	cutoff := time.Now().UTC().Add(-gracePeriod)
+
+	// Objects are listed before loading the DB keys,
+	// So an object which gets referenced in between is either in the DB keys or is newer than the cutoff.
+	var candidates []OrphanedObject
+	for _, prefix := range mediaPrefixes {
+		paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+			Bucket: aws.String(os.Getenv("AWS_BUCKET_NAME")),
+			Prefix: aws.String(prefix),
+		})
+
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, object := range page.Contents {
+				report.ScannedObjects++
+
+				if object.LastModified == nil || !object.LastModified.Before(cutoff) {
+					continue
+				}
+
+				candidates = append(candidates, OrphanedObject{
+					Key:          aws.ToString(object.Key),
+					Size:         aws.ToInt64(object.Size),
+					LastModified: *object.LastModified,
+				})
+			}
+		}
+	}
+
+	referenced, err := getReferencedObjectKeys(dbCfg, ctx, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, candidate := range candidates {
+		if referenced[candidate.Key] {
+			continue
+		}
+
+		report.Orphaned = append(report.Orphaned, candidate)
+		report.OrphanedBytes += candidate.Size
+		keys = append(keys, candidate.Key)
+	}
+
+	if dryRun || len(keys) == 0 {
+		return report, nil
+	}
+
+	if err = deleteObjects(ctx, client, keys); err != nil {
+		return report, err
+	}
+	report.DeletedObjects = len(keys)
+
+	return report, nil
+}
+
+// Log the orphaned objects found in a garbage collector run
+func logMediaGCReport(report *MediaGCReport) {
+	for _, object := range report.Orphaned {
+		log.Infoln("orphaned media object: ", object.Key, "size: ", object.Size, "last modified: ", object.LastModified, "dry run: ", report.DryRun)
+	}
+
+	log.Infof(
+		"orphaned media collection finished, scanned: %d orphaned: %d (%d bytes) deleted: %d dry run: %t",
+		report.ScannedObjects,
+		len(report.Orphaned),
+		report.OrphanedBytes,
+		report.DeletedObjects,
+		report.DryRun,
+	)
+}
+
+// Remove the orphaned media objects periodically, Should be started in background
+//
+// MEDIA_GC_DRY_RUN only reports the orphaned objects, MEDIA_GC_GRACE_HOURS sets how old an object should be to get removed.
+func CollectOrphanedMedia(dbCfg *database.Config) {
+	ticker := time.NewTicker(mediaGCInterval)
+	defer ticker.Stop()
+
+	for {
+		report, err := collectOrphanedMedia(dbCfg, context.Background(), getMediaGCGracePeriod(), isMediaGCDryRun())
+		if err != nil {
+			log.Errorln("error caught while collecting orphaned media: ", err)
+		}
+
+		if report != nil {
+			logMediaGCReport(report)
+		}
+		<-ticker.C
+	}
+}
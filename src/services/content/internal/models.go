@@ -6,22 +6,28 @@ import (
 	"github.com/google/uuid"
 )
 
+// User authenticated by the access token
 type User struct {
-	ID    uuid.UUID `json:"id"`
-	Name  string    `json:"name"`
-	Email string    `json:"email"`
+	ID uuid.UUID `json:"id"`
 }
 
-func UserToByte(user User) ([]byte, error) {
-	return json.Marshal(user)
+// Public details of the user who posted a content
+type Creator struct {
+	ID     uuid.UUID         `json:"id"`
+	Name   string            `json:"name"`
+	Avatar map[string]string `json:"avatar"`
 }
 
-func ByteToUser(userByte []byte) (*User, error) {
-	var user User
+func CreatorToByte(creator Creator) ([]byte, error) {
+	return json.Marshal(creator)
+}
+
+func ByteToCreator(creatorByte []byte) (*Creator, error) {
+	var creator Creator
 
-	err := json.Unmarshal(userByte, &user)
+	err := json.Unmarshal(creatorByte, &creator)
 	if err != nil {
 		return nil, err
 	}
-	return &user, nil
+	return &creator, nil
 }
@@ -0,0 +1,215 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redis/go-redis/v9"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+const (
+	playEventsKey      = "play_events"
+	playFlushInterval  = 5 * time.Second
+	playFlushBatchSize = 1000
+
+	// Batch of events being flushed, Kept until it's added into DB so that an interrupted flush is retried
+	playEventsProcessingKey = "play_events:processing"
+
+	// Only one instance flushes at a time since the processing list is shared,
+	// Lock expires by itself if the instance holding it stops in between.
+	playFlushLockKey = "play_events:flush_lock"
+	playFlushLockTTL = time.Minute
+)
+
+// Play event reported by a client, Events are buffered in redis until they are flushed into DB
+type PlayEvent struct {
+	UserID         uuid.UUID              `json:"user_id"`
+	ContentID      uuid.UUID              `json:"content_id"`
+	Type           database.PlayEventType `json:"type"`
+	Position       int32                  `json:"position"`
+	DurationPlayed int32                  `json:"duration_played"`
+	Client         string                 `json:"client"`
+	PlayedAt       time.Time              `json:"played_at"`
+	ReceivedAt     time.Time              `json:"received_at"`
+}
+
+// Month of the latest play events partition created by this instance
+var playEventsPartitionMonth string
+
+// Release the flush lock only if it's still held by the given token
+var releasePlayFlushLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Add the given play events into the redis buffer
+func BufferPlayEvents(ctx context.Context, events []PlayEvent) error {
+	values := make([]interface{}, 0, len(events))
+
+	for _, event := range events {
+		eventByte, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		values = append(values, eventByte)
+	}
+
+	conn := getConn()
+	defer conn.Close()
+
+	return conn.RPush(ctx, playEventsKey, values...).Err()
+}
+
+// Create the partitions of current and next month, So that the flushed events never land in the default partition
+func ensurePlayEventsPartitions(dbCfg *database.Config, ctx context.Context) error {
+	currentTime := time.Now().UTC()
+	month := currentTime.Format("2006-01")
+
+	if month == playEventsPartitionMonth {
+		return nil
+	}
+
+	monthStart := time.Date(currentTime.Year(), currentTime.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for _, start := range []time.Time{monthStart, monthStart.AddDate(0, 1, 0)} {
+		if err := database.CreatePlayEventsPartitionDB(dbCfg, ctx, start); err != nil {
+			return err
+		}
+	}
+
+	playEventsPartitionMonth = month
+	return nil
+}
+
+// Return the batch of play events which should be added into DB
+//
+// Events left in the processing list by an interrupted flush are returned again,
+// Otherwise the next batch is moved from the buffer into the processing list.
+func claimPlayEvents(ctx context.Context, conn *redis.Client) ([]string, error) {
+	values, err := conn.LRange(ctx, playEventsProcessingKey, 0, -1).Result()
+	if err != nil || len(values) > 0 {
+		return values, err
+	}
+
+	count, err := conn.LLen(ctx, playEventsKey).Result()
+	if err != nil || count == 0 {
+		return nil, err
+	}
+
+	pipe := conn.Pipeline()
+	for i := int64(0); i < min(count, playFlushBatchSize); i++ {
+		pipe.LMove(ctx, playEventsKey, playEventsProcessingKey, "LEFT", "RIGHT")
+	}
+
+	// Moves return nil once the buffer is empty
+	cmds, err := pipe.Exec(ctx)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	for _, cmd := range cmds {
+		if value, err := cmd.(*redis.StringCmd).Result(); err == nil {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// Move the buffered play events from redis into DB in batches
+//
+// Events of a batch are removed from redis only after they are committed into DB,
+// A failed batch stays in the processing list and is retried in the next run.
+func flushPlayEvents(dbCfg *database.Config, ctx context.Context, conn *redis.Client) {
+	token := uuid.NewString()
+
+	locked, err := conn.SetNX(ctx, playFlushLockKey, token, playFlushLockTTL).Result()
+	if err != nil {
+		log.Errorln("error caught while acquiring play events flush lock: ", err)
+		return
+	} else if !locked {
+		return
+	}
+	defer func() {
+		if err := releasePlayFlushLock.Run(ctx, conn, []string{playFlushLockKey}, token).Err(); err != nil {
+			log.Errorln("error caught while releasing play events flush lock: ", err)
+		}
+	}()
+
+	for {
+		values, err := claimPlayEvents(ctx, conn)
+		if err != nil {
+			log.Errorln("error caught while reading buffered play events: ", err)
+			return
+		} else if len(values) == 0 {
+			return
+		}
+
+		params := make([]database.AddPlayEventsParams, 0, len(values))
+		for _, value := range values {
+			var event PlayEvent
+			if err := json.Unmarshal([]byte(value), &event); err != nil {
+				log.Errorln("error caught while parsing buffered play event: ", err)
+				continue
+			}
+
+			params = append(params, database.AddPlayEventsParams{
+				ID: uuid.New(),
+				ReceivedAt: pgtype.Timestamp{
+					Time:  event.ReceivedAt,
+					Valid: true,
+				},
+				PlayedAt: pgtype.Timestamp{
+					Time:  event.PlayedAt,
+					Valid: true,
+				},
+				UserID:         event.UserID,
+				ContentID:      event.ContentID,
+				Type:           event.Type,
+				Position:       event.Position,
+				DurationPlayed: event.DurationPlayed,
+				Client:         event.Client,
+			})
+		}
+
+		err = ensurePlayEventsPartitions(dbCfg, ctx)
+		if err == nil {
+			_, err = database.AddPlayEventsDB(dbCfg, ctx, params)
+		}
+
+		if err != nil {
+			log.Errorln("error caught while adding play events to DB: ", err, "events kept for retry: ", len(values))
+			return
+		}
+
+		// Batch is committed, A failure here only adds the batch again in the next run
+		if err := conn.Del(ctx, playEventsProcessingKey).Err(); err != nil {
+			log.Errorln("error caught while removing flushed play events: ", err)
+			return
+		}
+
+		if len(values) < playFlushBatchSize {
+			return
+		}
+	}
+}
+
+// Flush the buffered play events periodically, Should be started in background
+func FlushPlayEvents(dbCfg *database.Config) {
+	conn := getConn()
+	defer conn.Close()
+
+	ticker := time.NewTicker(playFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		flushPlayEvents(dbCfg, context.Background(), conn)
+		<-ticker.C
+	}
+}
@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+func newPlayEventValues(t *testing.T, count int) []any {
+	values := make([]any, 0, count)
+	for i := 0; i < count; i++ {
+		value, err := json.Marshal(PlayEvent{
+			UserID:         uuid.New(),
+			ContentID:      uuid.New(),
+			Type:           database.PlayEventTypeE,
+			DurationPlayed: 60,
+			PlayedAt:       time.Now().UTC(),
+			ReceivedAt:     time.Now().UTC(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, string(value))
+	}
+	return values
+}
+
+func TestClaimPlayEvents(t *testing.T) {
+	m := miniredis.RunT(t)
+	conn := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	defer conn.Close()
+
+	ctx := context.Background()
+
+	if values, err := claimPlayEvents(ctx, conn); err != nil || len(values) != 0 {
+		t.Fatalf("expected no events from the empty buffer, got %d: %v", len(values), err)
+	}
+
+	if err := conn.RPush(ctx, playEventsKey, newPlayEventValues(t, playFlushBatchSize+5)...).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	batch, err := claimPlayEvents(ctx, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != playFlushBatchSize {
+		t.Fatalf("expected a batch of %d events, got %d", playFlushBatchSize, len(batch))
+	}
+	if remaining := conn.LLen(ctx, playEventsKey).Val(); remaining != 5 {
+		t.Errorf("expected 5 buffered events, got %d", remaining)
+	}
+
+	// Batch which is not removed after a failed flush is claimed again, Before the rest of the buffer
+	retried, err := claimPlayEvents(ctx, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(retried) != len(batch) || retried[0] != batch[0] || retried[len(retried)-1] != batch[len(batch)-1] {
+		t.Errorf("expected the same batch to be retried, got %d events", len(retried))
+	}
+
+	if err := conn.Del(ctx, playEventsProcessingKey).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	rest, err := claimPlayEvents(ctx, conn)
+	if err != nil || len(rest) != 5 {
+		t.Fatalf("expected the remaining 5 events, got %d: %v", len(rest), err)
+	}
+}
+
+func TestFlushPlayEventsFailure(t *testing.T) {
+	m := miniredis.RunT(t)
+	conn := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	defer conn.Close()
+
+	ctx := context.Background()
+	values := newPlayEventValues(t, 3)
+	if err := conn.RPush(ctx, playEventsKey, values...).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	db := newFakeDB()
+	db.on("CreatePlayEventsPartition", func(args []any) ([][]any, error) {
+		return nil, nil
+	})
+
+	pool, attempts := newTestPool(t)
+	dbCfg := &database.Config{DB: pool, Queries: database.New(db)}
+
+	// Events are kept in redis while they can't be added into DB
+	flushPlayEvents(dbCfg, ctx, conn)
+
+	if attempts.Load() == 0 {
+		t.Fatal("events are not added into DB")
+	}
+	if processing := conn.LLen(ctx, playEventsProcessingKey).Val(); processing != int64(len(values)) {
+		t.Errorf("expected %d events kept for retry, got %d", len(values), processing)
+	}
+	if err := conn.Get(ctx, playFlushLockKey).Err(); !errors.Is(err, redis.Nil) {
+		t.Errorf("flush lock is not released: %v", err)
+	}
+
+	// Flush is skipped while another instance holds the lock
+	if err := conn.Set(ctx, playFlushLockKey, "another-instance", playFlushLockTTL).Err(); err != nil {
+		t.Fatal(err)
+	}
+	before := attempts.Load()
+	flushPlayEvents(dbCfg, ctx, conn)
+
+	if attempts.Load() != before {
+		t.Error("events are flushed without holding the lock")
+	}
+	if lock := conn.Get(ctx, playFlushLockKey).Val(); lock != "another-instance" {
+		t.Errorf("lock of another instance is released: %q", lock)
+	}
+}
@@ -0,0 +1,367 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+const (
+	maxEnclosureSize      = 500 << 20
+	maxTitleLength        = 255
+	episodeMediaWorkers   = 2
+	enclosureDownloadTime = 30 * time.Minute
+
+	// Failed imports are retried on the next startup until they fail this many times
+	maxEpisodeMediaAttempts = 3
+)
+
+// File extensions of the audio types commonly used in podcast enclosures
+var enclosureExtensions = map[string]string{
+	"audio/mpeg":  ".mp3",
+	"audio/mp4":   ".m4a",
+	"audio/x-m4a": ".m4a",
+	"audio/aac":   ".aac",
+	"audio/ogg":   ".ogg",
+	"audio/wav":   ".wav",
+}
+
+var errEnclosureTooLarge = errors.New("episode media file should be smaller than 500 MB")
+
+// Episode media file which should be imported into our storage and sent for conversion
+type episodeMediaJob struct {
+	dbCfg        *database.Config
+	contentID    uuid.UUID
+	userID       uuid.UUID
+	enclosureURL string
+	mediaType    string
+}
+
+var (
+	episodeMediaJobs  = make(chan episodeMediaJob, 100)
+	startMediaWorkers sync.Once
+)
+
+// Cut the given text to the maximum length allowed for the titles
+func truncateTitle(title string) string {
+	if utf8.RuneCountInString(title) <= maxTitleLength {
+		return title
+	}
+	return string([]rune(title)[:maxTitleLength])
+}
+
+// Return the language code if it fits in the DB column, Invalid values are dropped instead of truncated
+func truncateLanguage(language string) string {
+	if len(language) > 16 {
+		return ""
+	}
+	return strings.ToLower(language)
+}
+
+// Create the podcast show along with its episodes from the given feed
+//
+// Media files of the added episodes are imported and converted in background,
+// The episodes stay without a media URL until their conversion is completed.
+func ImportShow(dbCfg *database.Config, ctx context.Context, userID uuid.UUID, feed *Feed, feedURL string) (*database.PodcastShow, []database.Content, error) {
+	currentTime := time.Now().UTC()
+
+	showParams := database.CreateShowParams{
+		ID: uuid.New(),
+		CreatedAt: pgtype.Timestamp{
+			Time:  currentTime,
+			Valid: true,
+		},
+		ModifiedAt: pgtype.Timestamp{
+			Time:  currentTime,
+			Valid: true,
+		},
+		UserID:      userID,
+		Title:       truncateTitle(feed.Title),
+		Author:      truncateTitle(feed.Author),
+		Description: feed.Description,
+		ArtworkUrl: pgtype.Text{
+			String: feed.ArtworkURL,
+			Valid:  feed.ArtworkURL != "",
+		},
+		Categories: feed.Categories,
+		Explicit:   feed.Explicit,
+		Language:   truncateLanguage(feed.Language),
+		FeedUrl: pgtype.Text{
+			String: feedURL,
+			Valid:  feedURL != "",
+		},
+	}
+
+	if showParams.Categories == nil {
+		showParams.Categories = []string{}
+	}
+	if showParams.Language == "" {
+		showParams.Language = "en"
+	}
+
+	episodesParams := make([]database.AddEpisodeParams, 0, len(feed.Episodes))
+	mediaImportsParams := make(map[uuid.UUID]database.AddEpisodeMediaImportParams, len(feed.Episodes))
+
+	for _, episode := range feed.Episodes {
+		params := database.AddEpisodeParams{
+			ID: uuid.New(),
+			CreatedAt: pgtype.Timestamp{
+				Time:  currentTime,
+				Valid: true,
+			},
+			ModifiedAt: pgtype.Timestamp{
+				Time:  currentTime,
+				Valid: true,
+			},
+			UserID:      userID,
+			Title:       truncateTitle(episode.Title),
+			Description: episode.Description,
+			SeasonNumber: pgtype.Int4{
+				Int32: episode.Season,
+				Valid: episode.Season > 0,
+			},
+			EpisodeNumber: pgtype.Int4{
+				Int32: episode.Episode,
+				Valid: episode.Episode > 0,
+			},
+			PublishedAt: pgtype.Timestamp{
+				Time:  episode.PublishedAt,
+				Valid: !episode.PublishedAt.IsZero(),
+			},
+			ShowNotes: pgtype.Text{
+				String: episode.ShowNotes,
+				Valid:  episode.ShowNotes != "",
+			},
+			Guid: pgtype.Text{
+				String: episode.GUID,
+				Valid:  true,
+			},
+			ChaptersUrl: pgtype.Text{
+				String: episode.ChaptersURL,
+				Valid:  episode.ChaptersURL != "",
+			},
+			TranscriptUrl: pgtype.Text{
+				String: episode.TranscriptURL,
+				Valid:  episode.TranscriptURL != "",
+			},
+		}
+
+		episodesParams = append(episodesParams, params)
+		mediaImportsParams[params.ID] = database.AddEpisodeMediaImportParams{
+			ContentID:     params.ID,
+			CreatedAt:     params.CreatedAt,
+			UserID:        userID,
+			EnclosureUrl:  episode.EnclosureURL,
+			EnclosureType: episode.EnclosureType,
+		}
+	}
+
+	show, episodes, err := database.ImportShowDB(dbCfg, ctx, showParams, episodesParams, mediaImportsParams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	jobs := make([]episodeMediaJob, 0, len(episodes))
+	for _, episode := range episodes {
+		params := mediaImportsParams[episode.ID]
+		jobs = append(jobs, episodeMediaJob{
+			dbCfg:        dbCfg,
+			contentID:    episode.ID,
+			userID:       userID,
+			enclosureURL: params.EnclosureUrl,
+			mediaType:    params.EnclosureType,
+		})
+	}
+	enqueueEpisodeMedia(jobs)
+
+	return show, episodes, nil
+}
+
+// Add the given jobs into the episode media queue
+//
+// Jobs are added in background, So that a large feed does not block the request until the queue has space
+func enqueueEpisodeMedia(jobs []episodeMediaJob) {
+	startMediaWorkers.Do(func() {
+		for i := 0; i < episodeMediaWorkers; i++ {
+			go processEpisodeMediaJobs()
+		}
+	})
+
+	go func() {
+		for _, job := range jobs {
+			episodeMediaJobs <- job
+		}
+	}()
+}
+
+func processEpisodeMediaJobs() {
+	for job := range episodeMediaJobs {
+		if err := importEpisodeMedia(job); err != nil {
+			log.Errorln("error caught while importing episode media: ", err, "content: ", job.contentID, "url: ", job.enclosureURL)
+
+			if err := database.FailEpisodeMediaImportDB(job.dbCfg, context.Background(), job.contentID); err != nil {
+				log.Errorln("error caught while marking episode media import as failed: ", err)
+			}
+		}
+	}
+}
+
+// Enqueue the episode media imports which were not completed before the service stopped
+func ResumeEpisodeMediaImports(dbCfg *database.Config) {
+	mediaImports, err := database.GetPendingEpisodeMediaImportsDB(dbCfg, context.Background(), maxEpisodeMediaAttempts)
+	if err != nil {
+		log.Errorln("error caught while fetching pending episode media imports: ", err)
+		return
+	}
+
+	if len(mediaImports) == 0 {
+		return
+	}
+
+	jobs := make([]episodeMediaJob, 0, len(mediaImports))
+	for _, mediaImport := range mediaImports {
+		jobs = append(jobs, episodeMediaJob{
+			dbCfg:        dbCfg,
+			contentID:    mediaImport.ContentID,
+			userID:       mediaImport.UserID,
+			enclosureURL: mediaImport.EnclosureUrl,
+			mediaType:    mediaImport.EnclosureType,
+		})
+	}
+	enqueueEpisodeMedia(jobs)
+
+	log.Infoln("resumed episode media imports: ", len(jobs))
+}
+
+// Return the file extension of the episode media file, Using the enclosure type if URL does not have one
+func getEnclosureExtension(enclosureURL, mediaType string) string {
+	if parsedURL, err := url.Parse(enclosureURL); err == nil {
+		if ext := strings.ToLower(path.Ext(parsedURL.Path)); ext != "" {
+			return ext
+		}
+	}
+
+	if ext, exists := enclosureExtensions[strings.ToLower(mediaType)]; exists {
+		return ext
+	}
+	return ".mp3"
+}
+
+// Download the episode media file into a temporary file
+func downloadEnclosure(ctx context.Context, enclosureURL string) (*os.File, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, enclosureURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := getExternalHTTPClient(enclosureDownloadTime).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected enclosure response status: %d", res.StatusCode)
+	}
+
+	file, err := os.CreateTemp("", "episode-*")
+	if err != nil {
+		return nil, err
+	}
+
+	written, err := io.Copy(file, io.LimitReader(res.Body, maxEnclosureSize+1))
+	if err == nil && written > maxEnclosureSize {
+		err = errEnclosureTooLarge
+	}
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	return file, nil
+}
+
+// Copy the episode media file into our storage and send it for conversion
+func importEpisodeMedia(job episodeMediaJob) error {
+	ctx := context.Background()
+
+	file, err := downloadEnclosure(ctx, job.enclosureURL)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	client, err := getS3Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Same key format which is used for the files uploaded via pre-signed URLs
+	key := "audio/" + job.contentID.String() + getEnclosureExtension(job.enclosureURL, job.mediaType)
+
+	// Version is added before the upload, So the storage notification of the file doesn't convert it again
+	version, err := database.AddMediaVersionDB(job.dbCfg, ctx, database.AddMediaVersionParams{
+		ID: uuid.New(),
+		CreatedAt: pgtype.Timestamp{
+			Time:  time.Now().UTC(),
+			Valid: true,
+		},
+		SourceKey: key,
+		ContentID: job.contentID,
+		UserID: pgtype.UUID{
+			Bytes: job.userID,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Media version takes over the import from here, Its failures are tracked by the version status
+	if err := database.DeleteEpisodeMediaImportDB(job.dbCfg, ctx, job.contentID); err != nil {
+		log.Errorln("error caught while deleting episode media import: ", err)
+	}
+
+	if _, err = client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(os.Getenv("AWS_BUCKET_NAME")),
+		Key:    aws.String(key),
+		Body:   file,
+		ACL:    types.ObjectCannedACLPublicRead,
+	}); err != nil {
+		if err := database.FailMediaVersionDB(job.dbCfg, ctx, database.MarkMediaVersionFailedParams{
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			ID: version.ID,
+		}); err != nil {
+			log.Errorln("error caught while marking media version as failed: ", err)
+		}
+		return err
+	}
+
+	ConvertMediaVersion(job.dbCfg, ctx, version, true)
+	return nil
+}
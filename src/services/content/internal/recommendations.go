@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+const (
+	recommendationsInterval = 6 * time.Hour
+
+	// Number of similar contents stored for each content
+	similarContentCount = 20
+
+	// Score added for each artist shared by two contents, Co-listening score is in between 0 and 1
+	sharedArtistWeight = 0.5
+
+	// Score added for each genre or tag shared by two contents, Tags used by too many contents are ignored
+	sharedLabelWeight = 0.2
+
+	// Only the recent plays are used as the co-listening signal
+	listeningWindow = 90 * 24 * time.Hour
+)
+
+// Recompute the similar contents from the co-listening in play history, The shared artists and genres & tags
+func refreshContentSimilarities(dbCfg *database.Config, ctx context.Context) {
+	currentTime := time.Now().UTC()
+
+	if err := database.RefreshContentSimilaritiesDB(dbCfg, ctx, database.AddContentSimilaritiesParams{
+		ListenedAfter: pgtype.Timestamp{
+			Time:  currentTime.Add(-listeningWindow),
+			Valid: true,
+		},
+		MinDuration:  minPlayDuration,
+		ArtistWeight: sharedArtistWeight,
+		LabelWeight:  sharedLabelWeight,
+		CreatedAt: pgtype.Timestamp{
+			Time:  currentTime,
+			Valid: true,
+		},
+		Neighbors: similarContentCount,
+	}); err != nil {
+		log.Errorln("error caught while refreshing content similarities: ", err)
+	}
+}
+
+// Refresh the similar contents periodically, Should be started in background
+func RefreshContentSimilarities(dbCfg *database.Config) {
+	ticker := time.NewTicker(recommendationsInterval)
+	defer ticker.Stop()
+
+	for {
+		refreshContentSimilarities(dbCfg, context.Background())
+		<-ticker.C
+	}
+}
@@ -0,0 +1,133 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+const (
+	releasesInterval       = time.Minute
+	releaseEventsBatchSize = 100
+
+	// Redis channel on which the content published events are sent
+	ContentPublishedChannel = "content_published"
+)
+
+// Source of the current time, Injected into the release logic so it can be controlled
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// Clock backed by the system time in UTC
+var SystemClock Clock = systemClock{}
+
+// Event sent when a scheduled content gets published
+//
+// Events are sent at least once, Subscribers can use the event ID to skip the duplicates
+type ReleaseEvent struct {
+	ID          uuid.UUID `json:"id"`
+	ContentID   uuid.UUID `json:"content_id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Title       string    `json:"title"`
+	Type        string    `json:"type"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// Check weather or not a content with the given schedule should be publicly visible at the current time
+func IsReleaseDue(clock Clock, publishAt pgtype.Timestamp, unpublishAt pgtype.Timestamp) bool {
+	now := clock.Now()
+
+	if publishAt.Valid && publishAt.Time.After(now) {
+		return false
+	}
+	return !unpublishAt.Valid || unpublishAt.Time.After(now)
+}
+
+// Publish the given outbox events on the redis channel, Returns the IDs of the events which were sent
+func publishReleaseEvents(ctx context.Context, dbEvents []database.ContentReleaseEvent) []uuid.UUID {
+	conn := getConn()
+	defer conn.Close()
+
+	sentIDs := make([]uuid.UUID, 0, len(dbEvents))
+	for _, dbEvent := range dbEvents {
+		eventByte, err := json.Marshal(ReleaseEvent{
+			ID:          dbEvent.ID,
+			ContentID:   dbEvent.ContentID,
+			UserID:      dbEvent.UserID,
+			Title:       dbEvent.Title,
+			Type:        string(dbEvent.Type),
+			PublishedAt: dbEvent.PublishedAt.Time,
+		})
+		if err != nil {
+			log.Errorln("error caught while encoding release event: ", err)
+			continue
+		}
+
+		// Event stays in the outbox and is sent again by the next run
+		if err := conn.Publish(ctx, ContentPublishedChannel, eventByte).Err(); err != nil {
+			log.Errorln("error caught while publishing release event: ", err, "content: ", dbEvent.ContentID)
+			continue
+		}
+		sentIDs = append(sentIDs, dbEvent.ID)
+	}
+	return sentIDs
+}
+
+// Send the pending release events in batches, Stops at the first batch which could not be sent completely
+func sendReleaseEvents(dbCfg *database.Config, ctx context.Context) {
+	for {
+		sent := 0
+		count, err := database.SendReleaseEventsDB(dbCfg, ctx, releaseEventsBatchSize, func(dbEvents []database.ContentReleaseEvent) []uuid.UUID {
+			sentIDs := publishReleaseEvents(ctx, dbEvents)
+			sent = len(sentIDs)
+			return sentIDs
+		})
+		if err != nil {
+			log.Errorln("error caught while sending release events: ", err)
+			return
+		}
+
+		if count < releaseEventsBatchSize || sent < count {
+			return
+		}
+	}
+}
+
+// Publish the contents which are due and hide the ones whose embargo has started
+func releaseScheduledContent(dbCfg *database.Config, ctx context.Context, clock Clock) {
+	now := clock.Now()
+
+	if _, err := database.UnpublishExpiredContentDB(dbCfg, ctx, now); err != nil {
+		log.Errorln("error caught while unpublishing expired contents: ", err)
+	}
+
+	if _, err := database.ReleaseScheduledContentDB(dbCfg, ctx, now); err != nil {
+		log.Errorln("error caught while releasing scheduled contents: ", err)
+	}
+
+	// Events of the previous runs which could not be sent are retried as well
+	sendReleaseEvents(dbCfg, ctx)
+}
+
+// Release the scheduled contents periodically, Should be started in background
+func ReleaseScheduledContent(dbCfg *database.Config) {
+	ticker := time.NewTicker(releasesInterval)
+	defer ticker.Stop()
+
+	for {
+		releaseScheduledContent(dbCfg, context.Background(), SystemClock)
+		<-ticker.C
+	}
+}
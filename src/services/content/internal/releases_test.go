@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redis/go-redis/v9"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func TestIsReleaseDue(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	clock := fixedClock(now)
+
+	at := func(d time.Duration) pgtype.Timestamp {
+		return pgtype.Timestamp{Time: now.Add(d), Valid: true}
+	}
+	unset := pgtype.Timestamp{}
+
+	tests := []struct {
+		name        string
+		publishAt   pgtype.Timestamp
+		unpublishAt pgtype.Timestamp
+		due         bool
+	}{
+		{name: "both unset", publishAt: unset, unpublishAt: unset, due: true},
+		{name: "at publish_at", publishAt: at(0), unpublishAt: unset, due: true},
+		{name: "before publish_at", publishAt: at(time.Second), unpublishAt: unset, due: false},
+		{name: "after publish_at", publishAt: at(-time.Hour), unpublishAt: unset, due: true},
+		{name: "at unpublish_at", publishAt: unset, unpublishAt: at(0), due: false},
+		{name: "before unpublish_at", publishAt: at(-time.Hour), unpublishAt: at(time.Second), due: true},
+		{name: "after unpublish_at", publishAt: at(-time.Hour), unpublishAt: at(-time.Minute), due: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if due := IsReleaseDue(clock, tc.publishAt, tc.unpublishAt); due != tc.due {
+				t.Fatalf("expected due %v, got %v", tc.due, due)
+			}
+		})
+	}
+}
+
+func TestPublishReleaseEvents(t *testing.T) {
+	m := miniredis.RunT(t)
+	t.Setenv("REDIS_HOST", m.Addr())
+
+	ctx := context.Background()
+	publishedAt := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	dbEvents := []database.ContentReleaseEvent{
+		{ID: uuid.New(), ContentID: uuid.New(), UserID: uuid.New(), Title: "First", Type: database.ContentTypeM, PublishedAt: pgtype.Timestamp{Time: publishedAt, Valid: true}},
+		{ID: uuid.New(), ContentID: uuid.New(), UserID: uuid.New(), Title: "Second", Type: database.ContentTypeP, PublishedAt: pgtype.Timestamp{Time: publishedAt, Valid: true}},
+	}
+
+	conn := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	defer conn.Close()
+
+	sub := conn.Subscribe(ctx, ContentPublishedChannel)
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	sentIDs := publishReleaseEvents(ctx, dbEvents)
+	if len(sentIDs) != len(dbEvents) {
+		t.Fatalf("expected %d sent events, got %d", len(dbEvents), len(sentIDs))
+	}
+
+	for _, dbEvent := range dbEvents {
+		msg, err := sub.ReceiveMessage(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var event ReleaseEvent
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			t.Fatal(err)
+		}
+		if event.ID != dbEvent.ID || event.ContentID != dbEvent.ContentID || !event.PublishedAt.Equal(publishedAt) {
+			t.Fatalf("unexpected event: %+v", event)
+		}
+	}
+
+	// Events are kept in the outbox while redis is not reachable
+	m.Close()
+	if sentIDs := publishReleaseEvents(ctx, dbEvents); len(sentIDs) != 0 {
+		t.Fatalf("expected no sent events, got %d", len(sentIDs))
+	}
+}
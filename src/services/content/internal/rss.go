@@ -0,0 +1,281 @@
+package internal
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	maxFeedSize       = 10 << 20
+	externalFetchTime = 30 * time.Second
+)
+
+var (
+	ErrInvalidFeed   = errors.New("invalid podcast RSS feed")
+	ErrFeedTooLarge  = errors.New("podcast RSS feed should be smaller than 10 MB")
+	errBlockedTarget = errors.New("requests to private network addresses are not allowed")
+)
+
+// Details of a podcast show parsed from its RSS feed
+type Feed struct {
+	Title       string
+	Author      string
+	Language    string
+	Description string
+	ArtworkURL  string
+	Categories  []string
+	Explicit    bool
+	Episodes    []FeedEpisode
+}
+
+type FeedEpisode struct {
+	GUID          string
+	Title         string
+	Description   string
+	ShowNotes     string
+	Season        int32
+	Episode       int32
+	PublishedAt   time.Time
+	EnclosureURL  string
+	EnclosureType string
+	ChaptersURL   string
+	TranscriptURL string
+}
+
+type rssImage struct {
+	Href string `xml:"href,attr"`
+	URL  string `xml:"url"`
+}
+
+type rssLink struct {
+	URL string `xml:"url,attr"`
+}
+
+type rssCategory struct {
+	Text          string        `xml:"text,attr"`
+	SubCategories []rssCategory `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd category"`
+}
+
+// Fields are matched in order, So the namespaced ones are declared before the plain RSS fields having the same name
+type rssItem struct {
+	GUID        string    `xml:"guid"`
+	ITunesTitle string    `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd title"`
+	Title       string    `xml:"title"`
+	Description string    `xml:"description"`
+	Summary     string    `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd summary"`
+	Encoded     string    `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate     string    `xml:"pubDate"`
+	Season      string    `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd season"`
+	Episode     string    `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	Chapters    rssLink   `xml:"https://podcastindex.org/namespace/1.0 chapters"`
+	Transcripts []rssLink `xml:"https://podcastindex.org/namespace/1.0 transcript"`
+	Enclosure   struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+}
+
+type rssFeed struct {
+	Channel struct {
+		Title       string        `xml:"title"`
+		Description string        `xml:"description"`
+		Language    string        `xml:"language"`
+		Author      string        `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author"`
+		Explicit    string        `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd explicit"`
+		Images      []rssImage    `xml:"image"`
+		Categories  []rssCategory `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd category"`
+		Items       []rssItem     `xml:"item"`
+	} `xml:"channel"`
+}
+
+// Date formats used by the podcast hosts for pubDate, RFC 822 is the one recommended by the RSS spec
+var pubDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC3339,
+}
+
+func parsePubDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+
+	for _, layout := range pubDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// Parse the positive number present in itunes:season or itunes:episode tag
+func parseFeedNumber(value string) int32 {
+	number, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+	if err != nil || number <= 0 {
+		return 0
+	}
+	return int32(number)
+}
+
+// Parse the given podcast RSS feed
+func ParseFeed(r io.Reader) (*Feed, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxFeedSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > maxFeedSize {
+		return nil, ErrFeedTooLarge
+	}
+
+	var rss rssFeed
+	if err = xml.Unmarshal(data, &rss); err != nil {
+		return nil, ErrInvalidFeed
+	}
+
+	channel := rss.Channel
+	if strings.TrimSpace(channel.Title) == "" {
+		return nil, ErrInvalidFeed
+	}
+
+	feed := &Feed{
+		Title:       strings.TrimSpace(channel.Title),
+		Author:      strings.TrimSpace(channel.Author),
+		Description: strings.TrimSpace(channel.Description),
+		Language:    strings.TrimSpace(channel.Language),
+	}
+
+	// Both <image><url> of RSS and <itunes:image href> are matched, The iTunes one is preferred
+	for _, image := range channel.Images {
+		if image.Href != "" {
+			feed.ArtworkURL = image.Href
+			break
+		}
+		if feed.ArtworkURL == "" {
+			feed.ArtworkURL = strings.TrimSpace(image.URL)
+		}
+	}
+
+	for _, category := range channel.Categories {
+		feed.Categories = append(feed.Categories, category.Text)
+		for _, subCategory := range category.SubCategories {
+			feed.Categories = append(feed.Categories, subCategory.Text)
+		}
+	}
+
+	switch strings.ToLower(strings.TrimSpace(channel.Explicit)) {
+	case "yes", "true", "explicit":
+		feed.Explicit = true
+	}
+
+	for _, item := range channel.Items {
+		// Episodes without an enclosure has no media, So there is nothing to import
+		if item.Enclosure.URL == "" {
+			continue
+		}
+
+		episode := FeedEpisode{
+			GUID:          strings.TrimSpace(item.GUID),
+			Title:         strings.TrimSpace(item.Title),
+			Description:   strings.TrimSpace(item.Summary),
+			ShowNotes:     strings.TrimSpace(item.Encoded),
+			Season:        parseFeedNumber(item.Season),
+			Episode:       parseFeedNumber(item.Episode),
+			PublishedAt:   parsePubDate(item.PubDate),
+			EnclosureURL:  strings.TrimSpace(item.Enclosure.URL),
+			EnclosureType: item.Enclosure.Type,
+			ChaptersURL:   strings.TrimSpace(item.Chapters.URL),
+		}
+
+		if len(item.Transcripts) > 0 {
+			episode.TranscriptURL = strings.TrimSpace(item.Transcripts[0].URL)
+		}
+
+		if episode.Title == "" {
+			episode.Title = strings.TrimSpace(item.ITunesTitle)
+		}
+		if episode.GUID == "" {
+			episode.GUID = episode.EnclosureURL
+		}
+		if episode.Description == "" {
+			episode.Description = strings.TrimSpace(item.Description)
+		}
+		if episode.ShowNotes == "" {
+			episode.ShowNotes = strings.TrimSpace(item.Description)
+		}
+		if episode.Title == "" {
+			continue
+		}
+
+		feed.Episodes = append(feed.Episodes, episode)
+	}
+
+	return feed, nil
+}
+
+// Reject the connections made to loopback, Private or link-local addresses,
+// So that the user provided URLs can not be used to reach the internal services
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return errBlockedTarget
+	}
+	return nil
+}
+
+// Return a HTTP client for fetching the user provided URLs
+func getExternalHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: dialControl,
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// Fetch and parse the podcast RSS feed present at the given URL
+func FetchFeed(ctx context.Context, feedURL string) (*Feed, error) {
+	parsedURL, err := url.Parse(feedURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+		return nil, fmt.Errorf("invalid feed URL: %q", feedURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := getExternalHTTPClient(externalFetchTime).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected feed response status: %d", res.StatusCode)
+	}
+
+	return ParseFeed(res.Body)
+}
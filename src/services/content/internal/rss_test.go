@@ -0,0 +1,160 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFeed(t *testing.T) {
+	file, err := os.Open("testdata/feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	feed, err := ParseFeed(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Feed{
+		Title:       "The Show",
+		Author:      "Show Author",
+		Language:    "en-US",
+		Description: "Weekly episodes about music",
+		// iTunes artwork is preferred over the RSS image
+		ArtworkURL: "https://example.com/itunes-artwork.jpg",
+		Categories: []string{"Music", "Music History", "Arts"},
+		Explicit:   true,
+		Episodes: []FeedEpisode{
+			{
+				GUID:          "episode-2",
+				Title:         "Second Episode",
+				Description:   "iTunes summary",
+				ShowNotes:     "<p>Show notes</p>",
+				Season:        1,
+				Episode:       2,
+				PublishedAt:   time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC),
+				EnclosureURL:  "https://example.com/episode-2.mp3",
+				EnclosureType: "audio/mpeg",
+				ChaptersURL:   "https://example.com/chapters.json",
+				TranscriptURL: "https://example.com/transcript.vtt",
+			},
+			{
+				GUID:          "https://example.com/episode-1.m4a",
+				Title:         "First Episode",
+				Description:   "Only description",
+				ShowNotes:     "Only description",
+				PublishedAt:   time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC),
+				EnclosureURL:  "https://example.com/episode-1.m4a",
+				EnclosureType: "audio/x-m4a",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(feed, expected) {
+		t.Errorf("expected %+v, got %+v", expected, feed)
+	}
+}
+
+func TestParseFeedFallbacks(t *testing.T) {
+	feed, err := ParseFeed(strings.NewReader(`<rss><channel>
+		<title>Show</title>
+		<image><url> https://example.com/rss-artwork.jpg </url></image>
+		<itunes:explicit xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">no</itunes:explicit>
+	</channel></rss>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if feed.ArtworkURL != "https://example.com/rss-artwork.jpg" {
+		t.Errorf("expected the RSS image as artwork, got %q", feed.ArtworkURL)
+	}
+	if feed.Explicit || len(feed.Episodes) != 0 {
+		t.Errorf("unexpected feed %+v", feed)
+	}
+}
+
+func TestParseInvalidFeed(t *testing.T) {
+	tests := map[string]string{
+		"not xml":       "not a feed",
+		"missing title": "<rss><channel><title> </title></channel></rss>",
+	}
+
+	for name, body := range tests {
+		if _, err := ParseFeed(strings.NewReader(body)); !errors.Is(err, ErrInvalidFeed) {
+			t.Errorf("%s: expected %v, got %v", name, ErrInvalidFeed, err)
+		}
+	}
+}
+
+func TestParseFeedSizeLimit(t *testing.T) {
+	prefix := "<rss><channel><title>Show</title><description>"
+	suffix := "</description></channel></rss>"
+
+	// Feed of exactly the maximum size is accepted
+	body := prefix + strings.Repeat("a", maxFeedSize-len(prefix)-len(suffix)) + suffix
+	if _, err := ParseFeed(strings.NewReader(body)); err != nil {
+		t.Errorf("feed of the maximum size is rejected: %v", err)
+	}
+
+	body = prefix + strings.Repeat("a", maxFeedSize-len(prefix)-len(suffix)+1) + suffix
+	if _, err := ParseFeed(strings.NewReader(body)); !errors.Is(err, ErrFeedTooLarge) {
+		t.Errorf("expected %v, got %v", ErrFeedTooLarge, err)
+	}
+}
+
+func TestDialControl(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{address: "93.184.216.34:443", allowed: true},
+		{address: "[2606:2800:220:1:248:1893:25c8:1946]:443", allowed: true},
+		{address: "127.0.0.1:80"},
+		{address: "[::1]:80"},
+		{address: "10.0.0.5:5432"},
+		{address: "172.16.0.1:6379"},
+		{address: "192.168.1.1:80"},
+		{address: "[fd00::1]:80"},
+		{address: "169.254.169.254:80"},
+		{address: "[fe80::1]:80"},
+		{address: "0.0.0.0:80"},
+		{address: "224.0.0.1:80"},
+		{address: "localhost:80"},
+		{address: "93.184.216.34"},
+	}
+
+	for _, tc := range tests {
+		err := dialControl("tcp", tc.address, nil)
+		if tc.allowed && err != nil {
+			t.Errorf("%s: expected to be allowed, got %v", tc.address, err)
+		}
+		if !tc.allowed && err == nil {
+			t.Errorf("%s: expected to be blocked", tc.address)
+		}
+	}
+}
+
+func TestGetEnclosureExtension(t *testing.T) {
+	tests := []struct {
+		url       string
+		mediaType string
+		ext       string
+	}{
+		{url: "https://example.com/episode.MP3?token=1", mediaType: "audio/mp4", ext: ".mp3"},
+		{url: "https://example.com/episode", mediaType: "audio/x-m4a", ext: ".m4a"},
+		{url: "https://example.com/episode", mediaType: "Audio/OGG", ext: ".ogg"},
+		{url: "https://example.com/episode", mediaType: "application/octet-stream", ext: ".mp3"},
+	}
+
+	for _, tc := range tests {
+		if ext := getEnclosureExtension(tc.url, tc.mediaType); ext != tc.ext {
+			t.Errorf("%s (%s): expected %s, got %s", tc.url, tc.mediaType, tc.ext, ext)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Maximum number of keys which can be deleted in a single DeleteObjects call
+const maxDeleteObjects = 1000
+
+func getS3Client(ctx context.Context) (*s3.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s3.NewFromConfig(cfg), nil
+}
+
+// Return all the s3 objects keys generated for the given media key
+//
+// Conversion service uploads the HLS playlist along with a single TS segment file having the same name,
+// And a M4A download file for audio
+func getMediaObjectKeys(key string) []string {
+	keys := []string{key}
+
+	if path.Ext(key) == ".m3u8" {
+		keys = append(keys, strings.TrimSuffix(key, ".m3u8")+".ts")
+
+		if strings.HasPrefix(key, "audio/") {
+			keys = append(keys, strings.TrimSuffix(key, ".m3u8")+".m4a")
+		}
+	}
+	return keys
+}
+
+// Delete the given objects from s3 in batches
+func deleteObjects(ctx context.Context, client *s3.Client, keys []string) error {
+	for start := 0; start < len(keys); start += maxDeleteObjects {
+		end := min(start+maxDeleteObjects, len(keys))
+
+		objects := make([]types.ObjectIdentifier, 0, end-start)
+		for _, key := range keys[start:end] {
+			objects = append(objects, types.ObjectIdentifier{Key: aws.String(key)})
+		}
+
+		if _, err := client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(os.Getenv("AWS_BUCKET_NAME")),
+			Delete: &types.Delete{
+				Objects: objects,
+				Quiet:   aws.Bool(true),
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete the given media files and their related objects from s3
+func DeleteMediaFiles(ctx context.Context, mediaKeys []string) error {
+	if len(mediaKeys) == 0 {
+		return nil
+	}
+
+	client, err := getS3Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	var keys []string
+	for _, mediaKey := range mediaKeys {
+		keys = append(keys, getMediaObjectKeys(mediaKey)...)
+	}
+
+	return deleteObjects(ctx, client, keys)
+}
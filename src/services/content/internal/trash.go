@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+const (
+	purgeInterval        = time.Hour
+	defaultRetentionDays = 30
+)
+
+// Return for how long the deleted contents are kept in trash
+func GetTrashRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("CONTENT_RETENTION_DAYS"))
+	if err != nil || days <= 0 {
+		days = defaultRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Permanently delete the contents whose retention period is over along with their media files
+func purgeDeletedContent(dbCfg *database.Config, ctx context.Context) {
+	deletedBefore := time.Now().UTC().Add(-GetTrashRetention())
+
+	dbKeys, err := database.PurgeDeletedContentDB(dbCfg, ctx, deletedBefore)
+	if err != nil {
+		log.Errorln("error caught while purging deleted contents: ", err)
+		return
+	}
+
+	var mediaKeys []string
+	for _, key := range dbKeys {
+		if key.Valid {
+			mediaKeys = append(mediaKeys, key.String)
+		}
+	}
+
+	if err = DeleteMediaFiles(ctx, mediaKeys); err != nil {
+		// Rows are already deleted, So the orphaned objects are just logged
+		log.Errorln("error caught while deleting purged media files: ", err, "keys: ", mediaKeys)
+	}
+}
+
+// Purge the contents from trash periodically, Should be started in background
+func PurgeDeletedContent(dbCfg *database.Config) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purgeDeletedContent(dbCfg, context.Background())
+		<-ticker.C
+	}
+}
@@ -0,0 +1,206 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+)
+
+const (
+	uploadPartURLExpiry    = time.Hour
+	expiredUploadsInterval = time.Hour
+	expiredUploadsBatch    = 100
+
+	// For how long the storage notification of a single file upload is accepted after issuing its key
+	mediaUploadTTL = 24 * time.Hour
+)
+
+// Part of a multipart upload which is uploaded to s3
+type UploadedPart struct {
+	PartNumber   int32     `json:"part_number"`
+	ETag         string    `json:"etag"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// Start a multipart upload in s3 for the given key and return its upload ID
+func CreateMultipartUpload(ctx context.Context, key string) (string, error) {
+	client, err := getS3Client(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(os.Getenv("AWS_BUCKET_NAME")),
+		Key:    aws.String(key),
+		ACL:    types.ObjectCannedACLPublicRead,
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(res.UploadId), nil
+}
+
+// Generate the pre-signed URLs for uploading the given parts, Mapped by their part number
+func PresignUploadParts(ctx context.Context, session *database.UploadSession, partNumbers []int32) (map[int32]string, error) {
+	client, err := getS3Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	presignClient := s3.NewPresignClient(client)
+
+	urls := make(map[int32]string, len(partNumbers))
+	for _, partNumber := range partNumbers {
+		res, err := presignClient.PresignUploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(os.Getenv("AWS_BUCKET_NAME")),
+			Key:        aws.String(session.S3Key),
+			UploadId:   aws.String(session.S3UploadID),
+			PartNumber: aws.Int32(partNumber),
+		}, s3.WithPresignExpires(uploadPartURLExpiry))
+		if err != nil {
+			return nil, err
+		}
+		urls[partNumber] = res.URL
+	}
+	return urls, nil
+}
+
+// Return the parts of a multipart upload which are uploaded to s3, Ordered by their part number
+func ListUploadedParts(ctx context.Context, session *database.UploadSession) ([]UploadedPart, error) {
+	client, err := getS3Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := []UploadedPart{}
+	paginator := s3.NewListPartsPaginator(client, &s3.ListPartsInput{
+		Bucket:   aws.String(os.Getenv("AWS_BUCKET_NAME")),
+		Key:      aws.String(session.S3Key),
+		UploadId: aws.String(session.S3UploadID),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, part := range page.Parts {
+			parts = append(parts, UploadedPart{
+				PartNumber:   aws.ToInt32(part.PartNumber),
+				ETag:         aws.ToString(part.ETag),
+				Size:         aws.ToInt64(part.Size),
+				LastModified: aws.ToTime(part.LastModified),
+			})
+		}
+	}
+	return parts, nil
+}
+
+// Assemble the uploaded parts into the final object
+func CompleteMultipartUpload(ctx context.Context, session *database.UploadSession, parts []UploadedPart) error {
+	client, err := getS3Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	completedParts := make([]types.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		completedParts = append(completedParts, types.CompletedPart{
+			PartNumber: aws.Int32(part.PartNumber),
+			ETag:       aws.String(part.ETag),
+		})
+	}
+
+	_, err = client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(os.Getenv("AWS_BUCKET_NAME")),
+		Key:             aws.String(session.S3Key),
+		UploadId:        aws.String(session.S3UploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completedParts},
+	})
+	return err
+}
+
+// Abort a multipart upload, s3 removes the uploaded parts
+//
+// Upload which doesn't exist in s3 anymore is considered as aborted, So that the sessions don't get stuck.
+func AbortMultipartUpload(ctx context.Context, session *database.UploadSession) error {
+	client, err := getS3Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(os.Getenv("AWS_BUCKET_NAME")),
+		Key:      aws.String(session.S3Key),
+		UploadId: aws.String(session.S3UploadID),
+	})
+
+	var noSuchUpload *types.NoSuchUpload
+	if errors.As(err, &noSuchUpload) {
+		return nil
+	}
+	return err
+}
+
+// Abort the multipart uploads which are not completed before their expiry, So that their parts don't stay in s3.
+// Expired keys of the single file uploads are removed as well.
+func abortExpiredUploads(dbCfg *database.Config, ctx context.Context) {
+	now := time.Now().UTC()
+
+	if err := database.DeleteExpiredMediaUploadsDB(dbCfg, ctx, pgtype.Timestamp{
+		Time:  now.Add(-mediaUploadTTL),
+		Valid: true,
+	}); err != nil {
+		log.Errorln("error caught while deleting expired media uploads: ", err)
+	}
+
+	sessions, err := database.GetExpiredUploadSessionsDB(dbCfg, ctx, database.GetExpiredUploadSessionsParams{
+		ExpiresAt: pgtype.Timestamp{
+			Time:  now,
+			Valid: true,
+		},
+		Limit: expiredUploadsBatch,
+	})
+	if err != nil {
+		log.Errorln("error caught while fetching expired upload sessions: ", err)
+		return
+	}
+
+	for _, session := range sessions {
+		if err := AbortMultipartUpload(ctx, &session); err != nil {
+			log.Errorln("error caught while aborting expired multipart upload: ", err, "session: ", session.ID)
+			continue
+		}
+
+		if err := database.UpdateUploadSessionStatusDB(dbCfg, ctx, database.UpdateUploadSessionStatusParams{
+			Status: database.UploadSessionStatusAborted,
+			ModifiedAt: pgtype.Timestamp{
+				Time:  now,
+				Valid: true,
+			},
+			ID: session.ID,
+		}); err != nil {
+			log.Errorln("error caught while updating upload session status: ", err, "session: ", session.ID)
+		}
+	}
+}
+
+// Abort the expired multipart uploads periodically, Should be started in background
+func AbortExpiredUploads(dbCfg *database.Config) {
+	ticker := time.NewTicker(expiredUploadsInterval)
+	defer ticker.Stop()
+
+	for {
+		abortExpiredUploads(dbCfg, context.Background())
+		<-ticker.C
+	}
+}
@@ -19,45 +19,121 @@ func getConn() *redis.Client {
 	})
 }
 
-func GetUserDetail(ctx context.Context, token string) (*User, error) {
+// Revoked users are kept for longer than the access token lifetime after the account is deleted permanently,
+// So that the tokens issued before the deletion expire before the key does
+const deletedUserRevokeExp = 30 * 24 * time.Hour
+
+func getRevokedUserKey(userID uuid.UUID) string {
+	return "revoked_user:" + userID.String()
+}
+
+// Mark the given user as revoked, So that its access tokens are rejected even though they are not expired yet
+//
+// Cached creator details are removed as well. Key is kept until the user is restored if the expiration is zero.
+func RevokeUser(ctx context.Context, userID uuid.UUID, expiration time.Duration) error {
 	conn := getConn()
 	defer conn.Close()
 
-	// Check and fetch if details exists in redis
-	userByte, err := conn.Get(ctx, token).Bytes()
-	if err == nil {
-		user, err := ByteToUser(userByte)
-		if err == nil {
-			return user, nil
-		}
-	}
+	pipe := conn.TxPipeline()
+	pipe.Set(ctx, getRevokedUserKey(userID), 1, expiration)
+	pipe.Del(ctx, getCreatorKey(userID))
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Accept the access tokens of the given user again
+func RestoreUser(ctx context.Context, userID uuid.UUID) error {
+	conn := getConn()
+	defer conn.Close()
+
+	return conn.Del(ctx, getRevokedUserKey(userID)).Err()
+}
 
-	// fetch user details via gRPC
-	grpcResponse, err := fetchUserDetail(token)
+// Check weather or not the given user is revoked
+func IsUserRevoked(ctx context.Context, userID uuid.UUID) (bool, error) {
+	conn := getConn()
+	defer conn.Close()
+
+	count, err := conn.Exists(ctx, getRevokedUserKey(userID)).Result()
 	if err != nil {
-		return nil, err
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func getCreatorKey(userID uuid.UUID) string {
+	return "creator:" + userID.String()
+}
+
+// Return the public details of the given users, Mapped by their IDs
+//
+// Details are cached in redis, So only the missing ones are fetched from the user service
+func GetCreators(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]Creator, error) {
+	creators := make(map[uuid.UUID]Creator)
+	if len(userIDs) == 0 {
+		return creators, nil
 	}
 
-	// Parse user ID
-	userID, err := uuid.Parse(grpcResponse.Id)
+	conn := getConn()
+	defer conn.Close()
+
+	keys := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		keys = append(keys, getCreatorKey(userID))
+	}
+
+	// Check and fetch the details which exists in redis
+	values, err := conn.MGet(ctx, keys...).Result()
 	if err != nil {
 		return nil, err
 	}
 
-	user := &User{
-		ID:    userID,
-		Name:  grpcResponse.Name,
-		Email: grpcResponse.Email,
+	var missingIDs []string
+	for idx, value := range values {
+		if str, ok := value.(string); ok {
+			if creator, err := ByteToCreator([]byte(str)); err == nil {
+				creators[userIDs[idx]] = *creator
+				continue
+			}
+		}
+		missingIDs = append(missingIDs, userIDs[idx].String())
+	}
+
+	if len(missingIDs) == 0 {
+		return creators, nil
 	}
 
-	userByte, err = UserToByte(*user)
+	// fetch missing details via gRPC
+	grpcResponse, err := fetchUsersDetail(missingIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	// Save user detail into redis
-	if err := conn.Set(ctx, token, userByte, 1*time.Hour).Err(); err != nil {
+	pipe := conn.Pipeline()
+	for _, user := range grpcResponse.GetUsers() {
+		userID, err := uuid.Parse(user.GetId())
+		if err != nil {
+			continue
+		}
+
+		creator := Creator{
+			ID:     userID,
+			Name:   user.GetName(),
+			Avatar: user.GetAvatar(),
+		}
+		creators[userID] = creator
+
+		creatorByte, err := CreatorToByte(creator)
+		if err != nil {
+			return nil, err
+		}
+		pipe.Set(ctx, getCreatorKey(userID), creatorByte, 1*time.Hour)
+	}
+
+	// Save creator details into redis
+	if _, err := pipe.Exec(ctx); err != nil {
 		return nil, err
 	}
-	return user, nil
+	return creators, nil
 }
@@ -8,9 +8,11 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	log "github.com/sirupsen/logrus"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/content/api"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/content/internal"
 )
 
 func getLoggerFormat(params gin.LogFormatterParams) string {
@@ -28,18 +30,64 @@ func getLoggerFormat(params gin.LogFormatterParams) string {
 	)
 }
 
+func startBackgroundJobs(dbConfig *database.Config) {
+	// Flush the buffered play events into DB in background
+	go internal.FlushPlayEvents(dbConfig)
+
+	// Refresh the play counts & charts in background
+	go internal.RefreshCharts(dbConfig)
+
+	// Refresh the similar contents used for recommendations in background
+	go internal.RefreshContentSimilarities(dbConfig)
+
+	// Publish the scheduled contents & apply the embargoes in background
+	go internal.ReleaseScheduledContent(dbConfig)
+
+	// Permanently delete the contents from trash after the retention period in background
+	go internal.PurgeDeletedContent(dbConfig)
+
+	// Remove the media objects which are not referenced by any content in background
+	go internal.CollectOrphanedMedia(dbConfig)
+
+	// Abort the multipart uploads which are not completed in time in background
+	go internal.AbortExpiredUploads(dbConfig)
+
+	// Resume the episode media imports which were interrupted by a restart in background
+	go internal.ResumeEpisodeMediaImports(dbConfig)
+}
+
 func main() {
 	engine := gin.Default()
 
-	// DB config
-	conn, err := pgx.Connect(context.Background(), os.Getenv("DB_URL"))
+	// Cursors of the list APIs can't be signed without the secret key
+	if err := api.LoadCursorSecretKey(); err != nil {
+		log.Fatalln("error while loading cursor secret key: ", err)
+	}
+
+	// DB config, Connection pool is shared by the API handlers, gRPC server and the background jobs.
+	// Pool size can be set using the pool_max_conns param of DB_URL.
+	dbPool, err := pgxpool.New(context.Background(), os.Getenv("DB_URL"))
 	if err != nil {
 		log.Fatalln("error while connecting to DB: ", err)
 	}
-	defer conn.Close(context.Background())
+	defer dbPool.Close()
+
+	if err = dbPool.Ping(context.Background()); err != nil {
+		log.Fatalln("error while connecting to DB: ", err)
+	}
+
+	dbConfig := &database.Config{
+		DB:      dbPool,
+		Queries: database.New(dbPool),
+	}
+
+	// Start gRPC server in background
+	go serveGRPC(dbConfig)
+
+	startBackgroundJobs(dbConfig)
 
 	// Load API routes
-	api.Routes(engine, conn)
+	api.Routes(engine, dbConfig)
 
 	// Server config
 	engine.Use(gin.LoggerWithFormatter(getLoggerFormat))
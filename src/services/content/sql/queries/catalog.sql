@@ -0,0 +1,60 @@
+-- name: CreateArtist :one
+INSERT INTO artists (id, created_at, modified_at, name, bio)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetArtistById :one
+SELECT * FROM artists WHERE id=$1;
+
+-- name: AddArtistUser :exec
+INSERT INTO artist_users (artist_id, user_id) VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteUserArtistLinks :exec
+DELETE FROM artist_users WHERE user_id=$1;
+
+-- name: IsArtistUser :one
+SELECT EXISTS(SELECT 1 FROM artist_users WHERE artist_id=$1 AND user_id=$2);
+
+-- name: CreateAlbum :one
+INSERT INTO albums (id, created_at, modified_at, artist_id, title, type, release_date, upc)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetAlbumById :one
+SELECT * FROM albums WHERE id=$1;
+
+-- name: GetArtistAlbums :many
+SELECT * FROM albums WHERE artist_id=sqlc.arg('artist_id')
+AND (sqlc.narg('cursor_id')::UUID IS NULL OR CASE
+    WHEN sqlc.arg('backward')::BOOLEAN
+        THEN (release_date, id) > (sqlc.narg('cursor_release_date')::DATE, sqlc.narg('cursor_id')::UUID)
+    ELSE (release_date, id) < (sqlc.narg('cursor_release_date')::DATE, sqlc.narg('cursor_id')::UUID)
+END)
+ORDER BY
+    CASE WHEN sqlc.arg('backward')::BOOLEAN THEN release_date END ASC,
+    CASE WHEN sqlc.arg('backward')::BOOLEAN THEN id END ASC,
+    release_date DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: AddAlbumTrack :one
+UPDATE content SET album_id=$1, disc_number=$2, track_number=$3, isrc=$4, modified_at=$5
+WHERE id=$6 AND user_id=$7 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetAlbumTracks :many
+SELECT id, title, type, s3_key, disc_number, track_number, isrc FROM content
+WHERE album_id=$1 AND visibility='public' AND released AND deleted_at IS NULL ORDER BY disc_number, track_number;
+
+-- name: DeleteContentArtists :exec
+DELETE FROM content_artists WHERE content_id=$1;
+
+-- name: AddContentArtist :exec
+INSERT INTO content_artists (content_id, artist_id, role) VALUES ($1, $2, $3)
+ON CONFLICT (content_id, artist_id) DO UPDATE SET role=EXCLUDED.role;
+
+-- name: GetContentArtists :many
+SELECT content_artists.content_id, content_artists.role, artists.id, artists.name FROM content_artists
+JOIN artists ON artists.id=content_artists.artist_id
+WHERE content_artists.content_id = ANY($1::uuid[])
+ORDER BY content_artists.role, artists.name;
@@ -0,0 +1,57 @@
+-- name: AddJobCheckpoint :exec
+INSERT INTO job_checkpoints (name, processed_until) VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: GetJobCheckpoint :one
+SELECT processed_until FROM job_checkpoints WHERE name=$1 FOR UPDATE;
+
+-- name: UpdateJobCheckpoint :exec
+UPDATE job_checkpoints SET processed_until=$1 WHERE name=$2;
+
+-- name: AddPlayCounts :exec
+INSERT INTO content_play_counts (content_id, total_plays, modified_at)
+SELECT plays.content_id, COUNT(*), sqlc.arg('modified_at')::TIMESTAMP FROM play_events AS plays
+JOIN content ON content.id=plays.content_id
+WHERE plays.received_at >= sqlc.arg('processed_from') AND plays.received_at < sqlc.arg('processed_until')
+AND plays.duration_played >= sqlc.arg('min_duration')
+AND NOT EXISTS (
+    SELECT 1 FROM play_events AS previous
+    WHERE previous.user_id=plays.user_id AND previous.content_id=plays.content_id
+    AND previous.duration_played >= sqlc.arg('min_duration')
+    AND previous.played_at >= plays.played_at - INTERVAL '30 minutes'
+    AND (previous.played_at < plays.played_at OR (previous.played_at = plays.played_at AND previous.id < plays.id))
+)
+GROUP BY plays.content_id
+ON CONFLICT (content_id) DO UPDATE
+SET total_plays=content_play_counts.total_plays + EXCLUDED.total_plays, modified_at=EXCLUDED.modified_at;
+
+-- name: RefreshWeeklyPlayCounts :exec
+WITH weekly AS (
+    SELECT plays.content_id, COUNT(*) AS plays FROM play_events AS plays
+    WHERE plays.received_at >= sqlc.arg('week_start') AND plays.received_at < sqlc.arg('processed_until')
+    AND plays.duration_played >= sqlc.arg('min_duration')
+    AND NOT EXISTS (
+        SELECT 1 FROM play_events AS previous
+        WHERE previous.user_id=plays.user_id AND previous.content_id=plays.content_id
+        AND previous.duration_played >= sqlc.arg('min_duration')
+        AND previous.played_at >= plays.played_at - INTERVAL '30 minutes'
+        AND (previous.played_at < plays.played_at OR (previous.played_at = plays.played_at AND previous.id < plays.id))
+    )
+    GROUP BY plays.content_id
+)
+UPDATE content_play_counts
+SET weekly_plays=COALESCE((SELECT weekly.plays FROM weekly WHERE weekly.content_id=content_play_counts.content_id), 0),
+    modified_at=sqlc.arg('modified_at')
+WHERE content_play_counts.weekly_plays > 0 OR content_play_counts.content_id IN (SELECT content_id FROM weekly);
+
+-- name: GetTrendingContent :many
+SELECT content.id, content.title, content.type, content.s3_key, content_play_counts.weekly_plays AS plays FROM content_play_counts
+JOIN content ON content.id=content_play_counts.content_id
+WHERE content.visibility='public' AND content.released AND content.deleted_at IS NULL AND content_play_counts.weekly_plays > 0
+ORDER BY content_play_counts.weekly_plays DESC, content.id LIMIT $1;
+
+-- name: GetTopContent :many
+SELECT content.id, content.title, content.type, content.s3_key, content_play_counts.total_plays AS plays FROM content_play_counts
+JOIN content ON content.id=content_play_counts.content_id
+WHERE content.type=$1 AND content.visibility='public' AND content.released AND content.deleted_at IS NULL AND content_play_counts.total_plays > 0
+ORDER BY content_play_counts.total_plays DESC, content.id LIMIT $2;
@@ -1,25 +1,120 @@
 -- name: AddContent :one
-INSERT INTO content (id, created_at, modified_at, user_id, title, description, type) 
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO content (id, created_at, modified_at, user_id, title, description, type, visibility, publish_at, unpublish_at, released) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING *;
 
 -- name: GetContentById :one
-SELECT * FROM content WHERE id=$1 FOR UPDATE NOWAIT;
+SELECT * FROM content WHERE id=$1 AND deleted_at IS NULL FOR UPDATE NOWAIT;
+
+-- name: GetContentByShareToken :one
+SELECT * FROM content WHERE share_token=$1 AND deleted_at IS NULL;
+
+-- name: RotateShareToken :one
+UPDATE content SET share_token=gen_random_uuid(), modified_at=$1
+WHERE id=$2 AND user_id=$3 AND deleted_at IS NULL
+RETURNING share_token;
 
 -- name: GetUserContent :many
-SELECT id, created_at, title, description, type FROM content WHERE user_id=$1 ORDER BY created_at DESC LIMIT $2 OFFSET $3;
+SELECT content.id, content.created_at, content.user_id, content.title, content.description, content.type,
+    COALESCE(content_play_counts.total_plays, 0)::BIGINT AS plays
+FROM content
+LEFT JOIN content_play_counts ON content_play_counts.content_id=content.id
+WHERE content.user_id=sqlc.arg('user_id') AND content.deleted_at IS NULL
+AND (sqlc.narg('cursor_id')::UUID IS NULL OR CASE
+    WHEN sqlc.arg('sort')::TEXT = 'title' AND sqlc.arg('backward')::BOOLEAN
+        THEN (content.title, content.id) < (sqlc.narg('cursor_title')::TEXT, sqlc.narg('cursor_id')::UUID)
+    WHEN sqlc.arg('sort')::TEXT = 'title'
+        THEN (content.title, content.id) > (sqlc.narg('cursor_title')::TEXT, sqlc.narg('cursor_id')::UUID)
+    WHEN sqlc.arg('sort')::TEXT = 'popularity' AND sqlc.arg('backward')::BOOLEAN
+        THEN (COALESCE(content_play_counts.total_plays, 0), content.id) > (sqlc.narg('cursor_plays')::BIGINT, sqlc.narg('cursor_id')::UUID)
+    WHEN sqlc.arg('sort')::TEXT = 'popularity'
+        THEN (COALESCE(content_play_counts.total_plays, 0), content.id) < (sqlc.narg('cursor_plays')::BIGINT, sqlc.narg('cursor_id')::UUID)
+    WHEN sqlc.arg('backward')::BOOLEAN
+        THEN (content.created_at, content.id) > (sqlc.narg('cursor_created_at')::TIMESTAMP, sqlc.narg('cursor_id')::UUID)
+    ELSE (content.created_at, content.id) < (sqlc.narg('cursor_created_at')::TIMESTAMP, sqlc.narg('cursor_id')::UUID)
+END)
+ORDER BY
+    CASE WHEN sqlc.arg('sort')::TEXT = 'title' AND NOT sqlc.arg('backward')::BOOLEAN THEN content.title END ASC,
+    CASE WHEN sqlc.arg('sort')::TEXT = 'title' AND sqlc.arg('backward')::BOOLEAN THEN content.title END DESC,
+    CASE WHEN sqlc.arg('sort')::TEXT = 'popularity' AND NOT sqlc.arg('backward')::BOOLEAN THEN COALESCE(content_play_counts.total_plays, 0) END DESC,
+    CASE WHEN sqlc.arg('sort')::TEXT = 'popularity' AND sqlc.arg('backward')::BOOLEAN THEN COALESCE(content_play_counts.total_plays, 0) END ASC,
+    CASE WHEN sqlc.arg('sort')::TEXT = 'newest' AND NOT sqlc.arg('backward')::BOOLEAN THEN content.created_at END DESC,
+    CASE WHEN sqlc.arg('sort')::TEXT = 'newest' AND sqlc.arg('backward')::BOOLEAN THEN content.created_at END ASC,
+    CASE WHEN (sqlc.arg('sort')::TEXT = 'title') <> sqlc.arg('backward')::BOOLEAN THEN content.id END ASC,
+    content.id DESC
+LIMIT sqlc.arg('limit');
 
 -- name: GetContentList :many
-SELECT id, created_at, title, description, type FROM content ORDER BY created_at DESC LIMIT $1 OFFSET $2;
+SELECT content.id, content.created_at, content.user_id, content.title, content.description, content.type,
+    COALESCE(content_play_counts.total_plays, 0)::BIGINT AS plays
+FROM content
+LEFT JOIN content_play_counts ON content_play_counts.content_id=content.id
+WHERE content.visibility='public' AND content.released AND content.deleted_at IS NULL
+AND (cardinality(sqlc.arg('genres')::TEXT[]) = 0 OR content.id IN (
+    SELECT content_id FROM content_genres WHERE genre_slug = ANY(sqlc.arg('genres')::TEXT[])
+))
+AND (cardinality(sqlc.arg('tags')::TEXT[]) = 0 OR content.id IN (
+    SELECT content_id FROM content_tags WHERE tag = ANY(sqlc.arg('tags')::TEXT[])
+))
+AND (sqlc.narg('cursor_id')::UUID IS NULL OR CASE
+    WHEN sqlc.arg('sort')::TEXT = 'title' AND sqlc.arg('backward')::BOOLEAN
+        THEN (content.title, content.id) < (sqlc.narg('cursor_title')::TEXT, sqlc.narg('cursor_id')::UUID)
+    WHEN sqlc.arg('sort')::TEXT = 'title'
+        THEN (content.title, content.id) > (sqlc.narg('cursor_title')::TEXT, sqlc.narg('cursor_id')::UUID)
+    WHEN sqlc.arg('sort')::TEXT = 'popularity' AND sqlc.arg('backward')::BOOLEAN
+        THEN (COALESCE(content_play_counts.total_plays, 0), content.id) > (sqlc.narg('cursor_plays')::BIGINT, sqlc.narg('cursor_id')::UUID)
+    WHEN sqlc.arg('sort')::TEXT = 'popularity'
+        THEN (COALESCE(content_play_counts.total_plays, 0), content.id) < (sqlc.narg('cursor_plays')::BIGINT, sqlc.narg('cursor_id')::UUID)
+    WHEN sqlc.arg('backward')::BOOLEAN
+        THEN (content.created_at, content.id) > (sqlc.narg('cursor_created_at')::TIMESTAMP, sqlc.narg('cursor_id')::UUID)
+    ELSE (content.created_at, content.id) < (sqlc.narg('cursor_created_at')::TIMESTAMP, sqlc.narg('cursor_id')::UUID)
+END)
+ORDER BY
+    CASE WHEN sqlc.arg('sort')::TEXT = 'title' AND NOT sqlc.arg('backward')::BOOLEAN THEN content.title END ASC,
+    CASE WHEN sqlc.arg('sort')::TEXT = 'title' AND sqlc.arg('backward')::BOOLEAN THEN content.title END DESC,
+    CASE WHEN sqlc.arg('sort')::TEXT = 'popularity' AND NOT sqlc.arg('backward')::BOOLEAN THEN COALESCE(content_play_counts.total_plays, 0) END DESC,
+    CASE WHEN sqlc.arg('sort')::TEXT = 'popularity' AND sqlc.arg('backward')::BOOLEAN THEN COALESCE(content_play_counts.total_plays, 0) END ASC,
+    CASE WHEN sqlc.arg('sort')::TEXT = 'newest' AND NOT sqlc.arg('backward')::BOOLEAN THEN content.created_at END DESC,
+    CASE WHEN sqlc.arg('sort')::TEXT = 'newest' AND sqlc.arg('backward')::BOOLEAN THEN content.created_at END ASC,
+    CASE WHEN (sqlc.arg('sort')::TEXT = 'title') <> sqlc.arg('backward')::BOOLEAN THEN content.id END ASC,
+    content.id DESC
+LIMIT sqlc.arg('limit');
 
 -- name: UpdateContentDetails :one
-UPDATE content SET title=$1, description=$2, type=$3, modified_at=$4
-WHERE id=$5 AND user_id=$6
+UPDATE content SET title=$1, description=$2, type=$3, visibility=$4, publish_at=$5, unpublish_at=$6, released=$7, modified_at=$8
+WHERE id=$9 AND user_id=$10 AND deleted_at IS NULL
 RETURNING *;
 
--- name: UpdateS3Key :exec
-UPDATE content SET s3_key=$1, modified_at=$2
-WHERE id=$3 AND user_id=$4;
-
 -- name: DeleteContent :exec
-DELETE FROM content WHERE id=$1 AND user_id=$2;
\ No newline at end of file
+UPDATE content SET deleted_at=$1, modified_at=$1
+WHERE id=$2 AND user_id=$3 AND deleted_at IS NULL;
+
+-- name: DeleteUserContent :many
+WITH deleted AS (
+    DELETE FROM content WHERE user_id=$1
+    RETURNING id, s3_key, download_key
+)
+SELECT deleted.id, deleted.s3_key AS key FROM deleted
+UNION
+SELECT deleted.id, deleted.download_key FROM deleted
+UNION
+SELECT deleted.id, COALESCE(content_media_versions.s3_key, content_media_versions.source_key) FROM content_media_versions
+JOIN deleted ON deleted.id=content_media_versions.content_id
+UNION
+SELECT deleted.id, content_media_versions.download_key FROM content_media_versions
+JOIN deleted ON deleted.id=content_media_versions.content_id;
+
+-- name: GetAllUserContent :many
+SELECT * FROM content WHERE user_id=$1 ORDER BY created_at;
+
+-- name: GetViewableContentIDs :many
+SELECT id FROM content
+WHERE id = ANY(sqlc.arg('ids')::UUID[]) AND deleted_at IS NULL
+AND ((visibility<>'private' AND released) OR user_id=sqlc.arg('user_id'));
+
+-- name: GetMediaKeys :many
+SELECT s3_key, download_key FROM content
+WHERE s3_key IS NOT NULL OR download_key IS NOT NULL
+UNION ALL
+SELECT COALESCE(s3_key, source_key), download_key FROM content_media_versions
+WHERE status<>'failed' OR modified_at >= sqlc.arg('failed_before');
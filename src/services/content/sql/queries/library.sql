@@ -0,0 +1,50 @@
+-- name: LikeContent :exec
+INSERT INTO content_likes (id, created_at, user_id, content_id) VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, content_id) DO NOTHING;
+
+-- name: UnlikeContent :exec
+DELETE FROM content_likes WHERE user_id=$1 AND content_id=$2;
+
+-- name: GetContentLikeCount :one
+SELECT COUNT(*) FROM content_likes WHERE content_id=$1;
+
+-- name: SaveAlbum :exec
+INSERT INTO saved_albums (id, created_at, user_id, album_id) VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, album_id) DO NOTHING;
+
+-- name: UnsaveAlbum :exec
+DELETE FROM saved_albums WHERE user_id=$1 AND album_id=$2;
+
+-- name: GetUserLibrary :many
+SELECT item_id, item_type, title, s3_key, added_at FROM (
+    SELECT content.id AS item_id, content.type::TEXT AS item_type, content.title, content.s3_key, content_likes.created_at AS added_at
+    FROM content_likes JOIN content ON content.id=content_likes.content_id
+    WHERE content_likes.user_id=sqlc.arg('user_id') AND content.deleted_at IS NULL AND ((content.visibility<>'private' AND content.released) OR content.user_id=sqlc.arg('user_id'))
+    UNION ALL
+    SELECT albums.id, 'album', albums.title, NULL, saved_albums.created_at
+    FROM saved_albums JOIN albums ON albums.id=saved_albums.album_id
+    WHERE saved_albums.user_id=sqlc.arg('user_id')
+) AS library
+WHERE (sqlc.narg('item_type')::TEXT IS NULL OR item_type=sqlc.narg('item_type')::TEXT)
+AND (sqlc.narg('cursor_id')::UUID IS NULL OR CASE
+    WHEN sqlc.arg('oldest_first')::BOOLEAN <> sqlc.arg('backward')::BOOLEAN
+        THEN (added_at, item_id) > (sqlc.narg('cursor_added_at')::TIMESTAMP, sqlc.narg('cursor_id')::UUID)
+    ELSE (added_at, item_id) < (sqlc.narg('cursor_added_at')::TIMESTAMP, sqlc.narg('cursor_id')::UUID)
+END)
+ORDER BY
+    CASE WHEN sqlc.arg('oldest_first')::BOOLEAN <> sqlc.arg('backward')::BOOLEAN THEN added_at END ASC,
+    CASE WHEN sqlc.arg('oldest_first')::BOOLEAN <> sqlc.arg('backward')::BOOLEAN THEN item_id END ASC,
+    added_at DESC, item_id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: DeleteUserLibrary :exec
+WITH deleted_likes AS (
+    DELETE FROM content_likes WHERE content_likes.user_id=$1
+)
+DELETE FROM saved_albums WHERE saved_albums.user_id=$1;
+
+-- name: GetAllUserLikes :many
+SELECT * FROM content_likes WHERE user_id=$1 ORDER BY created_at;
+
+-- name: GetAllUserSavedAlbums :many
+SELECT * FROM saved_albums WHERE user_id=$1 ORDER BY created_at;
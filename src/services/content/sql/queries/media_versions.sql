@@ -0,0 +1,57 @@
+-- name: AddMediaVersion :one
+INSERT INTO content_media_versions (id, created_at, modified_at, content_id, version, source_key, status)
+SELECT sqlc.arg('id')::UUID, sqlc.arg('created_at')::TIMESTAMP, sqlc.arg('created_at')::TIMESTAMP, content.id,
+    COALESCE((SELECT MAX(version) FROM content_media_versions WHERE content_media_versions.content_id=content.id), 0) + 1,
+    sqlc.arg('source_key')::TEXT, 'processing'
+FROM content WHERE content.id=sqlc.arg('content_id') AND content.deleted_at IS NULL
+AND (sqlc.narg('user_id')::UUID IS NULL OR content.user_id=sqlc.narg('user_id')::UUID)
+ON CONFLICT (source_key) DO NOTHING
+RETURNING *;
+
+-- name: GetMediaVersionBySourceKey :one
+SELECT * FROM content_media_versions WHERE content_id=$1 AND source_key=$2;
+
+-- name: MarkMediaVersionReady :exec
+UPDATE content_media_versions SET s3_key=$1, download_key=$2, download_size=$3, duration=$4, status='ready', modified_at=$5
+WHERE id=$6;
+
+-- name: MarkMediaVersionFailed :exec
+UPDATE content_media_versions SET status='failed', modified_at=$1
+WHERE id=$2;
+
+-- name: PromoteMediaVersion :execrows
+UPDATE content SET media_version_id=content_media_versions.id, s3_key=content_media_versions.s3_key,
+    download_key=content_media_versions.download_key, download_size=content_media_versions.download_size,
+    duration=content_media_versions.duration, modified_at=sqlc.arg('modified_at')
+FROM content_media_versions
+WHERE content_media_versions.id=sqlc.arg('version_id') AND content_media_versions.status='ready'
+AND content.id=content_media_versions.content_id
+AND (content.media_version_id IS NULL OR content_media_versions.version > (
+    SELECT current_version.version FROM content_media_versions current_version WHERE current_version.id=content.media_version_id
+));
+
+-- name: RollbackMediaVersion :execrows
+UPDATE content SET media_version_id=content_media_versions.id, s3_key=content_media_versions.s3_key,
+    download_key=content_media_versions.download_key, download_size=content_media_versions.download_size,
+    duration=content_media_versions.duration, modified_at=sqlc.arg('modified_at')
+FROM content_media_versions
+WHERE content_media_versions.id=sqlc.arg('version_id') AND content_media_versions.status='ready'
+AND content.id=content_media_versions.content_id
+AND content.id=sqlc.arg('content_id') AND content.user_id=sqlc.arg('user_id') AND content.deleted_at IS NULL;
+
+-- name: GetContentMediaVersions :many
+SELECT * FROM content_media_versions WHERE content_id=$1 ORDER BY version DESC;
+
+-- name: DeleteSupersededMediaVersions :many
+DELETE FROM content_media_versions
+WHERE content_media_versions.content_id=sqlc.arg('content_id') AND content_media_versions.status<>'processing'
+AND content_media_versions.id NOT IN (
+    SELECT content.media_version_id FROM content
+    WHERE content.id=sqlc.arg('content_id') AND content.media_version_id IS NOT NULL
+)
+AND content_media_versions.id NOT IN (
+    SELECT kept.id FROM content_media_versions kept
+    WHERE kept.content_id=sqlc.arg('content_id') AND kept.status='ready'
+    ORDER BY kept.version DESC LIMIT sqlc.arg('keep')
+)
+RETURNING COALESCE(s3_key, source_key)::TEXT AS media_key;
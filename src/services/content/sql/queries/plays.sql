@@ -0,0 +1,31 @@
+-- name: AddPlayEvents :copyfrom
+INSERT INTO play_events (id, received_at, played_at, user_id, content_id, type, position, duration_played, client)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: CreatePlayEventsPartition :exec
+SELECT create_play_events_partition(sqlc.arg('month_start')::TIMESTAMP);
+
+-- name: GetUserPlayHistory :many
+SELECT content.id, content.title, content.type, content.s3_key, history.played_at FROM (
+    SELECT content_id, MAX(played_at)::TIMESTAMP AS played_at FROM play_events
+    WHERE play_events.user_id=sqlc.arg('user_id') AND received_at >= sqlc.arg('received_at')
+    GROUP BY content_id
+) AS history
+JOIN content ON content.id=history.content_id
+WHERE content.deleted_at IS NULL AND content.deleted_at IS NULL AND ((content.visibility<>'private' AND content.released) OR content.user_id=sqlc.arg('user_id'))
+AND (sqlc.narg('cursor_id')::UUID IS NULL OR CASE
+    WHEN sqlc.arg('backward')::BOOLEAN
+        THEN (history.played_at, content.id) > (sqlc.narg('cursor_played_at')::TIMESTAMP, sqlc.narg('cursor_id')::UUID)
+    ELSE (history.played_at, content.id) < (sqlc.narg('cursor_played_at')::TIMESTAMP, sqlc.narg('cursor_id')::UUID)
+END)
+ORDER BY
+    CASE WHEN sqlc.arg('backward')::BOOLEAN THEN history.played_at END ASC,
+    CASE WHEN sqlc.arg('backward')::BOOLEAN THEN content.id END ASC,
+    history.played_at DESC, content.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: DeleteUserPlayEvents :exec
+DELETE FROM play_events WHERE user_id=$1;
+
+-- name: GetAllUserPlayEvents :many
+SELECT * FROM play_events WHERE user_id=$1 ORDER BY played_at, id;
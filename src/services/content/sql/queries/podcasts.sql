@@ -0,0 +1,54 @@
+-- name: CreateShow :one
+INSERT INTO podcast_shows (id, created_at, modified_at, user_id, title, author, description, artwork_url, categories, explicit, feed_url, language)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING *;
+
+-- name: GetShowById :one
+SELECT * FROM podcast_shows WHERE id=$1;
+
+-- name: AddEpisode :one
+INSERT INTO content (id, created_at, modified_at, user_id, title, description, type, show_id, season_number, episode_number, published_at, show_notes, guid, chapters_url, transcript_url)
+VALUES ($1, $2, $3, $4, $5, $6, 'P', $7, $8, $9, $10, $11, $12, $13, $14)
+ON CONFLICT DO NOTHING
+RETURNING *;
+
+-- name: GetShowEpisodes :many
+SELECT id, title, description, s3_key, season_number, episode_number, published_at,
+    COALESCE(published_at, created_at)::TIMESTAMP AS released_at
+FROM content
+WHERE show_id=sqlc.arg('show_id') AND visibility='public' AND released AND deleted_at IS NULL
+AND (sqlc.narg('cursor_id')::UUID IS NULL OR CASE
+    WHEN sqlc.arg('backward')::BOOLEAN
+        THEN (COALESCE(published_at, created_at), id) > (sqlc.narg('cursor_released_at')::TIMESTAMP, sqlc.narg('cursor_id')::UUID)
+    ELSE (COALESCE(published_at, created_at), id) < (sqlc.narg('cursor_released_at')::TIMESTAMP, sqlc.narg('cursor_id')::UUID)
+END)
+ORDER BY
+    CASE WHEN sqlc.arg('backward')::BOOLEAN THEN COALESCE(published_at, created_at) END ASC,
+    CASE WHEN sqlc.arg('backward')::BOOLEAN THEN id END ASC,
+    COALESCE(published_at, created_at) DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: DeleteUserShows :exec
+DELETE FROM podcast_shows WHERE user_id=$1;
+
+-- name: GetShowFeedEpisodes :many
+SELECT id, modified_at, title, description, download_key, download_size, duration, season_number, episode_number, published_at, show_notes, guid, chapters_url, transcript_url FROM content
+WHERE show_id=$1 AND visibility='public' AND released AND deleted_at IS NULL AND download_key IS NOT NULL AND (published_at IS NULL OR published_at <= $2)
+ORDER BY published_at DESC NULLS LAST, created_at DESC LIMIT $3;
+
+-- name: AddEpisodeMediaImport :exec
+INSERT INTO episode_media_imports (content_id, created_at, user_id, enclosure_url, enclosure_type)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetPendingEpisodeMediaImports :many
+SELECT i.* FROM episode_media_imports i
+JOIN content c ON c.id=i.content_id
+WHERE c.deleted_at IS NULL AND i.attempts < $1
+AND NOT EXISTS (SELECT 1 FROM content_media_versions v WHERE v.content_id=i.content_id)
+ORDER BY i.created_at;
+
+-- name: MarkEpisodeMediaImportFailed :exec
+UPDATE episode_media_imports SET attempts=attempts+1 WHERE content_id=$1;
+
+-- name: DeleteEpisodeMediaImport :exec
+DELETE FROM episode_media_imports WHERE content_id=$1;
@@ -0,0 +1,16 @@
+-- name: UpsertPlaybackPosition :exec
+INSERT INTO playback_positions (user_id, content_id, position, played, client_updated_at, modified_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id, content_id) DO UPDATE
+SET position=EXCLUDED.position, played=playback_positions.played OR EXCLUDED.played,
+    client_updated_at=EXCLUDED.client_updated_at, modified_at=EXCLUDED.modified_at
+WHERE playback_positions.client_updated_at < EXCLUDED.client_updated_at;
+
+-- name: GetPlaybackPosition :one
+SELECT * FROM playback_positions WHERE user_id=$1 AND content_id=$2;
+
+-- name: DeleteUserPlaybackPositions :exec
+DELETE FROM playback_positions WHERE user_id=$1;
+
+-- name: GetAllUserPlaybackPositions :many
+SELECT * FROM playback_positions WHERE user_id=$1 ORDER BY modified_at;
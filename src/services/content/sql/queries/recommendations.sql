@@ -0,0 +1,99 @@
+-- name: DeleteContentSimilarities :exec
+DELETE FROM content_similarities;
+
+-- name: AddContentSimilarities :exec
+WITH listens AS (
+    SELECT DISTINCT play_events.user_id, play_events.content_id FROM play_events
+    JOIN content ON content.id=play_events.content_id
+    WHERE play_events.received_at >= sqlc.arg('listened_after') AND play_events.duration_played >= sqlc.arg('min_duration')
+),
+listeners AS (
+    SELECT content_id, COUNT(*) AS total FROM listens GROUP BY content_id
+),
+co_listens AS (
+    SELECT a.content_id, b.content_id AS similar_content_id, COUNT(*) / SQRT(MAX(la.total) * MAX(lb.total)) AS score
+    FROM listens AS a
+    JOIN listens AS b ON b.user_id=a.user_id AND b.content_id<>a.content_id
+    JOIN listeners AS la ON la.content_id=a.content_id
+    JOIN listeners AS lb ON lb.content_id=b.content_id
+    GROUP BY a.content_id, b.content_id
+),
+shared_artists AS (
+    SELECT a.content_id, b.content_id AS similar_content_id, COUNT(*) * sqlc.arg('artist_weight')::DOUBLE PRECISION AS score
+    FROM content_artists AS a
+    JOIN content_artists AS b ON b.artist_id=a.artist_id AND b.content_id<>a.content_id
+    GROUP BY a.content_id, b.content_id
+),
+labels AS (
+    SELECT content_id, 'genre:' || genre_slug AS label FROM content_genres
+    UNION ALL
+    SELECT content_id, 'tag:' || tag AS label FROM content_tags
+    WHERE tag IN (SELECT tag FROM content_tags GROUP BY tag HAVING COUNT(*) <= 200)
+),
+shared_labels AS (
+    SELECT a.content_id, b.content_id AS similar_content_id, COUNT(*) * sqlc.arg('label_weight')::DOUBLE PRECISION AS score
+    FROM labels AS a
+    JOIN labels AS b ON b.label=a.label AND b.content_id<>a.content_id
+    GROUP BY a.content_id, b.content_id
+),
+ranked AS (
+    SELECT content_id, similar_content_id, SUM(score) AS score,
+        ROW_NUMBER() OVER (PARTITION BY content_id ORDER BY SUM(score) DESC, similar_content_id) AS rank
+    FROM (
+        SELECT content_id, similar_content_id, score FROM co_listens
+        UNION ALL
+        SELECT content_id, similar_content_id, score FROM shared_artists
+        UNION ALL
+        SELECT content_id, similar_content_id, score FROM shared_labels
+    ) AS signals
+    GROUP BY content_id, similar_content_id
+)
+INSERT INTO content_similarities (content_id, similar_content_id, score, created_at)
+SELECT content_id, similar_content_id, score, sqlc.arg('created_at')::TIMESTAMP FROM ranked
+WHERE rank <= sqlc.arg('neighbors');
+
+-- name: GetSimilarContent :many
+SELECT content.id, content.title, content.type, content.s3_key, content_similarities.score FROM content_similarities
+JOIN content ON content.id=content_similarities.similar_content_id
+WHERE content_similarities.content_id=sqlc.arg('content_id') AND content.visibility='public' AND content.released AND content.deleted_at IS NULL
+AND (sqlc.narg('cursor_id')::UUID IS NULL OR CASE
+    WHEN sqlc.arg('backward')::BOOLEAN
+        THEN (content_similarities.score, content.id) > (sqlc.narg('cursor_score')::DOUBLE PRECISION, sqlc.narg('cursor_id')::UUID)
+    ELSE (content_similarities.score, content.id) < (sqlc.narg('cursor_score')::DOUBLE PRECISION, sqlc.narg('cursor_id')::UUID)
+END)
+ORDER BY
+    CASE WHEN sqlc.arg('backward')::BOOLEAN THEN content_similarities.score END ASC,
+    CASE WHEN sqlc.arg('backward')::BOOLEAN THEN content.id END ASC,
+    content_similarities.score DESC, content.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetUserRecommendations :many
+WITH seeds AS (
+    (
+        SELECT content_id FROM play_events
+        WHERE play_events.user_id=sqlc.arg('user_id') AND received_at >= sqlc.arg('listened_after')
+        GROUP BY content_id ORDER BY MAX(played_at) DESC LIMIT 50
+    )
+    UNION
+    (
+        SELECT content_id FROM content_likes
+        WHERE content_likes.user_id=sqlc.arg('user_id')
+        ORDER BY created_at DESC LIMIT 50
+    )
+)
+SELECT content.id, content.title, content.type, content.s3_key, SUM(content_similarities.score)::DOUBLE PRECISION AS score
+FROM content_similarities
+JOIN seeds ON seeds.content_id=content_similarities.content_id
+JOIN content ON content.id=content_similarities.similar_content_id
+WHERE content.visibility='public' AND content.released AND content.deleted_at IS NULL AND content_similarities.similar_content_id NOT IN (SELECT content_id FROM seeds)
+GROUP BY content.id
+HAVING sqlc.narg('cursor_id')::UUID IS NULL OR CASE
+    WHEN sqlc.arg('backward')::BOOLEAN
+        THEN (SUM(content_similarities.score), content.id) > (sqlc.narg('cursor_score')::DOUBLE PRECISION, sqlc.narg('cursor_id')::UUID)
+    ELSE (SUM(content_similarities.score), content.id) < (sqlc.narg('cursor_score')::DOUBLE PRECISION, sqlc.narg('cursor_id')::UUID)
+END
+ORDER BY
+    CASE WHEN sqlc.arg('backward')::BOOLEAN THEN SUM(content_similarities.score) END ASC,
+    CASE WHEN sqlc.arg('backward')::BOOLEAN THEN content.id END ASC,
+    score DESC, content.id DESC
+LIMIT sqlc.arg('limit');
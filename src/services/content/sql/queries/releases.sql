@@ -0,0 +1,22 @@
+-- name: ReleaseScheduledContent :execrows
+WITH released AS (
+    UPDATE content SET released=TRUE, modified_at=sqlc.arg('now')
+    WHERE NOT released AND deleted_at IS NULL AND (publish_at IS NULL OR publish_at <= sqlc.arg('now'))
+    AND (unpublish_at IS NULL OR unpublish_at > sqlc.arg('now'))
+    RETURNING id, user_id, title, type, publish_at
+)
+INSERT INTO content_release_events (id, created_at, content_id, user_id, title, type, published_at)
+SELECT gen_random_uuid(), sqlc.arg('now'), id, user_id, title, type, COALESCE(publish_at, sqlc.arg('now')) FROM released;
+
+-- name: UnpublishExpiredContent :execrows
+UPDATE content SET released=FALSE, modified_at=sqlc.arg('now')
+WHERE released AND unpublish_at <= sqlc.arg('now');
+
+-- name: GetPendingReleaseEvents :many
+SELECT * FROM content_release_events
+ORDER BY created_at, id
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: DeleteReleaseEvents :exec
+DELETE FROM content_release_events WHERE id = ANY(sqlc.arg('ids')::UUID[]);
@@ -0,0 +1,51 @@
+-- name: GetGenres :many
+SELECT * FROM genres ORDER BY type, name;
+
+-- name: AddContentGenres :exec
+INSERT INTO content_genres (content_id, genre_slug)
+SELECT sqlc.arg('content_id')::UUID, unnest(sqlc.arg('genre_slugs')::TEXT[])
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteContentGenres :exec
+DELETE FROM content_genres WHERE content_id=$1;
+
+-- name: GetContentGenres :many
+SELECT genre_slug FROM content_genres WHERE content_id=$1 ORDER BY genre_slug;
+
+-- name: AddContentTags :exec
+INSERT INTO content_tags (content_id, tag)
+SELECT sqlc.arg('content_id')::UUID, unnest(sqlc.arg('tags')::TEXT[])
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteContentTags :exec
+DELETE FROM content_tags WHERE content_id=$1;
+
+-- name: GetContentTags :many
+SELECT tag FROM content_tags WHERE content_id=$1 ORDER BY tag;
+
+-- name: GetGenreFacets :many
+SELECT content_genres.genre_slug AS value, COUNT(*) AS count FROM content_genres
+JOIN content ON content.id=content_genres.content_id
+WHERE content.visibility='public' AND content.released AND content.deleted_at IS NULL
+AND (cardinality(sqlc.arg('genres')::TEXT[]) = 0 OR content.id IN (
+    SELECT content_id FROM content_genres WHERE genre_slug = ANY(sqlc.arg('genres')::TEXT[])
+))
+AND (cardinality(sqlc.arg('tags')::TEXT[]) = 0 OR content.id IN (
+    SELECT content_id FROM content_tags WHERE tag = ANY(sqlc.arg('tags')::TEXT[])
+))
+GROUP BY content_genres.genre_slug
+ORDER BY count DESC, value;
+
+-- name: GetTagFacets :many
+SELECT content_tags.tag AS value, COUNT(*) AS count FROM content_tags
+JOIN content ON content.id=content_tags.content_id
+WHERE content.visibility='public' AND content.released AND content.deleted_at IS NULL
+AND (cardinality(sqlc.arg('genres')::TEXT[]) = 0 OR content.id IN (
+    SELECT content_id FROM content_genres WHERE genre_slug = ANY(sqlc.arg('genres')::TEXT[])
+))
+AND (cardinality(sqlc.arg('tags')::TEXT[]) = 0 OR content.id IN (
+    SELECT content_id FROM content_tags WHERE tag = ANY(sqlc.arg('tags')::TEXT[])
+))
+GROUP BY content_tags.tag
+ORDER BY count DESC, value
+LIMIT sqlc.arg('limit');
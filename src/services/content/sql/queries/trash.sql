@@ -0,0 +1,33 @@
+-- name: GetUserTrash :many
+SELECT id, title, type, deleted_at FROM content
+WHERE user_id=sqlc.arg('user_id') AND deleted_at IS NOT NULL
+AND (sqlc.narg('cursor_id')::UUID IS NULL OR CASE
+    WHEN sqlc.arg('backward')::BOOLEAN
+        THEN (deleted_at, id) > (sqlc.narg('cursor_deleted_at')::TIMESTAMP, sqlc.narg('cursor_id')::UUID)
+    ELSE (deleted_at, id) < (sqlc.narg('cursor_deleted_at')::TIMESTAMP, sqlc.narg('cursor_id')::UUID)
+END)
+ORDER BY
+    CASE WHEN sqlc.arg('backward')::BOOLEAN THEN deleted_at END ASC,
+    CASE WHEN sqlc.arg('backward')::BOOLEAN THEN id END ASC,
+    deleted_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: RestoreContent :one
+UPDATE content SET deleted_at=NULL, modified_at=$1
+WHERE id=$2 AND user_id=$3 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeDeletedContent :many
+WITH deleted AS (
+    DELETE FROM content WHERE deleted_at <= $1
+    RETURNING id, s3_key, download_key
+)
+SELECT deleted.s3_key FROM deleted
+UNION
+SELECT deleted.download_key FROM deleted
+UNION
+SELECT COALESCE(content_media_versions.s3_key, content_media_versions.source_key) FROM content_media_versions
+JOIN deleted ON deleted.id=content_media_versions.content_id
+UNION
+SELECT content_media_versions.download_key FROM content_media_versions
+JOIN deleted ON deleted.id=content_media_versions.content_id;
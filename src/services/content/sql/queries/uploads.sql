@@ -0,0 +1,28 @@
+-- name: CreateUploadSession :one
+INSERT INTO upload_sessions (id, created_at, modified_at, expires_at, user_id, content_id, s3_key, s3_upload_id, is_audio_file)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetUploadSession :one
+SELECT * FROM upload_sessions WHERE id=$1 AND user_id=$2;
+
+-- name: UpdateUploadSessionStatus :exec
+UPDATE upload_sessions SET status=$1, modified_at=$2
+WHERE id=$3;
+
+-- name: GetExpiredUploadSessions :many
+SELECT * FROM upload_sessions WHERE status='active' AND expires_at <= $1
+ORDER BY expires_at LIMIT $2;
+
+-- name: CreateMediaUpload :exec
+INSERT INTO media_uploads (s3_key, created_at, user_id, content_id, is_audio_file)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetIssuedUpload :one
+SELECT content_id, user_id, is_audio_file FROM media_uploads WHERE media_uploads.s3_key=$1
+UNION ALL
+SELECT content_id, user_id, is_audio_file FROM upload_sessions WHERE upload_sessions.s3_key=$1 AND status<>'aborted'
+LIMIT 1;
+
+-- name: DeleteExpiredMediaUploads :exec
+DELETE FROM media_uploads WHERE created_at <= $1;
@@ -0,0 +1,77 @@
+-- +goose Up
+
+-- Titles of real tracks and albums can easily exceed 50 characters
+ALTER TABLE content ALTER COLUMN title TYPE VARCHAR(255);
+
+CREATE TABLE artists (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    modified_at TIMESTAMP NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    bio TEXT NOT NULL DEFAULT ''
+);
+
+-- Users who can manage the artist profile and publish releases on its behalf
+CREATE TABLE artist_users (
+    artist_id UUID NOT NULL REFERENCES artists(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    PRIMARY KEY (artist_id, user_id)
+);
+
+CREATE INDEX artist_users_user_id_idx ON artist_users (user_id);
+
+-- ('A', 'Album')
+-- ('S', 'Single')
+-- ('E', 'EP')
+CREATE TYPE album_type AS ENUM ('A', 'S', 'E');
+
+CREATE TABLE albums (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    modified_at TIMESTAMP NOT NULL,
+    artist_id UUID NOT NULL REFERENCES artists(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    type album_type NOT NULL,
+    release_date DATE NOT NULL,
+    upc VARCHAR(14) UNIQUE
+);
+
+CREATE INDEX albums_artist_id_idx ON albums (artist_id, release_date DESC);
+
+ALTER TABLE content
+    ADD COLUMN album_id UUID REFERENCES albums(id) ON DELETE SET NULL,
+    ADD COLUMN disc_number INTEGER CHECK (disc_number > 0),
+    ADD COLUMN track_number INTEGER CHECK (track_number > 0),
+    ADD COLUMN isrc VARCHAR(12) UNIQUE,
+    ADD CONSTRAINT UniqueAlbumTrack UNIQUE (album_id, disc_number, track_number);
+
+-- ('P', 'Primary')
+-- ('F', 'Featured')
+CREATE TYPE artist_role AS ENUM ('P', 'F');
+
+CREATE TABLE content_artists (
+    content_id UUID NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    artist_id UUID NOT NULL REFERENCES artists(id) ON DELETE CASCADE,
+    role artist_role NOT NULL,
+    PRIMARY KEY (content_id, artist_id)
+);
+
+CREATE INDEX content_artists_artist_id_idx ON content_artists (artist_id);
+
+-- +goose Down
+DROP TABLE content_artists;
+DROP TYPE artist_role;
+
+ALTER TABLE content
+    DROP CONSTRAINT UniqueAlbumTrack,
+    DROP COLUMN isrc,
+    DROP COLUMN track_number,
+    DROP COLUMN disc_number,
+    DROP COLUMN album_id;
+
+DROP TABLE albums;
+DROP TYPE album_type;
+DROP TABLE artist_users;
+DROP TABLE artists;
+
+ALTER TABLE content ALTER COLUMN title TYPE VARCHAR(50);
@@ -0,0 +1,42 @@
+-- +goose Up
+CREATE TABLE podcast_shows (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    modified_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    author VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    artwork_url TEXT,
+    categories TEXT[] NOT NULL DEFAULT '{}',
+    explicit BOOLEAN NOT NULL DEFAULT FALSE,
+    feed_url TEXT
+);
+
+CREATE INDEX podcast_shows_user_id_idx ON podcast_shows (user_id);
+
+-- Episodes are stored as content of type 'P' linked with the show
+ALTER TABLE content
+    ADD COLUMN show_id UUID REFERENCES podcast_shows(id) ON DELETE CASCADE,
+    ADD COLUMN season_number INTEGER CHECK (season_number > 0),
+    ADD COLUMN episode_number INTEGER CHECK (episode_number > 0),
+    ADD COLUMN published_at TIMESTAMP,
+    ADD COLUMN show_notes TEXT,
+    ADD COLUMN guid TEXT,
+    ADD CONSTRAINT UniqueShowEpisode UNIQUE (show_id, guid);
+
+CREATE INDEX content_show_id_idx ON content (show_id, published_at DESC);
+
+-- +goose Down
+DROP INDEX content_show_id_idx;
+
+ALTER TABLE content
+    DROP CONSTRAINT UniqueShowEpisode,
+    DROP COLUMN guid,
+    DROP COLUMN show_notes,
+    DROP COLUMN published_at,
+    DROP COLUMN episode_number,
+    DROP COLUMN season_number,
+    DROP COLUMN show_id;
+
+DROP TABLE podcast_shows;
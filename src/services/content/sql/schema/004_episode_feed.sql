@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE podcast_shows
+    ADD COLUMN language VARCHAR(16) NOT NULL DEFAULT 'en';
+
+-- Downloadable audio file generated by the conversion service, Used as the enclosure in podcast feeds
+ALTER TABLE content
+    ADD COLUMN download_key TEXT,
+    ADD COLUMN download_size BIGINT,
+    ADD COLUMN duration INTEGER,
+    ADD COLUMN chapters_url TEXT,
+    ADD COLUMN transcript_url TEXT;
+
+-- +goose Down
+ALTER TABLE content
+    DROP COLUMN transcript_url,
+    DROP COLUMN chapters_url,
+    DROP COLUMN duration,
+    DROP COLUMN download_size,
+    DROP COLUMN download_key;
+
+ALTER TABLE podcast_shows DROP COLUMN language;
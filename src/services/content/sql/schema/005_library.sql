@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE content_likes (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL,
+    content_id UUID NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    CONSTRAINT UniqueContentLike UNIQUE (user_id, content_id)
+);
+
+-- Used for counting the likes of a content
+CREATE INDEX content_likes_content_id_idx ON content_likes (content_id);
+
+CREATE TABLE saved_albums (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL,
+    album_id UUID NOT NULL REFERENCES albums(id) ON DELETE CASCADE,
+    CONSTRAINT UniqueSavedAlbum UNIQUE (user_id, album_id)
+);
+
+-- +goose Down
+DROP TABLE saved_albums;
+DROP TABLE content_likes;
@@ -0,0 +1,49 @@
+-- +goose Up
+
+-- ('S', 'Start')
+-- ('P', 'Progress')
+-- ('E', 'End')
+CREATE TYPE play_event_type AS ENUM ('S', 'P', 'E');
+
+-- Play events are partitioned by month of the time they were received by the server,
+-- So that the old months can be dropped or archived without touching the recent ones
+CREATE TABLE play_events (
+    id UUID NOT NULL,
+    received_at TIMESTAMP NOT NULL,
+    played_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL,
+    content_id UUID NOT NULL,
+    type play_event_type NOT NULL,
+    position INTEGER NOT NULL CHECK (position >= 0),
+    duration_played INTEGER NOT NULL CHECK (duration_played >= 0),
+    client VARCHAR(50) NOT NULL,
+    PRIMARY KEY (id, received_at)
+) PARTITION BY RANGE (received_at);
+
+CREATE INDEX play_events_user_id_idx ON play_events (user_id, received_at DESC);
+
+-- Catch the events which does not fit in any of the monthly partitions
+CREATE TABLE play_events_default PARTITION OF play_events DEFAULT;
+
+-- Create the monthly partition which holds the given time, If it does not exists already
+-- +goose StatementBegin
+CREATE FUNCTION create_play_events_partition(month_start TIMESTAMP) RETURNS VOID AS $$
+DECLARE
+    start_time TIMESTAMP := date_trunc('month', month_start);
+    partition_name TEXT := 'play_events_' || to_char(start_time, 'YYYY_MM');
+BEGIN
+    EXECUTE format(
+        'CREATE TABLE IF NOT EXISTS %I PARTITION OF play_events FOR VALUES FROM (%L) TO (%L)',
+        partition_name, start_time, start_time + INTERVAL '1 month'
+    );
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+SELECT create_play_events_partition(now()::TIMESTAMP);
+SELECT create_play_events_partition((now() + INTERVAL '1 month')::TIMESTAMP);
+
+-- +goose Down
+DROP FUNCTION create_play_events_partition;
+DROP TABLE play_events;
+DROP TYPE play_event_type;
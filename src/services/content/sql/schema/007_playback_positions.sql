@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- Last playback position of a content for each user, Synced across the user's devices
+CREATE TABLE playback_positions (
+    user_id UUID NOT NULL,
+    content_id UUID NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position >= 0),
+    played BOOLEAN NOT NULL DEFAULT FALSE,
+    client_updated_at TIMESTAMP NOT NULL,
+    modified_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, content_id)
+);
+
+-- +goose Down
+DROP TABLE playback_positions;
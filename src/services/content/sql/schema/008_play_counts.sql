@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE content_play_counts (
+    content_id UUID PRIMARY KEY REFERENCES content(id) ON DELETE CASCADE,
+    total_plays BIGINT NOT NULL DEFAULT 0,
+    weekly_plays BIGINT NOT NULL DEFAULT 0,
+    modified_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX content_play_counts_weekly_idx ON content_play_counts (weekly_plays DESC);
+CREATE INDEX content_play_counts_total_idx ON content_play_counts (total_plays DESC);
+
+-- Used for finding the previous plays of the same content by a user
+CREATE INDEX play_events_user_content_idx ON play_events (user_id, content_id, played_at);
+
+-- Time until which the data is processed by the background jobs
+CREATE TABLE job_checkpoints (
+    name VARCHAR(50) PRIMARY KEY,
+    processed_until TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE job_checkpoints;
+DROP INDEX play_events_user_content_idx;
+DROP TABLE content_play_counts;
@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- Top similar contents of each content, Precomputed by the recommendations job
+CREATE TABLE content_similarities (
+    content_id UUID NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    similar_content_id UUID NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (content_id, similar_content_id)
+);
+
+CREATE INDEX content_similarities_score_idx ON content_similarities (content_id, score DESC);
+
+-- +goose Down
+DROP TABLE content_similarities;
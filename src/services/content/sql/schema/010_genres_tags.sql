@@ -0,0 +1,57 @@
+-- +goose Up
+
+-- Curated genre taxonomy, Content can be added only to the genres listed here
+CREATE TABLE genres (
+    slug VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    type content_type NOT NULL
+);
+
+INSERT INTO genres (slug, name, type) VALUES
+    ('pop', 'Pop', 'M'),
+    ('rock', 'Rock', 'M'),
+    ('hip-hop', 'Hip-Hop', 'M'),
+    ('rnb', 'R&B', 'M'),
+    ('electronic', 'Electronic', 'M'),
+    ('jazz', 'Jazz', 'M'),
+    ('classical', 'Classical', 'M'),
+    ('country', 'Country', 'M'),
+    ('metal', 'Metal', 'M'),
+    ('folk', 'Folk', 'M'),
+    ('indie', 'Indie', 'M'),
+    ('latin', 'Latin', 'M'),
+    ('reggae', 'Reggae', 'M'),
+    ('blues', 'Blues', 'M'),
+    ('soundtrack', 'Soundtrack', 'M'),
+    ('comedy', 'Comedy', 'P'),
+    ('news', 'News', 'P'),
+    ('true-crime', 'True Crime', 'P'),
+    ('education', 'Education', 'P'),
+    ('technology', 'Technology', 'P'),
+    ('business', 'Business', 'P'),
+    ('sports', 'Sports', 'P'),
+    ('society-culture', 'Society & Culture', 'P'),
+    ('health-fitness', 'Health & Fitness', 'P'),
+    ('history', 'History', 'P');
+
+CREATE TABLE content_genres (
+    content_id UUID NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    genre_slug VARCHAR(50) NOT NULL REFERENCES genres(slug) ON DELETE CASCADE,
+    PRIMARY KEY (content_id, genre_slug)
+);
+
+CREATE INDEX content_genres_genre_slug_idx ON content_genres (genre_slug);
+
+-- Free-form tags, Stored in lower case
+CREATE TABLE content_tags (
+    content_id UUID NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    tag VARCHAR(50) NOT NULL,
+    PRIMARY KEY (content_id, tag)
+);
+
+CREATE INDEX content_tags_tag_idx ON content_tags (tag);
+
+-- +goose Down
+DROP TABLE content_tags;
+DROP TABLE content_genres;
+DROP TABLE genres;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TYPE content_visibility AS ENUM ('public', 'unlisted', 'private');
+
+-- Unlisted content is reachable only by its ID or the share link, Private content only by its owner
+ALTER TABLE content
+    ADD COLUMN visibility content_visibility NOT NULL DEFAULT 'public',
+    ADD COLUMN share_token UUID NOT NULL DEFAULT gen_random_uuid();
+
+CREATE UNIQUE INDEX content_share_token_idx ON content (share_token);
+CREATE INDEX content_visibility_created_at_idx ON content (visibility, created_at DESC);
+
+-- +goose Down
+DROP INDEX content_visibility_created_at_idx;
+DROP INDEX content_share_token_idx;
+
+ALTER TABLE content
+    DROP COLUMN share_token,
+    DROP COLUMN visibility;
+
+DROP TYPE content_visibility;
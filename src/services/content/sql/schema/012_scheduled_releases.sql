@@ -0,0 +1,20 @@
+-- +goose Up
+
+-- Content is hidden from public until publish_at, And again after unpublish_at.
+-- Released flag is flipped by the release scheduler, So that the public queries don't have to compare the dates.
+ALTER TABLE content
+    ADD COLUMN publish_at TIMESTAMP,
+    ADD COLUMN unpublish_at TIMESTAMP,
+    ADD COLUMN released BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE INDEX content_scheduled_release_idx ON content (publish_at) WHERE NOT released;
+CREATE INDEX content_scheduled_unpublish_idx ON content (unpublish_at) WHERE released AND unpublish_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX content_scheduled_unpublish_idx;
+DROP INDEX content_scheduled_release_idx;
+
+ALTER TABLE content
+    DROP COLUMN released,
+    DROP COLUMN unpublish_at,
+    DROP COLUMN publish_at;
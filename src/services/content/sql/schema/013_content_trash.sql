@@ -0,0 +1,11 @@
+-- +goose Up
+
+-- Deleted content is kept in trash until the retention job removes it permanently
+ALTER TABLE content ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX content_deleted_idx ON content (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX content_deleted_idx;
+
+ALTER TABLE content DROP COLUMN deleted_at;
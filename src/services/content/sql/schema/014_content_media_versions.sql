@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE TYPE media_version_status AS ENUM ('processing', 'ready', 'failed');
+
+-- Every uploaded master file of a content is a version, Content serves the files of its current version
+CREATE TABLE content_media_versions (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    modified_at TIMESTAMP NOT NULL,
+    content_id UUID NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    source_key TEXT NOT NULL,
+    s3_key TEXT,
+    download_key TEXT,
+    download_size BIGINT,
+    duration INT,
+    status media_version_status NOT NULL DEFAULT 'processing',
+    UNIQUE (content_id, version)
+);
+
+ALTER TABLE content ADD COLUMN media_version_id UUID REFERENCES content_media_versions(id) ON DELETE SET NULL;
+
+-- Existing media files become the first version of their contents
+INSERT INTO content_media_versions (id, created_at, modified_at, content_id, version, source_key, s3_key, download_key, download_size, duration, status)
+SELECT gen_random_uuid(), modified_at, modified_at, id, 1, s3_key, s3_key, download_key, download_size, duration, 'ready'
+FROM content WHERE s3_key IS NOT NULL;
+
+UPDATE content SET media_version_id=content_media_versions.id
+FROM content_media_versions WHERE content_media_versions.content_id=content.id;
+
+-- +goose Down
+ALTER TABLE content DROP COLUMN media_version_id;
+
+DROP TABLE content_media_versions;
+DROP TYPE media_version_status;
@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TYPE upload_session_status AS ENUM ('active', 'completed', 'aborted');
+
+-- Multipart uploads of the media files, Uploaded parts are tracked by s3 itself
+CREATE TABLE upload_sessions (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    modified_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL,
+    content_id UUID NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    s3_key TEXT NOT NULL,
+    s3_upload_id TEXT NOT NULL,
+    is_audio_file BOOLEAN NOT NULL,
+    status upload_session_status NOT NULL DEFAULT 'active'
+);
+
+CREATE INDEX upload_sessions_expiry_idx ON upload_sessions (expires_at) WHERE status='active';
+
+-- +goose Down
+DROP TABLE upload_sessions;
+DROP TYPE upload_session_status;
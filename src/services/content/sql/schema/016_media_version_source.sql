@@ -0,0 +1,7 @@
+-- +goose Up
+
+-- Same uploaded file can be reported by the client and by the storage notifications, It's converted only once
+CREATE UNIQUE INDEX content_media_versions_source_idx ON content_media_versions (source_key);
+
+-- +goose Down
+DROP INDEX content_media_versions_source_idx;
@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- Outbox of the content published events, Written along with the release so that no event is lost.
+-- Events are removed once they are sent on the redis channel.
+CREATE TABLE content_release_events (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    content_id UUID NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    type content_type NOT NULL,
+    published_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX content_release_events_created_at_idx ON content_release_events (created_at);
+
+-- +goose Down
+DROP TABLE content_release_events;
@@ -0,0 +1,16 @@
+-- +goose Up
+
+-- Keys of the single file uploads issued by the pre-signed URLs,
+-- Storage notifications are ingested only for the keys issued to the owner of the content.
+CREATE TABLE media_uploads (
+    s3_key TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL,
+    content_id UUID NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    is_audio_file BOOLEAN NOT NULL
+);
+
+CREATE INDEX media_uploads_created_at_idx ON media_uploads (created_at);
+
+-- +goose Down
+DROP TABLE media_uploads;
@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- Enclosures of the imported episodes which are not copied into our storage yet,
+-- Pending imports are resumed on startup, So they are not lost when the service restarts.
+CREATE TABLE episode_media_imports (
+    content_id UUID PRIMARY KEY REFERENCES content(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL,
+    enclosure_url TEXT NOT NULL,
+    enclosure_type TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0
+);
+
+-- +goose Down
+DROP TABLE episode_media_imports;
@@ -3,9 +3,11 @@ package main
 import (
 	"bytes"
 	"context"
+	"math"
 	"net"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -67,13 +69,18 @@ func main() {
 // gRPC request handler
 func (s *server) Conversion(ctx context.Context, in *pb.ConversionRequest) (*pb.ConversionResponse, error) {
 	// Convert the media file
-	key, err := convertMediaFile(in.GetKey(), in.GetIsAudioFile())
+	result, err := convertMediaFile(in.GetKey(), in.GetIsAudioFile())
 	if err != nil {
 		log.Errorln("error caught while converting the media file: ", err)
 		return nil, status.Errorf(codes.Internal, "something went wrong")
 	}
 
-	return &pb.ConversionResponse{Key: *key}, nil
+	return &pb.ConversionResponse{
+		Key:          result.key,
+		DownloadKey:  result.downloadKey,
+		DownloadSize: result.downloadSize,
+		Duration:     result.duration,
+	}, nil
 }
 
 func getS3Client() (*s3.Client, error) {
@@ -153,7 +160,43 @@ func removeFiles(fileNames []string) {
 	}
 }
 
-func convertMediaFile(key string, isAudioFile bool) (*string, error) {
+// Keys and details of the files generated for a media file
+type conversionResult struct {
+	key          string
+	downloadKey  string
+	downloadSize int64
+	duration     int32
+}
+
+// Return the duration of the given media file in seconds
+func getMediaDuration(fileName string) (int32, error) {
+	output, err := exec.Command("ffprobe", "-v", "error", "-show_entries", "format=duration", "-of", "default=noprint_wrappers=1:nokey=1", fileName).Output()
+	if err != nil {
+		return 0, err
+	}
+
+	duration, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	if err != nil {
+		return 0, err
+	}
+	return int32(math.Round(duration)), nil
+}
+
+// Convert the audio file into a single AAC file, Which is used as the enclosure in podcast feeds
+func convertToDownloadFile(srcFileName, dstFileName string) error {
+	var stderr bytes.Buffer
+
+	convertCmd := exec.Command("ffmpeg", "-y", "-i", srcFileName, "-c:a", "aac", "-b:a", "128k", "-vn", "-movflags", "+faststart", dstFileName)
+	convertCmd.Stderr = &stderr
+
+	if err := convertCmd.Run(); err != nil {
+		log.Errorln("FFmpeg stderr: ", stderr.String())
+		return err
+	}
+	return nil
+}
+
+func convertMediaFile(key string, isAudioFile bool) (*conversionResult, error) {
 	client, err := getS3Client()
 	if err != nil {
 		return nil, err
@@ -209,11 +252,46 @@ func convertMediaFile(key string, isAudioFile bool) (*string, error) {
 
 	log.Infof("%s object uploaded successfully", tsKey)
 
-	// Remove old media file from s3
-	go deleteFileFromS3(client, bucket, key)
+	result := &conversionResult{key: hlsKey}
+	processedFiles := []string{srcFileName, dstFileName, tsFileName}
+
+	// Duration is optional, So the conversion is not failed if it can not be detected
+	if result.duration, err = getMediaDuration(srcFileName); err != nil {
+		log.Errorln("error caught while detecting media duration: ", err)
+	}
+
+	// Generate a downloadable file for audio, Since HLS is not supported by the podcast apps
+	if isAudioFile {
+		downloadKey := strings.Split(key, ".")[0] + ".m4a"
+		downloadFileName := "download-" + strings.Split(downloadKey, "/")[1]
+		processedFiles = append(processedFiles, downloadFileName)
+
+		if err = convertToDownloadFile(srcFileName, downloadFileName); err != nil {
+			return nil, err
+		}
+
+		fileInfo, err := os.Stat(downloadFileName)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = uploadFileToS3(client, bucket, downloadKey, downloadFileName, "audio/mp4"); err != nil {
+			return nil, err
+		}
+
+		log.Infof("%s object uploaded successfully", downloadKey)
+
+		result.downloadKey = downloadKey
+		result.downloadSize = fileInfo.Size()
+	}
+
+	// Remove old media file from s3, Unless it was overwritten by the download file
+	if key != result.downloadKey {
+		go deleteFileFromS3(client, bucket, key)
+	}
 
 	// Remove the downloaded or processed files in background
-	go removeFiles([]string{srcFileName, dstFileName, tsFileName})
+	go removeFiles(processedFiles)
 
-	return &hlsKey, err
+	return result, nil
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/user/database"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/user/utils"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/user/validators"
@@ -26,7 +27,7 @@ func signUp(dbCfg *database.Config) gin.HandlerFunc {
 
 		if err != nil {
 			log.Errorln("Error caught while parsing signup request data: ", err)
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Error while parsing the request data"})
+			apierror.Respond(ctx, apierror.FromBinding(err))
 			return
 		}
 
@@ -36,7 +37,7 @@ func signUp(dbCfg *database.Config) gin.HandlerFunc {
 		// Validate Password
 		err = validators.PasswordValidator(params.Password, email)
 		if err != nil {
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			apierror.Respond(ctx, apierror.InvalidArgument(err.Error()).WithField("password", err.Error()))
 			return
 		}
 
@@ -45,22 +46,22 @@ func signUp(dbCfg *database.Config) gin.HandlerFunc {
 
 		if err != nil {
 			log.Errorln("Error caught while generating hashed password: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, err)
 			return
 		}
 
 		// Check if user exists with the given email address
 		_, err = database.GetUserByEmailDB(dbCfg, ctx, email)
 		if err == nil {
-			ctx.SecureJSON(http.StatusForbidden, gin.H{"message": "User with this email address already exists"})
+			apierror.Respond(ctx, apierror.AlreadyExists("User with this email address already exists").WithField("email", "Already in use"))
 			return
 		}
 
 		// Begin DB transaction
 		tx, err := dbCfg.DB.Begin(ctx)
 		if err != nil {
-			log.Fatalln("Error caught while starting a transaction: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			log.Errorln("Error caught while starting a transaction: ", err)
+			apierror.Respond(ctx, err)
 			return
 		}
 		defer tx.Rollback(ctx)
@@ -85,7 +86,7 @@ func signUp(dbCfg *database.Config) gin.HandlerFunc {
 
 		if err != nil {
 			log.Errorln("Error caught while creating a user in DB: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, apierror.FromDB(err, "", "User with this email address already exists"))
 			return
 		}
 
@@ -94,15 +95,15 @@ func signUp(dbCfg *database.Config) gin.HandlerFunc {
 
 		if err != nil {
 			log.Errorln("Error caught while generating auth tokens during signup: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, err)
 			return
 		}
 
 		// Commit the transaction
 		err = tx.Commit(ctx)
 		if err != nil {
-			log.Fatalln("Error caught while closing a transaction: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			log.Errorln("Error caught while closing a transaction: ", err)
+			apierror.Respond(ctx, err)
 			return
 		}
 
@@ -123,24 +124,30 @@ func login(dbCfg *database.Config) gin.HandlerFunc {
 
 		if err != nil {
 			log.Errorln("Error caught while parsing login request data: ", err)
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Error while parsing the request data"})
+			apierror.Respond(ctx, apierror.FromBinding(err))
 			return
 		}
 
 		// Check weather the user exists with the given email or not
 		user, err := database.GetUserByEmailDB(dbCfg, ctx, strings.ToLower(params.Email))
 		if err != nil {
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "User does not exists, Please check your credentials"})
+			apierror.Respond(ctx, apierror.InvalidArgument("User does not exists, Please check your credentials"))
+			return
+		}
+
+		// Accounts created via social login does not have a password
+		if user.Password == "" {
+			apierror.Respond(ctx, apierror.InvalidArgument("Please login using your linked account"))
 			return
 		}
 
 		// Check the given password with hashed password stored in DB
 		match, err := utils.CheckPasswordValid(params.Password, user.Password)
 		if err != nil {
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Invalid password"})
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid password"))
 			return
 		} else if !match {
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Invalid password"})
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid password"))
 			return
 		}
 
@@ -149,7 +156,13 @@ func login(dbCfg *database.Config) gin.HandlerFunc {
 
 		if err != nil {
 			log.Errorln("Error caught while generating auth tokens during login: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		// Tokens are still issued, So that user can restore the account
+		if user.DeletedAt.Valid {
+			ctx.SecureJSON(http.StatusOK, gin.H{"message": "Account is scheduled for deletion, Please restore it to continue", "data": tokens})
 			return
 		}
 
@@ -170,16 +183,31 @@ func refreshAccessToken(ctx *gin.Context) {
 
 	if err != nil {
 		log.Errorln("Error caught while parsing refresh token request data: ", err)
-		ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Error while parsing the request data"})
+		apierror.Respond(ctx, apierror.FromBinding(err))
 		return
 	}
 
 	tokens, err := utils.ReIssueAccessToken(params.RefreshToken)
 	if err != nil {
 		log.Errorln("Error caught while re-issuing auth tokens: ", err)
-		ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Error while issuing new tokens"})
+		apierror.Respond(ctx, apierror.InvalidArgument("Error while issuing new tokens"))
 		return
 	}
 
 	ctx.SecureJSON(http.StatusOK, gin.H{"message": "Tokens re-issued Successfully!", "data": tokens})
 }
+
+// JWKS API
+//
+// Publish the public keys used for signing the JWT tokens, So that other services can verify the tokens locally
+func getJWKS(ctx *gin.Context) {
+	jwks, err := utils.GetJWKS()
+	if err != nil {
+		log.Errorln("Error caught while loading JWKS: ", err)
+		apierror.Respond(ctx, err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, jwks)
+}
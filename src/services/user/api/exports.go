@@ -0,0 +1,103 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/internal"
+)
+
+// Request an export of all the data stored for the user
+//
+// Archive is built in background, Status can be checked via the export detail API.
+// A new export can't be requested while the previous one is being prepared.
+func requestDataExport(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, err := getUserFromCtx(ctx)
+		if err != nil {
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		currentTime := time.Now().UTC()
+
+		dbDataExport, err := database.CreateDataExportDB(dbCfg, ctx, database.CreateDataExportParams{
+			ID: uuid.New(),
+			CreatedAt: pgtype.Timestamp{
+				Time:  currentTime,
+				Valid: true,
+			},
+			ModifiedAt: pgtype.Timestamp{
+				Time:  currentTime,
+				Valid: true,
+			},
+			UserID: user.ID,
+			Status: database.ExportStatusP,
+		})
+
+		if err != nil {
+			log.Errorln("error while creating data export request: ", err)
+			apierror.Respond(ctx, apierror.FromDB(err, "", "Your data is already being prepared, Please wait for the previous export"))
+			return
+		}
+
+		go internal.BuildDataExport(dbCfg, dbDataExport.ID, user.ID)
+
+		ctx.SecureJSON(http.StatusAccepted, gin.H{
+			"message": "Preparing your data. Download link will be available soon",
+			"data":    databaseDataExportToDataExport(dbDataExport, nil),
+		})
+	}
+}
+
+// Fetch the status of a data export request along with the download link if it is ready
+func getDataExport(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, err := getUserFromCtx(ctx)
+		if err != nil {
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		exportID, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid export ID"))
+			return
+		}
+
+		dbDataExport, err := database.GetDataExportDB(dbCfg, ctx, database.GetDataExportParams{
+			ID:     exportID,
+			UserID: user.ID,
+		})
+		if err != nil {
+			apierror.Respond(ctx, apierror.FromDB(err, "Data export not found", ""))
+			return
+		}
+
+		if dbDataExport.Status != database.ExportStatusR {
+			ctx.SecureJSON(http.StatusOK, gin.H{"data": databaseDataExportToDataExport(dbDataExport, nil)})
+			return
+		}
+
+		// Archive is already removed from s3
+		if time.Since(dbDataExport.ModifiedAt.Time) > internal.ExportRetention {
+			apierror.Respond(ctx, apierror.Expired("Data export is expired, Please request a new one"))
+			return
+		}
+
+		url, err := internal.GetExportDownloadURL(ctx, dbDataExport.S3Key.String, internal.ExportLinkExpiry)
+		if err != nil {
+			log.Errorln("error caught while generating data export download URL: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"data": databaseDataExportToDataExport(dbDataExport, &url)})
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/utils"
+)
+
+func TestGetDataExport(t *testing.T) {
+	dbUser := database.User{ID: uuid.New(), Email: "user@example.com"}
+
+	modifiedAt := pgtype.Timestamp{Time: time.Now().UTC(), Valid: true}
+	pending := database.DataExport{
+		ID:         uuid.New(),
+		CreatedAt:  modifiedAt,
+		ModifiedAt: modifiedAt,
+		UserID:     dbUser.ID,
+		Status:     database.ExportStatusP,
+	}
+	brokenID := uuid.New()
+
+	db := newFakeDB()
+	db.on("GetUserById", func(args []any) ([][]any, error) {
+		return [][]any{rowOf(dbUser)}, nil
+	})
+	db.on("GetDataExport", func(args []any) ([][]any, error) {
+		switch args[0] {
+		case pending.ID:
+			return [][]any{rowOf(pending)}, nil
+		case brokenID:
+			return nil, errors.New("connection reset")
+		}
+		return nil, nil
+	})
+
+	engine := gin.New()
+	Routes(engine, &database.Config{DB: newTestPool(t), Queries: database.New(db)})
+
+	tokens, err := utils.GenerateTokens(dbUser.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		id     string
+		status int
+	}{
+		{name: "pending", id: pending.ID.String(), status: http.StatusOK},
+		{name: "missing", id: uuid.NewString(), status: http.StatusNotFound},
+		{name: "invalid ID", id: "export", status: http.StatusBadRequest},
+		// DB errors are not reported as a missing export
+		{name: "DB error", id: brokenID.String(), status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/profile/export/"+tc.id+"/", nil)
+			req.Header.Set("Authorization", "Bearer "+tokens.Access)
+
+			rec := serve(engine, req)
+			if rec.Code != tc.status {
+				t.Errorf("expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
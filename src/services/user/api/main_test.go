@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// Generate a signing key for the tests and point JWT_KEYS_DIR to it
+func setupSigningKey() (string, error) {
+	keysDir, err := os.MkdirTemp("", "jwt-keys")
+	if err != nil {
+		return "", err
+	}
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return keysDir, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return keysDir, err
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err = os.WriteFile(filepath.Join(keysDir, "test.pem"), data, 0600); err != nil {
+		return keysDir, err
+	}
+
+	return keysDir, os.Setenv("JWT_KEYS_DIR", keysDir)
+}
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	log.SetOutput(io.Discard)
+
+	keysDir, err := setupSigningKey()
+	if err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(keysDir)
+	os.Exit(code)
+}
+
+// Return a DB pool which never connects, Transactions fail in the tests
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	config, err := pgxpool.ParseConfig("postgres://test@127.0.0.1:1/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.BeforeConnect = func(ctx context.Context, connConfig *pgx.ConnConfig) error {
+		return errors.New("DB is not available in tests")
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+// Serve the given request and return the recorded response
+func serve(engine *gin.Engine, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
@@ -1,12 +1,15 @@
 package api
 
 import (
-	"net/http"
+	"errors"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/user/database"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/user/utils"
 )
@@ -14,13 +17,14 @@ import (
 // Validate the request by checking weather or not they have the valid JWT access token or not
 //
 // Token format: Bearer <TOKEN>
-func JWTAuth(dbCfg *database.Config) gin.HandlerFunc {
+//
+// Users whose account is scheduled for deletion are allowed only when allowDeleted is set
+func JWTAuth(dbCfg *database.Config, allowDeleted bool) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		headerAuthToken := ctx.GetHeader("Authorization")
 
 		if headerAuthToken == "" {
-			ctx.SecureJSON(http.StatusForbidden, gin.H{"message": "Authentication required"})
-			ctx.Abort()
+			apierror.Respond(ctx, apierror.Unauthenticated("Authentication required"))
 			return
 		}
 
@@ -29,39 +33,43 @@ func JWTAuth(dbCfg *database.Config) gin.HandlerFunc {
 
 		// Validate the token string
 		if len(authToken) != 2 || authToken[0] != "Bearer" {
-			ctx.SecureJSON(http.StatusForbidden, gin.H{"message": "Invalid authentication format"})
-			ctx.Abort()
+			apierror.Respond(ctx, apierror.Unauthenticated("Invalid authentication format"))
 			return
 		}
 
 		// Verify the token and get the encoded payload which is the userID string
 		claims, err := utils.VerifyToken(authToken[1])
-		if err != nil {
-			ctx.SecureJSON(http.StatusForbidden, gin.H{"message": "Invalid authentication token"})
-			ctx.Abort()
+		if err != nil || claims.Type != utils.AccessToken {
+			apierror.Respond(ctx, apierror.Unauthenticated("Invalid authentication token"))
 			return
 		}
 
 		// Check the validity of the token
 		if !time.Unix(claims.ExpiresAt.Unix(), 0).After(time.Now()) {
-			ctx.SecureJSON(http.StatusForbidden, gin.H{"message": "Authentication token is expired"})
-			ctx.Abort()
+			apierror.Respond(ctx, apierror.Unauthenticated("Authentication token is expired"))
 			return
 		}
 
 		// Convert the userID string to UUID
 		userID, err := uuid.Parse(claims.Data)
 		if err != nil {
-			ctx.SecureJSON(http.StatusForbidden, gin.H{"message": "Invalid authentication token"})
-			ctx.Abort()
+			apierror.Respond(ctx, apierror.Unauthenticated("Invalid authentication token"))
 			return
 		}
 
 		// Fetch user by ID from DB
 		dbUser, err := database.GetUserByIDFromDB(dbCfg, ctx, userID)
-		if err != nil {
-			ctx.SecureJSON(http.StatusForbidden, gin.H{"message": "Something went wrong"})
-			ctx.Abort()
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierror.Respond(ctx, apierror.Unauthenticated("Invalid authentication token"))
+			return
+		} else if err != nil {
+			log.Errorln("error caught while fetching user for authentication: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		if dbUser.DeletedAt.Valid && !allowDeleted {
+			apierror.Respond(ctx, apierror.PermissionDenied("Account is scheduled for deletion, Please restore it to continue"))
 			return
 		}
 
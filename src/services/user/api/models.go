@@ -5,20 +5,50 @@
 package api
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/user/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/internal"
 )
 
 type User struct {
-	ID    uuid.UUID `json:"id"`
-	Name  string    `json:"name"`
-	Email string    `json:"email"`
+	ID     uuid.UUID         `json:"id"`
+	Name   string            `json:"name"`
+	Email  string            `json:"email"`
+	Avatar map[string]string `json:"avatar"`
+
+	avatarKey string
 }
 
 func databaseUserToUser(dbUser *database.User) User {
 	return User{
-		ID:    dbUser.ID,
-		Name:  dbUser.Name.String,
-		Email: dbUser.Email,
+		ID:        dbUser.ID,
+		Name:      dbUser.Name.String,
+		Email:     dbUser.Email,
+		Avatar:    internal.GetAvatarURLs(dbUser.AvatarKey.String),
+		avatarKey: dbUser.AvatarKey.String,
+	}
+}
+
+type DataExport struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Status    string    `json:"status"`
+	Url       *string   `json:"url"`
+}
+
+var exportStatusNames = map[database.ExportStatus]string{
+	database.ExportStatusP: "pending",
+	database.ExportStatusR: "ready",
+	database.ExportStatusF: "failed",
+}
+
+func databaseDataExportToDataExport(dbDataExport *database.DataExport, url *string) DataExport {
+	return DataExport{
+		ID:        dbDataExport.ID,
+		CreatedAt: dbDataExport.CreatedAt.Time,
+		Status:    exportStatusNames[dbDataExport.Status],
+		Url:       url,
 	}
 }
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/oidc"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/utils"
+)
+
+const loginStateCookie = "oidc_state"
+
+var errVerifiedEmailRequired = errors.New("verified email address is required, Please verify your email with the provider")
+
+// Check weather or not the request was made over HTTPS, Either directly or via the API gateway
+func isSecureRequest(ctx *gin.Context) bool {
+	return ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https"
+}
+
+// OIDC login API
+//
+// Redirect the user to the login page of the given provider using authorization code flow with PKCE
+func oidcLogin(ctx *gin.Context) {
+	provider, err := oidc.GetProvider(ctx, ctx.Param("provider"))
+	if err != nil {
+		log.Errorln("Error caught while loading OIDC provider: ", err)
+		apierror.Respond(ctx, apierror.NotFound("Login provider not found"))
+		return
+	}
+
+	loginState, signedState, err := utils.GenerateLoginState(provider.Name())
+	if err != nil {
+		log.Errorln("Error caught while generating OIDC login state: ", err)
+		apierror.Respond(ctx, err)
+		return
+	}
+
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(loginStateCookie, signedState, int((10 * time.Minute).Seconds()), "/", "", isSecureRequest(ctx), true)
+	ctx.Redirect(http.StatusFound, provider.AuthCodeURL(loginState.State, loginState.Nonce, loginState.Verifier))
+}
+
+// OIDC callback API
+//
+// Exchange the authorization code, Find or create the user linked with the external identity and issue our own tokens
+func oidcCallback(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if errCode := ctx.Query("error"); errCode != "" {
+			log.Errorln("OIDC provider returned an error: ", errCode, ctx.Query("error_description"))
+			apierror.Respond(ctx, apierror.InvalidArgument("Login was not successful, Please try again"))
+			return
+		}
+
+		// Verify the login state saved in the cookie
+		signedState, err := ctx.Cookie(loginStateCookie)
+		if err != nil {
+			apierror.Respond(ctx, apierror.InvalidArgument("Login session expired, Please try again"))
+			return
+		}
+
+		// Login state is valid for a single callback only
+		ctx.SetCookie(loginStateCookie, "", -1, "/", "", isSecureRequest(ctx), true)
+
+		loginState, err := utils.VerifyLoginState(signedState)
+		if err != nil || loginState.Provider != ctx.Param("provider") || loginState.State != ctx.Query("state") {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid login state, Please try again"))
+			return
+		}
+
+		provider, err := oidc.GetProvider(ctx, loginState.Provider)
+		if err != nil {
+			log.Errorln("Error caught while loading OIDC provider: ", err)
+			apierror.Respond(ctx, apierror.NotFound("Login provider not found"))
+			return
+		}
+
+		identity, err := provider.Exchange(ctx, ctx.Query("code"), loginState.Nonce, loginState.Verifier)
+		if err != nil {
+			log.Errorln("Error caught while exchanging OIDC authorization code: ", err)
+			apierror.Respond(ctx, apierror.InvalidArgument("Login was not successful, Please try again"))
+			return
+		}
+
+		dbUser, err := getOrCreateIdentityUser(dbCfg, ctx, identity)
+		if errors.Is(err, errVerifiedEmailRequired) {
+			apierror.Respond(ctx, apierror.PermissionDenied(err.Error()))
+			return
+		} else if err != nil {
+			log.Errorln("Error caught while resolving OIDC user: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		// Generate auth tokens for the user
+		tokens, err := utils.GenerateTokens(dbUser.ID.String())
+		if err != nil {
+			log.Errorln("Error caught while generating auth tokens during OIDC login: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		// Same as the password login, Tokens of an account scheduled for deletion can only be used for restoring it
+		if dbUser.DeletedAt.Valid {
+			ctx.SecureJSON(http.StatusOK, gin.H{"message": "Account is scheduled for deletion, Please restore it to continue", "data": tokens})
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"message": "Logged in Successfully!", "data": tokens})
+	}
+}
+
+// Return the user linked with the given identity
+//
+// If the identity is not linked yet then it is linked with the user having the same verified email address,
+// Or a new account is created for it.
+func getOrCreateIdentityUser(dbCfg *database.Config, ctx *gin.Context, identity *oidc.Identity) (*database.User, error) {
+	dbIdentity, err := database.GetUserIdentityDB(dbCfg, ctx, database.GetUserIdentityParams{
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+	})
+	if err == nil {
+		return database.GetUserByIDFromDB(dbCfg, ctx, dbIdentity.UserID)
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, errVerifiedEmailRequired
+	}
+
+	currentTime := time.Now().UTC()
+	identityParams := database.CreateUserIdentityParams{
+		ID: uuid.New(),
+		CreatedAt: pgtype.Timestamp{
+			Time:  currentTime,
+			Valid: true,
+		},
+		ModifiedAt: pgtype.Timestamp{
+			Time:  currentTime,
+			Valid: true,
+		},
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email: pgtype.Text{
+			String: identity.Email,
+			Valid:  true,
+		},
+	}
+
+	// Link the identity with the existing account
+	if dbUser, err := database.GetUserByEmailDB(dbCfg, ctx, identity.Email); err == nil {
+		identityParams.UserID = dbUser.ID
+		if _, err = database.CreateUserIdentityDB(dbCfg, ctx, identityParams); err != nil {
+			return nil, err
+		}
+		return dbUser, nil
+	}
+
+	// Create a new account, Password is left empty since the user will always login via the provider
+	dbUser, err := database.CreateUserWithIdentityDB(dbCfg, ctx, database.CreateUserParams{
+		ID: uuid.New(),
+		CreatedAt: pgtype.Timestamp{
+			Time:  currentTime,
+			Valid: true,
+		},
+		ModifiedAt: pgtype.Timestamp{
+			Time:  currentTime,
+			Valid: true,
+		},
+		Email: identity.Email,
+	}, database.UpdateUserDetailsParams{
+		Email: identity.Email,
+		Name: pgtype.Text{
+			String: identity.Name,
+			Valid:  identity.Name != "",
+		},
+		ModifiedAt: pgtype.Timestamp{
+			Time:  currentTime,
+			Valid: true,
+		},
+	}, identityParams)
+
+	if err != nil {
+		return nil, err
+	}
+	return dbUser, nil
+}
@@ -0,0 +1,384 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apidocs/apitest"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/utils"
+)
+
+const (
+	mockClientID     = "spotify-clone"
+	mockClientSecret = "secret"
+	mockSubject      = "mock-user"
+	mockCallbackURL  = "http://localhost/api/v1/oidc/mock/callback/"
+)
+
+// Authorization request waiting for its code to be exchanged
+type mockAuthRequest struct {
+	redirectURI string
+	nonce       string
+	challenge   string
+}
+
+// OpenID Connect provider supporting the authorization code flow with PKCE (S256),
+// Every login is done by the same user.
+type mockProvider struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]mockAuthRequest
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &mockProvider{key: key, codes: make(map[string]mockAuthRequest)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/token", p.token)
+
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func (p *mockProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                p.URL,
+		"authorization_endpoint":                p.URL + "/authorize",
+		"token_endpoint":                        p.URL + "/token",
+		"jwks_uri":                              p.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (p *mockProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "mock",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}},
+	})
+}
+
+// Login the user right away and redirect back to the client with a new code
+func (p *mockProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != mockClientID ||
+		query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	code := uuid.NewString()
+	p.mu.Lock()
+	p.codes[code] = mockAuthRequest{
+		redirectURI: query.Get("redirect_uri"),
+		nonce:       query.Get("nonce"),
+		challenge:   query.Get("code_challenge"),
+	}
+	p.mu.Unlock()
+
+	redirectURL, _ := url.Parse(query.Get("redirect_uri"))
+	redirectURL.RawQuery = url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, redirectURL.String(), http.StatusFound)
+}
+
+// Exchange the code for an ID token, Code can be used only once and only with the matching verifier
+func (p *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+	}
+	if clientID != mockClientID || clientSecret != mockClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	code := r.PostFormValue("code")
+	p.mu.Lock()
+	authRequest, exists := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+
+	hash := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if !exists || r.PostFormValue("grant_type") != "authorization_code" ||
+		r.PostFormValue("redirect_uri") != authRequest.redirectURI ||
+		base64.RawURLEncoding.EncodeToString(hash[:]) != authRequest.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            p.URL,
+		"sub":            mockSubject,
+		"aud":            mockClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          authRequest.nonce,
+		"email":          "mock@example.com",
+		"email_verified": true,
+		"name":           "Mock User",
+	})
+	idToken.Header["kid"] = "mock"
+
+	signedIDToken, err := idToken.SignedString(p.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": uuid.NewString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     signedIDToken,
+	})
+}
+
+// Start the login with the given provider and follow the redirects till the provider sends the user back,
+// Returns the callback request along with the login state cookie.
+func startOIDCLogin(t *testing.T, engine *gin.Engine, provider string) *http.Request {
+	rec := serve(engine, httptest.NewRequest(http.MethodGet, "/api/v1/oidc/"+provider+"/login/", nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("login: expected status %d, got %d", http.StatusFound, rec.Code)
+	}
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	res, err := client.Get(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusFound {
+		t.Fatalf("authorize: expected status %d, got %d", http.StatusFound, res.StatusCode)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, res.Header.Get("Location"), nil)
+	for _, cookie := range rec.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	return req
+}
+
+func TestOIDCLogin(t *testing.T) {
+	provider := newMockProvider(t)
+	t.Setenv("OIDC_PROVIDERS", "mock")
+	t.Setenv("OIDC_MOCK_ISSUER", provider.URL)
+	t.Setenv("OIDC_MOCK_CLIENT_ID", mockClientID)
+	t.Setenv("OIDC_MOCK_CLIENT_SECRET", mockClientSecret)
+	t.Setenv("OIDC_MOCK_REDIRECT_URL", mockCallbackURL)
+
+	dbUser := database.User{
+		ID:    uuid.New(),
+		Email: "mock@example.com",
+	}
+
+	db := newFakeDB()
+	db.on("GetUserIdentity", func(args []any) ([][]any, error) {
+		if args[0] != "mock" || args[1] != mockSubject {
+			return nil, nil
+		}
+		return [][]any{rowOf(database.UserIdentity{
+			ID:       uuid.New(),
+			UserID:   dbUser.ID,
+			Provider: "mock",
+			Subject:  mockSubject,
+		})}, nil
+	})
+	db.on("GetUserById", func(args []any) ([][]any, error) {
+		if args[0] != dbUser.ID {
+			return nil, nil
+		}
+		return [][]any{rowOf(dbUser)}, nil
+	})
+
+	// Responses of the login flow are validated against the OpenAPI spec
+	engine := gin.New()
+	engine.Use(apitest.LoadSpec(t, openAPISpec).Validator(t))
+	engine.GET("/api/v1/oidc/:provider/login/", oidcLogin)
+	engine.GET("/api/v1/oidc/:provider/callback/", oidcCallback(&database.Config{Queries: database.New(db)}))
+
+	login := func(t *testing.T) *http.Request {
+		return startOIDCLogin(t, engine, "mock")
+	}
+
+	tests := []struct {
+		name      string
+		deletedAt pgtype.Timestamp
+		callback  func(t *testing.T) *http.Request
+		status    int
+		message   string
+	}{
+		{
+			name:     "linked account",
+			callback: login,
+			status:   http.StatusOK,
+			message:  "Logged in Successfully!",
+		},
+		{
+			name:      "account scheduled for deletion",
+			deletedAt: pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+			callback:  login,
+			status:    http.StatusOK,
+			message:   "Account is scheduled for deletion, Please restore it to continue",
+		},
+		{
+			name: "state mismatch",
+			callback: func(t *testing.T) *http.Request {
+				req := login(t)
+				query := req.URL.Query()
+				query.Set("state", "forged")
+				req.URL.RawQuery = query.Encode()
+				return req
+			},
+			status:  http.StatusBadRequest,
+			message: "Invalid login state, Please try again",
+		},
+		{
+			name: "reused code",
+			callback: func(t *testing.T) *http.Request {
+				req := login(t)
+				if rec := serve(engine, req.Clone(req.Context())); rec.Code != http.StatusOK {
+					t.Fatalf("first callback: expected status %d, got %d", http.StatusOK, rec.Code)
+				}
+				return req
+			},
+			status:  http.StatusBadRequest,
+			message: "Login was not successful, Please try again",
+		},
+		{
+			name: "access token as login state",
+			callback: func(t *testing.T) *http.Request {
+				req := login(t)
+
+				tokens, err := utils.GenerateTokens(dbUser.ID.String())
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.Header.Del("Cookie")
+				req.AddCookie(&http.Cookie{Name: loginStateCookie, Value: tokens.Access})
+				return req
+			},
+			status:  http.StatusBadRequest,
+			message: "Invalid login state, Please try again",
+		},
+		{
+			name: "missing login state",
+			callback: func(t *testing.T) *http.Request {
+				req := login(t)
+				req.Header.Del("Cookie")
+				return req
+			},
+			status:  http.StatusBadRequest,
+			message: "Login session expired, Please try again",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbUser.DeletedAt = tc.deletedAt
+
+			rec := serve(engine, tc.callback(t))
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+
+			var res struct {
+				Message string       `json:"message"`
+				Data    utils.Tokens `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Message != tc.message {
+				t.Fatalf("expected message %q, got %q", tc.message, res.Message)
+			}
+
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			claims, err := utils.VerifyToken(res.Data.Access)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims.Data != dbUser.ID.String() {
+				t.Fatalf("expected tokens of user %s, got %s", dbUser.ID, claims.Data)
+			}
+		})
+	}
+}
+
+func TestLoginStateAsAccessToken(t *testing.T) {
+	dbUser := database.User{ID: uuid.New(), Email: "user@example.com"}
+
+	db := newFakeDB()
+	db.on("GetUserById", func(args []any) ([][]any, error) {
+		return [][]any{rowOf(dbUser)}, nil
+	})
+
+	engine := gin.New()
+	Routes(engine, &database.Config{DB: newTestPool(t), Queries: database.New(db)})
+
+	loginState, signedState, err := utils.GenerateLoginState("mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loginState.Type != utils.LoginStateToken {
+		t.Errorf("expected type %s, got %s", utils.LoginStateToken, loginState.Type)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/profile/", nil)
+	req.Header.Set("Authorization", "Bearer "+signedState)
+
+	if rec := serve(engine, req); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d: %s", http.StatusUnauthorized, rec.Code, rec.Body.String())
+	}
+
+	if _, err := utils.VerifyLoginState(signedState); err != nil {
+		t.Errorf("login state is not verified: %v", err)
+	}
+}
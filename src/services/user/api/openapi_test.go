@@ -0,0 +1,396 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"image"
+	"image/png"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apidocs/apitest"
+	contentPB "github.com/thejasmeetsingh/spotify-clone/src/services/common/contentpb"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/internal"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/utils"
+	"google.golang.org/grpc"
+)
+
+const testPassword = "Password@123"
+
+// Users & data exports of the fake DB
+type userStore struct {
+	mu         sync.Mutex
+	users      map[uuid.UUID]database.User
+	identities map[string]database.UserIdentity
+	exports    map[uuid.UUID]database.DataExport
+
+	// Receives the ID of every export whose status is updated
+	exported chan uuid.UUID
+}
+
+// Run the given update on the stored user and return it as a result row
+func (s *userStore) update(id any, update func(user *database.User)) [][]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, exists := s.users[id.(uuid.UUID)]
+	if !exists {
+		return nil
+	}
+
+	update(&user)
+	s.users[user.ID] = user
+	return [][]any{rowOf(user)}
+}
+
+func (s *userStore) register(db *fakeDB) {
+	db.on("GetUserById", func(args []any) ([][]any, error) {
+		return s.update(args[0], func(user *database.User) {}), nil
+	})
+	db.on("GetUserByEmail", func(args []any) ([][]any, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		for _, user := range s.users {
+			if user.Email == args[0] {
+				return [][]any{rowOf(user)}, nil
+			}
+		}
+		return nil, nil
+	})
+	db.on("CreateUser", func(args []any) ([][]any, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		user := database.User{
+			ID:         args[0].(uuid.UUID),
+			CreatedAt:  args[1].(pgtype.Timestamp),
+			ModifiedAt: args[2].(pgtype.Timestamp),
+			Email:      args[3].(string),
+			Password:   args[4].(string),
+		}
+		s.users[user.ID] = user
+		return [][]any{rowOf(user)}, nil
+	})
+	db.on("UpdateUserDetails", func(args []any) ([][]any, error) {
+		return s.update(args[3], func(user *database.User) {
+			user.Email, user.Name, user.ModifiedAt = args[0].(string), args[1].(pgtype.Text), args[2].(pgtype.Timestamp)
+		}), nil
+	})
+	db.on("UpdateUserPassword", func(args []any) ([][]any, error) {
+		return s.update(args[2], func(user *database.User) {
+			user.Password, user.ModifiedAt = args[0].(string), args[1].(pgtype.Timestamp)
+		}), nil
+	})
+	db.on("UpdateUserAvatar", func(args []any) ([][]any, error) {
+		return s.update(args[2], func(user *database.User) {
+			user.AvatarKey, user.ModifiedAt = args[0].(pgtype.Text), args[1].(pgtype.Timestamp)
+		}), nil
+	})
+	db.on("SoftDeleteUser", func(args []any) ([][]any, error) {
+		return s.update(args[2], func(user *database.User) {
+			user.DeletedAt, user.ModifiedAt = args[0].(pgtype.Timestamp), args[1].(pgtype.Timestamp)
+		}), nil
+	})
+	db.on("RestoreUser", func(args []any) ([][]any, error) {
+		return s.update(args[1], func(user *database.User) {
+			user.DeletedAt, user.ModifiedAt = pgtype.Timestamp{}, args[0].(pgtype.Timestamp)
+		}), nil
+	})
+	db.on("GetUserIdentity", func(args []any) ([][]any, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		identity, exists := s.identities[args[0].(string)+":"+args[1].(string)]
+		if !exists {
+			return nil, nil
+		}
+		return [][]any{rowOf(identity)}, nil
+	})
+	db.on("GetUserIdentities", func(args []any) ([][]any, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		var rows [][]any
+		for _, identity := range s.identities {
+			if identity.UserID == args[0] {
+				rows = append(rows, rowOf(identity))
+			}
+		}
+		return rows, nil
+	})
+	db.on("CreateDataExport", func(args []any) ([][]any, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		dataExport := database.DataExport{
+			ID:         args[0].(uuid.UUID),
+			CreatedAt:  args[1].(pgtype.Timestamp),
+			ModifiedAt: args[2].(pgtype.Timestamp),
+			UserID:     args[3].(uuid.UUID),
+			Status:     args[4].(database.ExportStatus),
+		}
+		s.exports[dataExport.ID] = dataExport
+		return [][]any{rowOf(dataExport)}, nil
+	})
+	db.on("GetDataExport", func(args []any) ([][]any, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		dataExport, exists := s.exports[args[0].(uuid.UUID)]
+		if !exists || dataExport.UserID != args[1] {
+			return nil, nil
+		}
+		return [][]any{rowOf(dataExport)}, nil
+	})
+	db.on("UpdateDataExport", func(args []any) ([][]any, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		dataExport := s.exports[args[3].(uuid.UUID)]
+		dataExport.Status, dataExport.S3Key, dataExport.ModifiedAt = args[0].(database.ExportStatus), args[1].(pgtype.Text), args[2].(pgtype.Timestamp)
+		s.exports[dataExport.ID] = dataExport
+
+		s.exported <- dataExport.ID
+		return [][]any{rowOf(dataExport)}, nil
+	})
+}
+
+// Content service which accepts every notification about the users
+type fakeContentService struct {
+	contentPB.UnimplementedContentServiceServer
+}
+
+func (s *fakeContentService) UserDeactivated(ctx context.Context, req *contentPB.UserDeactivatedRequest) (*contentPB.UserDeactivatedResponse, error) {
+	return &contentPB.UserDeactivatedResponse{}, nil
+}
+
+func (s *fakeContentService) UserRestored(ctx context.Context, req *contentPB.UserRestoredRequest) (*contentPB.UserRestoredResponse, error) {
+	return &contentPB.UserRestoredResponse{}, nil
+}
+
+func (s *fakeContentService) ExportUserData(ctx context.Context, req *contentPB.ExportUserDataRequest) (*contentPB.ExportUserDataResponse, error) {
+	return &contentPB.ExportUserDataResponse{}, nil
+}
+
+// Serve the fake content service and point the gRPC client to it
+func serveContentService(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	contentPB.RegisterContentServiceServer(server, &fakeContentService{})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	contentAddr := flag.Lookup("contentAddr").Value.String()
+	flag.Set("contentAddr", listener.Addr().String())
+	t.Cleanup(func() { flag.Set("contentAddr", contentAddr) })
+}
+
+// Encode a blank PNG image of the given size
+func encodeImage(t *testing.T, size int) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, size, size))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	s3 := newFakeS3(t)
+	t.Setenv("AWS_BUCKET_NAME", "media")
+	t.Setenv("AWS_EXPORT_BUCKET_NAME", "exports")
+	t.Setenv("AWS_CDN_BASE_URL", "https://cdn.example.com")
+
+	serveContentService(t)
+
+	// Provider is registered under its own name, Since the providers are cached by their names
+	provider := newMockProvider(t)
+	t.Setenv("OIDC_PROVIDERS", "spec")
+	t.Setenv("OIDC_SPEC_ISSUER", provider.URL)
+	t.Setenv("OIDC_SPEC_CLIENT_ID", mockClientID)
+	t.Setenv("OIDC_SPEC_CLIENT_SECRET", mockClientSecret)
+	t.Setenv("OIDC_SPEC_REDIRECT_URL", "http://localhost/api/v1/oidc/spec/callback/")
+
+	hashedPassword, err := utils.GetHashedPassword(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createdAt := pgtype.Timestamp{Time: time.Now().UTC().Add(-time.Hour), Valid: true}
+	dbUser := database.User{
+		ID:         uuid.New(),
+		CreatedAt:  createdAt,
+		ModifiedAt: createdAt,
+		Email:      "user@example.com",
+		Password:   hashedPassword,
+		Name:       pgtype.Text{String: "User", Valid: true},
+	}
+	readyExport := database.DataExport{
+		ID:         uuid.New(),
+		CreatedAt:  createdAt,
+		ModifiedAt: createdAt,
+		UserID:     dbUser.ID,
+		Status:     database.ExportStatusR,
+		S3Key:      pgtype.Text{String: "exports/" + dbUser.ID.String() + "/export.zip", Valid: true},
+	}
+
+	store := &userStore{
+		users: map[uuid.UUID]database.User{dbUser.ID: dbUser},
+		identities: map[string]database.UserIdentity{"spec:" + mockSubject: {
+			ID:        uuid.New(),
+			CreatedAt: createdAt,
+			UserID:    dbUser.ID,
+			Provider:  "spec",
+			Subject:   mockSubject,
+		}},
+		exports:  map[uuid.UUID]database.DataExport{readyExport.ID: readyExport},
+		exported: make(chan uuid.UUID, 1),
+	}
+
+	db := newFakeDB()
+	store.register(db)
+
+	spec := apitest.LoadSpec(t, openAPISpec)
+
+	engine := gin.New()
+	engine.Use(spec.Validator(t))
+	Routes(engine, &database.Config{DB: db, Queries: database.New(db)})
+
+	spec.CheckRoutes(t, engine.Routes())
+
+	tokens, err := utils.GenerateTokens(dbUser.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every route is called as anonymous with an empty body, Responses are validated by the engine
+	for _, route := range engine.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/") && !strings.HasPrefix(route.Path, "/.well-known/") {
+			continue
+		}
+
+		path := strings.NewReplacer(":id", uuid.NewString(), ":provider", "unknown").Replace(route.Path)
+
+		body := ""
+		if route.Method != http.MethodGet && route.Method != http.MethodDelete {
+			body = "{}"
+		}
+
+		t.Run(route.Method+" "+route.Path, func(t *testing.T) {
+			req := httptest.NewRequest(route.Method, path, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			serve(engine, req)
+		})
+	}
+
+	// Uploaded avatar which is processed by the update avatar API
+	uploadKey := "avatars/" + dbUser.ID.String() + "/uploads/" + uuid.NewString() + ".png"
+	s3.put("media/"+uploadKey, encodeImage(t, 128))
+
+	// Every operation is called with a valid request as well, In order as the user would
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		auth   bool
+		status int
+		check  func(t *testing.T)
+	}{
+		{method: http.MethodGet, path: "/.well-known/jwks.json", status: http.StatusOK},
+		{method: http.MethodPost, path: "/api/v1/register/", body: `{"email": "new@example.com", "password": "` + testPassword + `"}`, status: http.StatusCreated},
+		{method: http.MethodPost, path: "/api/v1/register/", body: `{"email": "user@example.com", "password": "` + testPassword + `"}`, status: http.StatusConflict},
+		{method: http.MethodPost, path: "/api/v1/login/", body: `{"email": "user@example.com", "password": "` + testPassword + `"}`, status: http.StatusOK},
+		{method: http.MethodPost, path: "/api/v1/refresh-token/", body: `{"refresh_token": "` + tokens.Refresh + `"}`, status: http.StatusOK},
+		{method: http.MethodGet, path: "/api/v1/profile/", auth: true, status: http.StatusOK},
+		{method: http.MethodPatch, path: "/api/v1/profile/", body: `{"name": "New name"}`, auth: true, status: http.StatusOK},
+		{method: http.MethodPut, path: "/api/v1/change-password/", body: `{"old_password": "` + testPassword + `", "new_password": "NewPassword@123"}`, auth: true, status: http.StatusOK},
+		{method: http.MethodPost, path: "/api/v1/profile/avatar/upload-url/", body: `{"filename": "avatar.png"}`, auth: true, status: http.StatusOK},
+		{method: http.MethodPut, path: "/api/v1/profile/avatar/", body: `{"key": "` + uploadKey + `"}`, auth: true, status: http.StatusOK, check: func(t *testing.T) {
+			if _, exists := s3.get("media/" + uploadKey); exists {
+				t.Error("uploaded avatar is not removed after processing")
+			}
+
+			avatarKey := store.users[dbUser.ID].AvatarKey.String
+			for name := range internal.AvatarSizes {
+				if _, exists := s3.get("media/" + avatarKey + "/" + name + ".jpg"); !exists {
+					t.Errorf("%s avatar is not uploaded", name)
+				}
+			}
+		}},
+		{method: http.MethodDelete, path: "/api/v1/profile/avatar/", auth: true, status: http.StatusOK},
+		{method: http.MethodGet, path: "/api/v1/profile/export/" + readyExport.ID.String() + "/", auth: true, status: http.StatusOK},
+		{method: http.MethodPost, path: "/api/v1/profile/export/", auth: true, status: http.StatusAccepted},
+		{method: http.MethodDelete, path: "/api/v1/profile/", auth: true, status: http.StatusOK},
+		{method: http.MethodGet, path: "/api/v1/profile/", auth: true, status: http.StatusForbidden},
+		{method: http.MethodPost, path: "/api/v1/profile/restore/", auth: true, status: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.auth {
+				req.Header.Set("Authorization", "Bearer "+tokens.Access)
+			}
+
+			rec := serve(engine, req)
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.check != nil {
+				tc.check(t)
+			}
+		})
+	}
+
+	t.Run("data export", func(t *testing.T) {
+		var exportID uuid.UUID
+		select {
+		case exportID = <-store.exported:
+		case <-time.After(10 * time.Second):
+			t.Fatal("data export is not built")
+		}
+
+		dataExport := store.exports[exportID]
+		if dataExport.Status != database.ExportStatusR {
+			t.Fatalf("expected the export to be ready, got status %s", dataExport.Status)
+		}
+		if _, exists := s3.get("exports/" + dataExport.S3Key.String); !exists {
+			t.Errorf("export archive is not uploaded to %s", dataExport.S3Key.String)
+		}
+	})
+
+	t.Run("OIDC login", func(t *testing.T) {
+		rec := serve(engine, startOIDCLogin(t, engine, "spec"))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+
+		var res struct {
+			Data utils.Tokens `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		if claims, err := utils.VerifyToken(res.Data.Access); err != nil || claims.Data != dbUser.ID.String() {
+			t.Errorf("expected tokens of the linked user, got %v: %v", claims, err)
+		}
+	})
+
+	spec.CheckSucceeded(t)
+}
@@ -4,15 +4,15 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apidocs"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/user/database"
 )
 
-func Routes(engine *gin.Engine, dbConn *pgx.Conn) {
-	dbConfig := &database.Config{
-		DB:      dbConn,
-		Queries: database.New(dbConn),
-	}
+// Register the API routes, Deleted accounts are purged by the gRPC service
+func Routes(engine *gin.Engine, dbConfig *database.Config) {
+	// Assign an ID to every request, Sent back in the error responses
+	engine.Use(apierror.RequestID())
 
 	// Default route for health check
 	engine.GET("/health-check/", func(ctx *gin.Context) {
@@ -21,19 +21,13 @@ func Routes(engine *gin.Engine, dbConn *pgx.Conn) {
 		})
 	})
 
-	// Public API Routes
-	pubRouter := engine.Group("/api/v1/")
-	authRouter := pubRouter.Group("")
-	authRouter.Use(JWTAuth((dbConfig)))
+	// OpenAPI spec of the REST APIs along with its docs page
+	apidocs.Routes(engine, openAPISpec, "User API")
 
-	// Non auth routes
-	pubRouter.POST("register/", signUp(dbConfig))
-	pubRouter.POST("login/", login(dbConfig))
-	pubRouter.POST("refresh-token/", refreshAccessToken)
-
-	// Auth routes
-	authRouter.GET("profile/", getUserProfile)
-	authRouter.PATCH("profile/", updateUserProfile(dbConfig))
-	authRouter.DELETE("profile/", deleteUserProfile(dbConfig))
-	authRouter.PUT("change-password/", changePassword(dbConfig))
+	// Operations of the OpenAPI spec, Authenticated by their security requirements,
+	// Restore route is available for the accounts which are scheduled for deletion.
+	RegisterHandlers(engine, &server{dbConfig: dbConfig}, SecurityHandlers{
+		BearerAuth:         JWTAuth(dbConfig, false),
+		DeletedAccountAuth: JWTAuth(dbConfig, true),
+	})
 }
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// In-memory stand-in of S3 used by the API tests, Serves the object APIs called by the service with path style URLs
+type fakeS3 struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+// Start the fake S3 and point the AWS clients to it, Along with the dummy credentials
+func newFakeS3(t *testing.T) *fakeS3 {
+	s := &fakeS3{objects: make(map[string][]byte)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+
+	t.Setenv("AWS_ENDPOINT_URL", s.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	return s
+}
+
+// Return the object stored with the given key, Keys are prefixed by their bucket
+func (s *fakeS3) get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, exists := s.objects[key]
+	return data, exists
+}
+
+func (s *fakeS3) put(key string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.objects[key] = data
+}
+
+func (s *fakeS3) serve(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	query := r.URL.Query()
+
+	switch {
+	case r.Method == http.MethodPut && key != "":
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.put(bucket+"/"+key, data)
+
+	case r.Method == http.MethodGet && key != "":
+		data, exists := s.get(bucket + "/" + key)
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			xml.NewEncoder(w).Encode(struct {
+				XMLName xml.Name `xml:"Error"`
+				Code    string
+			}{Code: "NoSuchKey"})
+			return
+		}
+		w.Write(data)
+
+	case r.Method == http.MethodGet && query.Get("list-type") == "2":
+		type object struct {
+			Key  string
+			Size int
+		}
+		result := struct {
+			XMLName  xml.Name `xml:"ListBucketResult"`
+			Name     string
+			Prefix   string
+			KeyCount int
+			Contents []object
+		}{Name: bucket, Prefix: query.Get("prefix")}
+
+		s.mu.Lock()
+		for objectKey, data := range s.objects {
+			if name, ok := strings.CutPrefix(objectKey, bucket+"/"); ok && strings.HasPrefix(name, result.Prefix) {
+				result.Contents = append(result.Contents, object{Key: name, Size: len(data)})
+			}
+		}
+		s.mu.Unlock()
+
+		slices.SortFunc(result.Contents, func(a, b object) int { return strings.Compare(a.Key, b.Key) })
+		result.KeyCount = len(result.Contents)
+		xml.NewEncoder(w).Encode(result)
+
+	case r.Method == http.MethodPost && query.Has("delete"):
+		var request struct {
+			Objects []struct {
+				Key string
+			} `xml:"Object"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.mu.Lock()
+		for _, object := range request.Objects {
+			delete(s.objects, bucket+"/"+object.Key)
+		}
+		s.mu.Unlock()
+
+		xml.NewEncoder(w).Encode(struct {
+			XMLName xml.Name `xml:"DeleteResult"`
+		}{})
+
+	default:
+		http.Error(w, "unexpected S3 request: "+r.Method+" "+r.URL.String(), http.StatusNotImplemented)
+	}
+}
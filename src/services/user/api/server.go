@@ -0,0 +1,77 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/database"
+)
+
+// Handlers of the operations in the OpenAPI spec, Served by the generated RegisterHandlers
+type server struct {
+	dbConfig *database.Config
+}
+
+var _ ServerInterface = (*server)(nil)
+
+func (s *server) GetJWKS(ctx *gin.Context) {
+	getJWKS(ctx)
+}
+
+func (s *server) ChangePassword(ctx *gin.Context) {
+	changePassword(s.dbConfig)(ctx)
+}
+
+func (s *server) Login(ctx *gin.Context) {
+	login(s.dbConfig)(ctx)
+}
+
+func (s *server) OidcCallback(ctx *gin.Context) {
+	oidcCallback(s.dbConfig)(ctx)
+}
+
+func (s *server) OidcLogin(ctx *gin.Context) {
+	oidcLogin(ctx)
+}
+
+func (s *server) GetUserProfile(ctx *gin.Context) {
+	getUserProfile(ctx)
+}
+
+func (s *server) UpdateUserProfile(ctx *gin.Context) {
+	updateUserProfile(s.dbConfig)(ctx)
+}
+
+func (s *server) DeleteUserProfile(ctx *gin.Context) {
+	deleteUserProfile(s.dbConfig)(ctx)
+}
+
+func (s *server) UpdateAvatar(ctx *gin.Context) {
+	updateAvatar(s.dbConfig)(ctx)
+}
+
+func (s *server) DeleteAvatar(ctx *gin.Context) {
+	deleteAvatar(s.dbConfig)(ctx)
+}
+
+func (s *server) GetAvatarUploadURL(ctx *gin.Context) {
+	getAvatarUploadURL(ctx)
+}
+
+func (s *server) RequestDataExport(ctx *gin.Context) {
+	requestDataExport(s.dbConfig)(ctx)
+}
+
+func (s *server) GetDataExport(ctx *gin.Context) {
+	getDataExport(s.dbConfig)(ctx)
+}
+
+func (s *server) RestoreUserProfile(ctx *gin.Context) {
+	restoreUserProfile(s.dbConfig)(ctx)
+}
+
+func (s *server) RefreshAccessToken(ctx *gin.Context) {
+	refreshAccessToken(ctx)
+}
+
+func (s *server) SignUp(ctx *gin.Context) {
+	signUp(s.dbConfig)(ctx)
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -9,7 +11,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgtype"
 	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/user/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/internal"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/user/utils"
 	"github.com/thejasmeetsingh/spotify-clone/src/services/user/validators"
 )
@@ -19,13 +23,13 @@ func getUserFromCtx(ctx *gin.Context) (User, error) {
 	value, exists := ctx.Get("user")
 
 	if !exists {
-		return User{}, fmt.Errorf("authentication required")
+		return User{}, apierror.Unauthenticated("Authentication required")
 	}
 
 	user, ok := value.(User)
 
 	if !ok {
-		return User{}, fmt.Errorf("invalid user")
+		return User{}, apierror.Internal(fmt.Errorf("invalid user in request context"))
 	}
 
 	return user, nil
@@ -35,7 +39,7 @@ func getUserFromCtx(ctx *gin.Context) (User, error) {
 func getUserProfile(ctx *gin.Context) {
 	user, err := getUserFromCtx(ctx)
 	if err != nil {
-		ctx.SecureJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+		apierror.Respond(ctx, err)
 		return
 	}
 	ctx.SecureJSON(http.StatusOK, gin.H{"data": user})
@@ -46,7 +50,7 @@ func updateUserProfile(dbCfg *database.Config) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		user, err := getUserFromCtx(ctx)
 		if err != nil {
-			ctx.SecureJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			apierror.Respond(ctx, err)
 			return
 		}
 
@@ -58,13 +62,13 @@ func updateUserProfile(dbCfg *database.Config) gin.HandlerFunc {
 
 		if err = ctx.ShouldBindJSON(&params); err != nil {
 			log.Errorln("Error caught while parsing update user detail API request data: ", err)
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Error while parsing the request data"})
+			apierror.Respond(ctx, apierror.FromBinding(err))
 			return
 		}
 
 		// check if request body is empty
 		if params.Name == "" && params.Email == "" {
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Invalid request data"})
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid request data"))
 			return
 		}
 
@@ -83,14 +87,14 @@ func updateUserProfile(dbCfg *database.Config) gin.HandlerFunc {
 
 		// Validate the given email address
 		if !validators.EmailValidator(params.Email) {
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Invalid email address"})
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid email address"))
 			return
 		}
 
 		// Check if user any exists with the new email address
 		if isEmailChanged && email != user.Email {
 			if _, err = database.GetUserByEmailDB(dbCfg, ctx, email); err == nil {
-				ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "User with this email address already exists"})
+				apierror.Respond(ctx, apierror.AlreadyExists("User with this email address already exists").WithField("email", "Already in use"))
 				return
 			}
 		}
@@ -110,8 +114,8 @@ func updateUserProfile(dbCfg *database.Config) gin.HandlerFunc {
 		})
 
 		if err != nil {
-			log.Fatalln("error while updating user details: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			log.Errorln("error while updating user details: ", err)
+			apierror.Respond(ctx, apierror.FromDB(err, "", "User with this email address already exists"))
 			return
 		}
 
@@ -125,15 +129,78 @@ func deleteUserProfile(dbCfg *database.Config) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		user, err := getUserFromCtx(ctx)
 		if err != nil {
-			ctx.SecureJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			apierror.Respond(ctx, err)
 			return
 		}
 
-		if err = database.DeleteUserDB(dbCfg, ctx, user.ID); err != nil {
-			ctx.SecureJSON(http.StatusForbidden, gin.H{"message": "Something went wrong"})
+		// Content service rejects the tokens of the user from now on, It is notified first
+		// so that a deleted account is never left with working tokens
+		if err = internal.NotifyUserDeactivated(user.ID); err != nil {
+			log.Errorln("error while notifying content service about deactivated user: ", err)
+			apierror.Respond(ctx, err)
 			return
 		}
-		ctx.SecureJSON(http.StatusOK, gin.H{"message": "Profile deleted successfully!"})
+
+		// Account is deleted permanently after the grace period, Till then it can be restored
+		currentTime := time.Now().UTC()
+
+		if err = database.SoftDeleteUserDB(dbCfg, ctx, database.SoftDeleteUserParams{
+			DeletedAt: pgtype.Timestamp{
+				Time:  currentTime,
+				Valid: true,
+			},
+			ModifiedAt: pgtype.Timestamp{
+				Time:  currentTime,
+				Valid: true,
+			},
+			ID: user.ID,
+		}); err != nil {
+			log.Errorln("error while deleting user profile: ", err)
+			if notifyErr := internal.NotifyUserRestored(user.ID); notifyErr != nil {
+				log.Errorln("error while notifying content service about restored user: ", notifyErr)
+			}
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		gracePeriodDays := int(internal.GetDeletionGracePeriod().Hours() / 24)
+		ctx.SecureJSON(http.StatusOK, gin.H{
+			"message": fmt.Sprintf("Profile will be deleted permanently after %d days, You can restore it before that", gracePeriodDays),
+		})
+	}
+}
+
+// Restore the profile which is scheduled for deletion
+func restoreUserProfile(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, err := getUserFromCtx(ctx)
+		if err != nil {
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		// Content service is notified first, Restoring is idempotent so a failed request can simply be retried
+		if err = internal.NotifyUserRestored(user.ID); err != nil {
+			log.Errorln("error while notifying content service about restored user: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbUser, err := database.RestoreUserDB(dbCfg, ctx, database.RestoreUserParams{
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			ID: user.ID,
+		})
+
+		if err != nil {
+			log.Errorln("error while restoring user profile: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"message": "Profile restored successfully!", "data": databaseUserToUser(dbUser)})
 	}
 }
 
@@ -142,7 +209,7 @@ func changePassword(dbCfg *database.Config) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		user, err := getUserFromCtx(ctx)
 		if err != nil {
-			ctx.SecureJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			apierror.Respond(ctx, err)
 			return
 		}
 
@@ -154,15 +221,15 @@ func changePassword(dbCfg *database.Config) gin.HandlerFunc {
 		var params Parameters
 		if err = ctx.ShouldBindJSON(&params); err != nil {
 			log.Errorln("Error caught while parsing change password API request data: ", err)
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Error while parsing the request data"})
+			apierror.Respond(ctx, apierror.FromBinding(err))
 			return
 		}
 
 		// Fetch user by ID from DB
 		dbUser, err := database.GetUserByIDFromDB(dbCfg, ctx, user.ID)
 		if err != nil {
-			log.Fatalln("error while getting user details by ID: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			log.Errorln("error while getting user details by ID: ", err)
+			apierror.Respond(ctx, apierror.FromDB(err, "User not found", ""))
 			return
 		}
 
@@ -170,22 +237,22 @@ func changePassword(dbCfg *database.Config) gin.HandlerFunc {
 		match, err := utils.CheckPasswordValid(params.OldPassword, dbUser.Password)
 		if err != nil {
 			log.Errorln("Error caught while checking current password: ", err)
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Invalid old password, Please try again."})
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid old password, Please try again."))
 			return
 		} else if !match {
 			log.Errorln("Error caught while checking current password: ", err)
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Invalid old password, Please try again."})
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid old password, Please try again."))
 			return
 		}
 
 		if params.OldPassword == params.NewPassword {
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "New password should not be same as old password"})
+			apierror.Respond(ctx, apierror.InvalidArgument("New password should not be same as old password"))
 			return
 		}
 
 		// Validate the new password
 		if err = validators.PasswordValidator(params.NewPassword, dbUser.Email); err != nil {
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			apierror.Respond(ctx, apierror.InvalidArgument(err.Error()).WithField("new_password", err.Error()))
 			return
 		}
 
@@ -194,7 +261,7 @@ func changePassword(dbCfg *database.Config) gin.HandlerFunc {
 
 		if err != nil {
 			log.Errorln("Error caught while generating hashed password: ", err)
-			ctx.SecureJSON(http.StatusBadRequest, gin.H{"message": "Something went wrong"})
+			apierror.Respond(ctx, err)
 			return
 		}
 
@@ -209,11 +276,142 @@ func changePassword(dbCfg *database.Config) gin.HandlerFunc {
 		})
 
 		if err != nil {
-			log.Fatalln("error while updating password: ", err)
-			ctx.SecureJSON(http.StatusInternalServerError, gin.H{"message": "Something went wrong"})
+			log.Errorln("error while updating password: ", err)
+			apierror.Respond(ctx, err)
 			return
 		}
 
 		ctx.SecureJSON(http.StatusOK, gin.H{"message": "Password changed successfully!"})
 	}
 }
+
+// API for getting pre-signed URL for avatar upload
+func getAvatarUploadURL(ctx *gin.Context) {
+	user, err := getUserFromCtx(ctx)
+	if err != nil {
+		apierror.Respond(ctx, err)
+		return
+	}
+
+	type Parameters struct {
+		FileName string `json:"filename" binding:"required"`
+	}
+	var params Parameters
+
+	if err = ctx.ShouldBindJSON(&params); err != nil {
+		log.Errorln("Error caught while parsing avatar upload URL API request data: ", err)
+		apierror.Respond(ctx, apierror.FromBinding(err))
+		return
+	}
+
+	key := internal.GetAvatarUploadKey(user.ID, params.FileName)
+
+	url, err := internal.GetUploadURL(ctx, key, time.Minute*5)
+	if err != nil {
+		log.Errorln("error caught while generating pre-sign avatar upload URL: ", err)
+		apierror.Respond(ctx, err)
+		return
+	}
+
+	ctx.SecureJSON(http.StatusOK, gin.H{"data": map[string]string{
+		"url": url,
+		"key": key,
+	}})
+}
+
+// Update the user avatar with the uploaded image
+func updateAvatar(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, err := getUserFromCtx(ctx)
+		if err != nil {
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		type Parameters struct {
+			Key string `json:"key" binding:"required"`
+		}
+		var params Parameters
+
+		if err = ctx.ShouldBindJSON(&params); err != nil {
+			log.Errorln("Error caught while parsing update avatar API request data: ", err)
+			apierror.Respond(ctx, apierror.FromBinding(err))
+			return
+		}
+
+		if !internal.IsAvatarUploadKey(user.ID, params.Key) {
+			apierror.Respond(ctx, apierror.InvalidArgument("Invalid avatar key"))
+			return
+		}
+
+		// Validate and resize the uploaded image
+		avatarKey, err := internal.ProcessAvatar(ctx, user.ID, params.Key)
+		if errors.Is(err, internal.ErrUnsupportedImage) || errors.Is(err, internal.ErrImageTooLarge) || errors.Is(err, internal.ErrImageDimensions) {
+			apierror.Respond(ctx, apierror.InvalidArgument(err.Error()))
+			return
+		} else if err != nil {
+			log.Errorln("error while processing avatar: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbUser, err := database.UpdateUserAvatarDB(dbCfg, ctx, database.UpdateUserAvatarParams{
+			AvatarKey: pgtype.Text{
+				String: avatarKey,
+				Valid:  true,
+			},
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			ID: user.ID,
+		})
+
+		if err != nil {
+			log.Errorln("error while updating avatar: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		// Remove the previous avatar in background
+		go deleteOldAvatar(user.avatarKey)
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"message": "Avatar updated successfully!", "data": databaseUserToUser(dbUser)})
+	}
+}
+
+// Remove the user avatar
+func deleteAvatar(dbCfg *database.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, err := getUserFromCtx(ctx)
+		if err != nil {
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		dbUser, err := database.UpdateUserAvatarDB(dbCfg, ctx, database.UpdateUserAvatarParams{
+			AvatarKey: pgtype.Text{},
+			ModifiedAt: pgtype.Timestamp{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+			ID: user.ID,
+		})
+
+		if err != nil {
+			log.Errorln("error while removing avatar: ", err)
+			apierror.Respond(ctx, err)
+			return
+		}
+
+		go deleteOldAvatar(user.avatarKey)
+
+		ctx.SecureJSON(http.StatusOK, gin.H{"message": "Avatar removed successfully!", "data": databaseUserToUser(dbUser)})
+	}
+}
+
+func deleteOldAvatar(avatarKey string) {
+	if err := internal.DeleteAvatar(context.Background(), avatarKey); err != nil {
+		log.Errorln("error caught while deleting old avatar: ", err, "key: ", avatarKey)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/common/apierror"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/database"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/internal"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type server struct {
+	pb.UnimplementedUserServiceServer
+	dbCfg *database.Config
+}
+
+func valid(authorization []string) bool {
+	if len(authorization) < 1 {
+		return false
+	}
+	token := strings.TrimPrefix(authorization[0], "Bearer ")
+	return token == os.Getenv("GRPC_AUTH_KEY")
+}
+
+func ensureValidToken(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "missing metadata")
+	}
+	if !valid(md["authorization"]) {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+	// Continue execution of handler after ensuring a valid token.
+	return handler(ctx, req)
+}
+
+func main() {
+	// DB config, Connection pool is shared by the gRPC handlers and the background jobs
+	dbPool, err := pgxpool.New(context.Background(), os.Getenv("DB_URL"))
+	if err != nil {
+		log.Fatalln("error while connecting to DB: ", err)
+	}
+	defer dbPool.Close()
+
+	if err = dbPool.Ping(context.Background()); err != nil {
+		log.Fatalln("error while connecting to DB: ", err)
+	}
+
+	dbConfig := &database.Config{
+		DB:      dbPool,
+		Queries: database.New(dbPool),
+	}
+
+	// Permanently delete the accounts whose grace period is over in background
+	go internal.PurgeDeletedAccounts(dbConfig)
+
+	// Build the data exports which were interrupted by a restart of the HTTP service in background
+	go internal.RetryStaleDataExports(dbConfig)
+
+	lis, err := net.Listen("tcp", ":"+os.Getenv("GRPC_PORT"))
+	if err != nil {
+		log.Fatalln("failed to listen gRPC: ", err)
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(ensureValidToken, apierror.UnaryServerInterceptor),
+	}
+
+	grpcServer := grpc.NewServer(opts...)
+	pb.RegisterUserServiceServer(grpcServer, &server{dbCfg: dbConfig})
+
+	log.Infoln("gRPC service is up & running")
+
+	if err := grpcServer.Serve(lis); err != nil {
+		log.Fatalln("failed to serve gRPC: ", err)
+	}
+}
+
+func databaseUserToUserDetail(dbUser *database.User) *pb.UserDetailResponse {
+	return &pb.UserDetailResponse{
+		Id:     dbUser.ID.String(),
+		Name:   dbUser.Name.String,
+		Email:  dbUser.Email,
+		Avatar: internal.GetAvatarURLs(dbUser.AvatarKey.String),
+	}
+}
+
+// gRPC request handler, Return the public details of the given users
+//
+// Used by other services for showing the creator details in listings, Unknown or deleted users are skipped
+func (s *server) UsersDetail(ctx context.Context, in *pb.UsersDetailRequest) (*pb.UsersDetailResponse, error) {
+	userIDs := make([]uuid.UUID, 0, len(in.GetIds()))
+	for _, id := range in.GetIds() {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, apierror.InvalidArgument("Invalid user ID: "+id).WithField("ids", "Invalid ID: "+id)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	dbUsers, err := database.GetUsersByIDsFromDB(s.dbCfg, ctx, userIDs)
+	if err != nil {
+		log.Errorln("error caught while fetching users: ", err)
+		return nil, err
+	}
+
+	users := make([]*pb.UserDetailResponse, 0, len(dbUsers))
+	for _, dbUser := range dbUsers {
+		user := databaseUserToUserDetail(&dbUser)
+
+		// Email address is private
+		user.Email = ""
+		users = append(users, user)
+	}
+
+	return &pb.UsersDetailResponse{Users: users}, nil
+}
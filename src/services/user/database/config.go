@@ -1,10 +1,17 @@
 package database
 
 import (
+	"context"
+
 	"github.com/jackc/pgx/v5"
 )
 
+// Starts the DB transactions, Connection pool is used outside the tests
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
 type Config struct {
-	DB      *pgx.Conn
+	DB      TxBeginner
 	Queries *Queries
 }
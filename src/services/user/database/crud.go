@@ -71,6 +71,60 @@ func UpdateUserPasswordDB(c *Config, ctx context.Context, params UpdateUserPassw
 	return nil
 }
 
+// Mark the user as deleted, User will be removed permanently after the grace period
+func SoftDeleteUserDB(c *Config, ctx context.Context, params SoftDeleteUserParams) error {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	if err := qtx.SoftDeleteUser(ctx, params); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Restore the user which was marked as deleted
+func RestoreUserDB(c *Config, ctx context.Context, params RestoreUserParams) (*User, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	user, err := qtx.RestoreUser(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Get IDs of the users which were marked as deleted before the given time
+func GetUsersDeletedBeforeDB(c *Config, ctx context.Context, params GetUsersDeletedBeforeParams) ([]uuid.UUID, error) {
+	userIDs, err := c.Queries.GetUsersDeletedBefore(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
 // Delete user from DB
 func DeleteUserDB(apiCfg *Config, ctx context.Context, userID uuid.UUID) error {
 	// Begin DB transaction
@@ -92,3 +146,186 @@ func DeleteUserDB(apiCfg *Config, ctx context.Context, userID uuid.UUID) error {
 	}
 	return nil
 }
+
+// Get linked external identity by provider and subject from DB
+func GetUserIdentityDB(c *Config, ctx context.Context, params GetUserIdentityParams) (*UserIdentity, error) {
+	identity, err := c.Queries.GetUserIdentity(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// Link an external identity to an existing user
+func CreateUserIdentityDB(c *Config, ctx context.Context, params CreateUserIdentityParams) (*UserIdentity, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	identity, err := qtx.CreateUserIdentity(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// Create a new user along with the linked external identity
+func CreateUserWithIdentityDB(
+	c *Config,
+	ctx context.Context,
+	userParams CreateUserParams,
+	detailParams UpdateUserDetailsParams,
+	identityParams CreateUserIdentityParams,
+) (*User, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	user, err := qtx.CreateUser(ctx, userParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the name given by the provider
+	if detailParams.Name.Valid {
+		detailParams.ID = user.ID
+		if user, err = qtx.UpdateUserDetails(ctx, detailParams); err != nil {
+			return nil, err
+		}
+	}
+
+	identityParams.UserID = user.ID
+	if _, err = qtx.CreateUserIdentity(ctx, identityParams); err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Get linked external identities of a user from DB
+func GetUserIdentitiesDB(c *Config, ctx context.Context, userID uuid.UUID) ([]UserIdentity, error) {
+	identities, err := c.Queries.GetUserIdentities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+// Add a data export request into DB
+func CreateDataExportDB(c *Config, ctx context.Context, params CreateDataExportParams) (*DataExport, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	dataExport, err := qtx.CreateDataExport(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &dataExport, nil
+}
+
+// Get data export request of a user from DB
+func GetDataExportDB(c *Config, ctx context.Context, params GetDataExportParams) (*DataExport, error) {
+	dataExport, err := c.Queries.GetDataExport(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &dataExport, nil
+}
+
+// Mark the pending data exports created before the given time as failed
+func FailStaleDataExportsDB(c *Config, ctx context.Context, params FailStaleDataExportsParams) error {
+	return c.Queries.FailStaleDataExports(ctx, params)
+}
+
+// Claim the pending data exports which are not updated since the given time, So that they can be built again
+func ClaimStaleDataExportsDB(c *Config, ctx context.Context, params ClaimStaleDataExportsParams) ([]DataExport, error) {
+	dataExports, err := c.Queries.ClaimStaleDataExports(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return dataExports, nil
+}
+
+// Update the status of a data export request
+func UpdateDataExportDB(c *Config, ctx context.Context, params UpdateDataExportParams) error {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	if err := qtx.UpdateDataExport(ctx, params); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Update user avatar key in DB
+func UpdateUserAvatarDB(c *Config, ctx context.Context, params UpdateUserAvatarParams) (*User, error) {
+	// Begin DB transaction
+	tx, err := c.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.Queries.WithTx(tx)
+
+	user, err := qtx.UpdateUserAvatar(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Get active users by their IDs from DB
+func GetUsersByIDsFromDB(c *Config, ctx context.Context, userIDs []uuid.UUID) ([]User, error) {
+	users, err := c.Queries.GetUsersByIds(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -5,10 +5,65 @@
 package database
 
 import (
+	"database/sql/driver"
+	"fmt"
+
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ExportStatus string
+
+const (
+	ExportStatusP ExportStatus = "P"
+	ExportStatusR ExportStatus = "R"
+	ExportStatusF ExportStatus = "F"
+)
+
+func (e *ExportStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ExportStatus(s)
+	case string:
+		*e = ExportStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ExportStatus: %T", src)
+	}
+	return nil
+}
+
+type NullExportStatus struct {
+	ExportStatus ExportStatus
+	Valid        bool // Valid is true if ExportStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullExportStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ExportStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ExportStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullExportStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ExportStatus), nil
+}
+
+type DataExport struct {
+	ID         uuid.UUID
+	CreatedAt  pgtype.Timestamp
+	ModifiedAt pgtype.Timestamp
+	UserID     uuid.UUID
+	Status     ExportStatus
+	S3Key      pgtype.Text
+}
+
 type User struct {
 	ID         uuid.UUID
 	CreatedAt  pgtype.Timestamp
@@ -16,4 +71,16 @@ type User struct {
 	Email      string
 	Password   string
 	Name       pgtype.Text
+	DeletedAt  pgtype.Timestamp
+	AvatarKey  pgtype.Text
+}
+
+type UserIdentity struct {
+	ID         uuid.UUID
+	CreatedAt  pgtype.Timestamp
+	ModifiedAt pgtype.Timestamp
+	UserID     uuid.UUID
+	Provider   string
+	Subject    string
+	Email      pgtype.Text
 }
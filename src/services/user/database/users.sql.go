@@ -15,7 +15,7 @@ import (
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (id, created_at, modified_at, email, password) 
 VALUES ($1, $2, $3, $4, $5)
-RETURNING id, created_at, modified_at, email, password, name
+RETURNING id, created_at, modified_at, email, password, name, deleted_at, avatar_key
 `
 
 type CreateUserParams struct {
@@ -42,6 +42,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.Password,
 		&i.Name,
+		&i.DeletedAt,
+		&i.AvatarKey,
 	)
 	return i, err
 }
@@ -56,7 +58,7 @@ func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, created_at, modified_at, email, password, name FROM users WHERE email=$1 FOR UPDATE NOWAIT
+SELECT id, created_at, modified_at, email, password, name, deleted_at, avatar_key FROM users WHERE email=$1 FOR UPDATE NOWAIT
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -69,12 +71,14 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Email,
 		&i.Password,
 		&i.Name,
+		&i.DeletedAt,
+		&i.AvatarKey,
 	)
 	return i, err
 }
 
 const getUserById = `-- name: GetUserById :one
-SELECT id, created_at, modified_at, email, password, name FROM users WHERE id=$1 FOR UPDATE NOWAIT
+SELECT id, created_at, modified_at, email, password, name, deleted_at, avatar_key FROM users WHERE id=$1 FOR UPDATE NOWAIT
 `
 
 func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (User, error) {
@@ -87,6 +91,141 @@ func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.Email,
 		&i.Password,
 		&i.Name,
+		&i.DeletedAt,
+		&i.AvatarKey,
+	)
+	return i, err
+}
+
+const getUsersByIds = `-- name: GetUsersByIds :many
+SELECT id, created_at, modified_at, email, password, name, deleted_at, avatar_key FROM users WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUsersByIds(ctx context.Context, dollar_1 []uuid.UUID) ([]User, error) {
+	rows, err := q.db.Query(ctx, getUsersByIds, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.ModifiedAt,
+			&i.Email,
+			&i.Password,
+			&i.Name,
+			&i.DeletedAt,
+			&i.AvatarKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsersDeletedBefore = `-- name: GetUsersDeletedBefore :many
+SELECT id FROM users WHERE deleted_at < $1 ORDER BY deleted_at LIMIT $2
+`
+
+type GetUsersDeletedBeforeParams struct {
+	DeletedAt pgtype.Timestamp
+	Limit     int32
+}
+
+func (q *Queries) GetUsersDeletedBefore(ctx context.Context, arg GetUsersDeletedBeforeParams) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getUsersDeletedBefore, arg.DeletedAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users SET deleted_at=NULL, modified_at=$1
+WHERE id=$2
+RETURNING id, created_at, modified_at, email, password, name, deleted_at, avatar_key
+`
+
+type RestoreUserParams struct {
+	ModifiedAt pgtype.Timestamp
+	ID         uuid.UUID
+}
+
+func (q *Queries) RestoreUser(ctx context.Context, arg RestoreUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, restoreUser, arg.ModifiedAt, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.Email,
+		&i.Password,
+		&i.Name,
+		&i.DeletedAt,
+		&i.AvatarKey,
+	)
+	return i, err
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :exec
+UPDATE users SET deleted_at=$1, modified_at=$2
+WHERE id=$3
+`
+
+type SoftDeleteUserParams struct {
+	DeletedAt  pgtype.Timestamp
+	ModifiedAt pgtype.Timestamp
+	ID         uuid.UUID
+}
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, arg SoftDeleteUserParams) error {
+	_, err := q.db.Exec(ctx, softDeleteUser, arg.DeletedAt, arg.ModifiedAt, arg.ID)
+	return err
+}
+
+const updateUserAvatar = `-- name: UpdateUserAvatar :one
+UPDATE users SET avatar_key=$1, modified_at=$2
+WHERE id=$3
+RETURNING id, created_at, modified_at, email, password, name, deleted_at, avatar_key
+`
+
+type UpdateUserAvatarParams struct {
+	AvatarKey  pgtype.Text
+	ModifiedAt pgtype.Timestamp
+	ID         uuid.UUID
+}
+
+func (q *Queries) UpdateUserAvatar(ctx context.Context, arg UpdateUserAvatarParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserAvatar, arg.AvatarKey, arg.ModifiedAt, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.Email,
+		&i.Password,
+		&i.Name,
+		&i.DeletedAt,
+		&i.AvatarKey,
 	)
 	return i, err
 }
@@ -94,7 +233,7 @@ func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (User, error) {
 const updateUserDetails = `-- name: UpdateUserDetails :one
 UPDATE users SET email=$1, name=$2, modified_at=$3
 WHERE id=$4
-RETURNING id, created_at, modified_at, email, password, name
+RETURNING id, created_at, modified_at, email, password, name, deleted_at, avatar_key
 `
 
 type UpdateUserDetailsParams struct {
@@ -119,6 +258,8 @@ func (q *Queries) UpdateUserDetails(ctx context.Context, arg UpdateUserDetailsPa
 		&i.Email,
 		&i.Password,
 		&i.Name,
+		&i.DeletedAt,
+		&i.AvatarKey,
 	)
 	return i, err
 }
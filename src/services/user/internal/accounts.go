@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/database"
+)
+
+const (
+	purgeInterval  = 1 * time.Hour
+	purgeBatchSize = 100
+)
+
+// Return the duration for which a deleted account can be restored
+func GetDeletionGracePeriod() time.Duration {
+	gracePeriod, err := strconv.Atoi(os.Getenv("ACCOUNT_DELETION_GRACE_DAYS"))
+	if err != nil {
+		gracePeriod = 30
+	}
+
+	return time.Hour * 24 * time.Duration(gracePeriod)
+}
+
+// Permanently delete the accounts whose grace period is over
+//
+// Content service is notified and the avatar files are removed first, So that if either fails then the account is retried in the next run
+func purgeDeletedAccounts(dbCfg *database.Config, ctx context.Context) {
+	userIDs, err := database.GetUsersDeletedBeforeDB(dbCfg, ctx, database.GetUsersDeletedBeforeParams{
+		DeletedAt: pgtype.Timestamp{
+			Time:  time.Now().UTC().Add(-GetDeletionGracePeriod()),
+			Valid: true,
+		},
+		Limit: purgeBatchSize,
+	})
+
+	if err != nil {
+		log.Errorln("error caught while fetching deleted accounts: ", err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		grpcResponse, err := notifyUserDeleted(userID.String())
+		if err != nil {
+			log.Errorln("error caught while notifying content service about deleted user: ", err, "user: ", userID)
+			continue
+		}
+
+		if err = DeleteUserAvatars(ctx, userID); err != nil {
+			log.Errorln("error caught while deleting avatar files: ", err, "user: ", userID)
+			continue
+		}
+
+		if err = database.DeleteUserDB(dbCfg, ctx, userID); err != nil {
+			log.Errorln("error caught while deleting user from DB: ", err, "user: ", userID)
+			continue
+		}
+
+		log.Infof("user %s deleted permanently along with %d contents", userID, grpcResponse.GetDeletedContent())
+	}
+}
+
+// Run the account purge periodically, Should be started in background
+func PurgeDeletedAccounts(dbCfg *database.Config) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purgeDeletedAccounts(dbCfg, context.Background())
+		<-ticker.C
+	}
+}
@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	maxAvatarFileSize  = 5 << 20
+	minAvatarDimension = 64
+	maxAvatarDimension = 4096
+)
+
+// Square sizes (in pixels) in which the avatar is stored
+var AvatarSizes = map[string]int{
+	"small":  64,
+	"medium": 256,
+	"large":  512,
+}
+
+var (
+	ErrUnsupportedImage = errors.New("only JPEG, PNG and WebP images are supported")
+	ErrImageTooLarge    = errors.New("image should be smaller than 5 MB")
+	ErrImageDimensions  = errors.New("image should be between 64x64 and 4096x4096 pixels")
+)
+
+var allowedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// Return the key where the user should upload the original avatar image
+func GetAvatarUploadKey(userID uuid.UUID, srcFilename string) string {
+	return "avatars/" + userID.String() + "/uploads/" + uuid.NewString() + strings.ToLower(path.Ext(srcFilename))
+}
+
+// Check weather or not the given key is an avatar upload key of the given user
+func IsAvatarUploadKey(userID uuid.UUID, key string) bool {
+	return strings.HasPrefix(key, "avatars/"+userID.String()+"/uploads/") && !strings.Contains(key, "..")
+}
+
+// Return the CDN URLs of all the avatar sizes
+func GetAvatarURLs(avatarKey string) map[string]string {
+	if avatarKey == "" {
+		return nil
+	}
+
+	cdnBaseURL := os.Getenv("AWS_CDN_BASE_URL")
+	urls := make(map[string]string, len(AvatarSizes))
+
+	for name := range AvatarSizes {
+		urls[name] = cdnBaseURL + "/" + avatarKey + "/" + name + ".jpg"
+	}
+	return urls
+}
+
+// Crop the center square of the given image and scale it to the given size
+func resizeAvatar(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+
+	crop := image.Rect(0, 0, side, side).Add(image.Point{
+		X: bounds.Min.X + (bounds.Dx()-side)/2,
+		Y: bounds.Min.Y + (bounds.Dy()-side)/2,
+	})
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+	return dst
+}
+
+// Validate the uploaded avatar image, Resize it into all the avatar sizes and upload them
+//
+// Returns the key prefix under which the resized images are stored
+func ProcessAvatar(ctx context.Context, userID uuid.UUID, uploadKey string) (string, error) {
+	client, err := getS3Client(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// Original image is not required once it is processed, Valid or not
+	defer func() {
+		if err := deleteMediaFiles(ctx, client, uploadKey); err != nil {
+			log.Errorln("error caught while deleting uploaded avatar: ", err, "key: ", uploadKey)
+		}
+	}()
+
+	data, err := downloadMediaFile(ctx, client, uploadKey, maxAvatarFileSize)
+	if errors.Is(err, errFileTooLarge) {
+		return "", ErrImageTooLarge
+	} else if err != nil {
+		return "", err
+	}
+
+	// Check the actual file content instead of trusting the file extension
+	if !allowedImageTypes[http.DetectContentType(data)] {
+		return "", ErrUnsupportedImage
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrUnsupportedImage
+	}
+
+	if config.Width < minAvatarDimension || config.Height < minAvatarDimension ||
+		config.Width > maxAvatarDimension || config.Height > maxAvatarDimension {
+		return "", ErrImageDimensions
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrUnsupportedImage
+	}
+
+	// A new prefix is used for every avatar, So that the CDN never serves a stale image
+	avatarKey := "avatars/" + userID.String() + "/" + uuid.NewString()
+
+	for name, size := range AvatarSizes {
+		var buf bytes.Buffer
+		if err = jpeg.Encode(&buf, resizeAvatar(src, size), &jpeg.Options{Quality: 90}); err != nil {
+			return "", err
+		}
+
+		if err = uploadMediaFile(ctx, client, avatarKey+"/"+name+".jpg", buf.Bytes(), "image/jpeg"); err != nil {
+			return "", err
+		}
+	}
+
+	return avatarKey, nil
+}
+
+// Remove all the resized images of the given avatar
+func DeleteAvatar(ctx context.Context, avatarKey string) error {
+	if avatarKey == "" {
+		return nil
+	}
+
+	client, err := getS3Client(ctx)
+	if err != nil {
+		return err
+	}
+	return deleteMediaFiles(ctx, client, avatarKey+"/")
+}
+
+// Remove all the avatar files of the user, Including the uploaded images which were never processed
+func DeleteUserAvatars(ctx context.Context, userID uuid.UUID) error {
+	client, err := getS3Client(ctx)
+	if err != nil {
+		return err
+	}
+	return deleteMediaFiles(ctx, client, "avatars/"+userID.String()+"/")
+}
@@ -0,0 +1,212 @@
+package internal
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	log "github.com/sirupsen/logrus"
+	"github.com/thejasmeetsingh/spotify-clone/src/services/user/database"
+)
+
+const (
+	// Export archives are removed from s3 by the bucket lifecycle rule after this duration
+	ExportRetention = 7 * 24 * time.Hour
+
+	// Duration for which the download link of an export archive is valid
+	ExportLinkExpiry = 15 * time.Minute
+
+	// Pending exports which are not completed in this duration are considered interrupted
+	exportBuildTimeout = 30 * time.Minute
+
+	// Interrupted exports are built again until they are this old, After which they are marked as failed
+	exportRetryWindow = 2 * time.Hour
+)
+
+type exportedProfile struct {
+	ID         uuid.UUID  `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ModifiedAt time.Time  `json:"modified_at"`
+	Email      string     `json:"email"`
+	Name       string     `json:"name"`
+	DeletedAt  *time.Time `json:"deleted_at"`
+}
+
+type exportedIdentity struct {
+	CreatedAt time.Time `json:"created_at"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+}
+
+// Write the given value as an indented JSON file into the archive
+func writeJSONFile(archive *zip.Writer, name string, value any) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// Collect the user data from all the services and return it as a ZIP archive
+func buildExportArchive(dbCfg *database.Config, ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	dbUser, err := database.GetUserByIDFromDB(dbCfg, ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	dbIdentities, err := database.GetUserIdentitiesDB(dbCfg, ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	grpcResponse, err := exportContentData(userID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	profile := exportedProfile{
+		ID:         dbUser.ID,
+		CreatedAt:  dbUser.CreatedAt.Time,
+		ModifiedAt: dbUser.ModifiedAt.Time,
+		Email:      dbUser.Email,
+		Name:       dbUser.Name.String,
+	}
+	if dbUser.DeletedAt.Valid {
+		profile.DeletedAt = &dbUser.DeletedAt.Time
+	}
+
+	identities := make([]exportedIdentity, 0, len(dbIdentities))
+	for _, dbIdentity := range dbIdentities {
+		identities = append(identities, exportedIdentity{
+			CreatedAt: dbIdentity.CreatedAt.Time,
+			Provider:  dbIdentity.Provider,
+			Subject:   dbIdentity.Subject,
+			Email:     dbIdentity.Email.String,
+		})
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	files := map[string]any{
+		"profile.json":            profile,
+		"identities.json":         identities,
+		"content.json":            grpcResponse.GetContent(),
+		"plays.json":              grpcResponse.GetPlays(),
+		"likes.json":              grpcResponse.GetLikes(),
+		"saved_albums.json":       grpcResponse.GetSavedAlbums(),
+		"playback_positions.json": grpcResponse.GetPlaybackPositions(),
+	}
+
+	for name, value := range files {
+		if err = writeJSONFile(archive, name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Build the data export archive, Upload it to s3 and update the export status
+//
+// Should be called in background since collecting the data from all the services can take a while
+func BuildDataExport(dbCfg *database.Config, exportID, userID uuid.UUID) {
+	ctx := context.Background()
+
+	// Build is stopped before the export is considered interrupted, So that it's not built twice at the same time
+	buildCtx, cancel := context.WithTimeout(ctx, exportBuildTimeout)
+	defer cancel()
+
+	params := database.UpdateDataExportParams{
+		ID:     exportID,
+		Status: database.ExportStatusF,
+	}
+
+	archive, err := buildExportArchive(dbCfg, buildCtx, userID)
+	if err != nil {
+		log.Errorln("error caught while building data export archive: ", err)
+	} else {
+		key := "exports/" + userID.String() + "/" + exportID.String() + ".zip"
+
+		if err = uploadExportFile(buildCtx, key, archive, "application/zip"); err != nil {
+			log.Errorln("error caught while uploading data export archive: ", err)
+		} else {
+			params.Status = database.ExportStatusR
+			params.S3Key = pgtype.Text{
+				String: key,
+				Valid:  true,
+			}
+		}
+	}
+
+	params.ModifiedAt = pgtype.Timestamp{
+		Time:  time.Now().UTC(),
+		Valid: true,
+	}
+
+	if err = database.UpdateDataExportDB(dbCfg, ctx, params); err != nil {
+		log.Errorln("error caught while updating data export status: ", err)
+	}
+}
+
+// Build the interrupted data exports again and fail the ones which are interrupted for too long
+func retryStaleDataExports(dbCfg *database.Config, ctx context.Context) {
+	currentTime := time.Now().UTC()
+
+	if err := database.FailStaleDataExportsDB(dbCfg, ctx, database.FailStaleDataExportsParams{
+		ModifiedAt: pgtype.Timestamp{
+			Time:  currentTime,
+			Valid: true,
+		},
+		CreatedAt: pgtype.Timestamp{
+			Time:  currentTime.Add(-exportRetryWindow),
+			Valid: true,
+		},
+	}); err != nil {
+		log.Errorln("error caught while failing stale data exports: ", err)
+		return
+	}
+
+	// Exports are claimed by updating their modified time, So they are not retried again until the build timeout
+	dataExports, err := database.ClaimStaleDataExportsDB(dbCfg, ctx, database.ClaimStaleDataExportsParams{
+		ClaimedAt: pgtype.Timestamp{
+			Time:  currentTime,
+			Valid: true,
+		},
+		StaleBefore: pgtype.Timestamp{
+			Time:  currentTime.Add(-exportBuildTimeout),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		log.Errorln("error caught while claiming stale data exports: ", err)
+		return
+	}
+
+	for _, dataExport := range dataExports {
+		log.Infoln("retrying interrupted data export: ", dataExport.ID)
+		BuildDataExport(dbCfg, dataExport.ID, dataExport.UserID)
+	}
+}
+
+// Retry the interrupted data exports on startup and then periodically, Should be started in background
+func RetryStaleDataExports(dbCfg *database.Config) {
+	ticker := time.NewTicker(exportBuildTimeout)
+	defer ticker.Stop()
+
+	for {
+		retryStaleDataExports(dbCfg, context.Background())
+		<-ticker.C
+	}
+}
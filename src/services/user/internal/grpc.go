@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	"github.com/google/uuid"
+	contentPB "github.com/thejasmeetsingh/spotify-clone/src/services/common/contentpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// Play history of the active users can be larger than the default message size limit (4MB)
+const exportMaxMessageSize = 64 << 20
+
+var contentAddr = flag.String("contentAddr", os.Getenv("CONTENT_GRPC_ADDRESS"), "content gRPC server address")
+
+// Connect with the content service, Returns the client along with the authorized context for its calls
+func getContentClient() (contentPB.ContentServiceClient, *grpc.ClientConn, context.Context, error) {
+	flag.Parse()
+
+	conn, err := grpc.Dial(*contentAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	md := metadata.Pairs("authorization", "Bearer "+os.Getenv("GRPC_AUTH_KEY"))
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	return contentPB.NewContentServiceClient(conn), conn, ctx, nil
+}
+
+// gRPC to content service for removing all the data of a deleted user
+func notifyUserDeleted(userID string) (*contentPB.UserDeletedResponse, error) {
+	c, conn, ctx, err := getContentClient()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var header, trailer metadata.MD
+	r, err := c.UserDeleted(ctx, &contentPB.UserDeletedRequest{UserId: userID}, grpc.Header(&header), grpc.Trailer(&trailer))
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// gRPC to content service for rejecting the tokens of a user whose account is scheduled for deletion
+func NotifyUserDeactivated(userID uuid.UUID) error {
+	c, conn, ctx, err := getContentClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = c.UserDeactivated(ctx, &contentPB.UserDeactivatedRequest{UserId: userID.String()})
+	return err
+}
+
+// gRPC to content service for accepting the tokens of a restored user again
+func NotifyUserRestored(userID uuid.UUID) error {
+	c, conn, ctx, err := getContentClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = c.UserRestored(ctx, &contentPB.UserRestoredRequest{UserId: userID.String()})
+	return err
+}
+
+// gRPC to content service for collecting the data of a user for a data export request
+func exportContentData(userID string) (*contentPB.ExportUserDataResponse, error) {
+	c, conn, ctx, err := getContentClient()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var header, trailer metadata.MD
+	r, err := c.ExportUserData(ctx, &contentPB.ExportUserDataRequest{UserId: userID}, grpc.Header(&header), grpc.Trailer(&trailer), grpc.MaxCallRecvMsgSize(exportMaxMessageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
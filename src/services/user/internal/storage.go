@@ -0,0 +1,161 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+var errFileTooLarge = errors.New("file is too large")
+
+func getS3Client(ctx context.Context) (*s3.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s3.NewFromConfig(cfg), nil
+}
+
+// Upload the given file to the private exports bucket
+func uploadExportFile(ctx context.Context, key string, data []byte, contentType string) error {
+	client, err := getS3Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(os.Getenv("AWS_EXPORT_BUCKET_NAME")),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+	})
+	return err
+}
+
+// Return a pre-signed URL for downloading the given file from the private exports bucket
+func GetExportDownloadURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	client, err := getS3Client(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := s3.NewPresignClient(client).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(os.Getenv("AWS_EXPORT_BUCKET_NAME")),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expires))
+
+	if err != nil {
+		return "", err
+	}
+	return res.URL, nil
+}
+
+// Return a pre-signed URL for uploading a file to the media bucket
+func GetUploadURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	client, err := getS3Client(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := s3.NewPresignClient(client).PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(os.Getenv("AWS_BUCKET_NAME")),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expires))
+
+	if err != nil {
+		return "", err
+	}
+	return res.URL, nil
+}
+
+// Download a file from the media bucket, Files larger than maxSize are rejected
+func downloadMediaFile(ctx context.Context, client *s3.Client, key string, maxSize int64) ([]byte, error) {
+	result, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(os.Getenv("AWS_BUCKET_NAME")),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer result.Body.Close()
+
+	if aws.ToInt64(result.ContentLength) > maxSize {
+		return nil, errFileTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(result.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, errFileTooLarge
+	}
+	return data, nil
+}
+
+// Upload a publicly readable file to the media bucket, So that it can be served via CDN
+func uploadMediaFile(ctx context.Context, client *s3.Client, key string, data []byte, contentType string) error {
+	_, err := client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(os.Getenv("AWS_BUCKET_NAME")),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ACL:         types.ObjectCannedACLPublicRead,
+		ContentType: aws.String(contentType),
+	})
+	return err
+}
+
+// Delete all the files from the media bucket having the given prefix
+func deleteMediaFiles(ctx context.Context, client *s3.Client, prefix string) error {
+	bucket := aws.String(os.Getenv("AWS_BUCKET_NAME"))
+
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: bucket,
+		Prefix: aws.String(prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(page.Contents) == 0 {
+			continue
+		}
+
+		objects := make([]types.ObjectIdentifier, 0, len(page.Contents))
+		for _, object := range page.Contents {
+			objects = append(objects, types.ObjectIdentifier{Key: object.Key})
+		}
+
+		res, err := client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: bucket,
+			Delete: &types.Delete{
+				Objects: objects,
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Objects which could not be deleted are reported in the response instead of an error
+		if len(res.Errors) != 0 {
+			return fmt.Errorf("failed to delete %d objects, first error: %s %s",
+				len(res.Errors), aws.ToString(res.Errors[0].Key), aws.ToString(res.Errors[0].Message))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+// Contain the OpenID Connect provider abstraction used for social login
+//
+// Providers are configured via environment variables:
+//
+//	OIDC_PROVIDERS=google,mock
+//	OIDC_<NAME>_ISSUER=https://accounts.google.com
+//	OIDC_<NAME>_CLIENT_ID=...
+//	OIDC_<NAME>_CLIENT_SECRET=...
+//	OIDC_<NAME>_REDIRECT_URL=http://localhost:8000/user/api/v1/oidc/<name>/callback/
+//
+// Provider endpoints are discovered from the issuer, So any compliant provider (Including a local mock) can be used.
+
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// Details of the user returned by the provider after a successful login
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type Provider interface {
+	Name() string
+
+	// Return the URL where user should be redirected for login
+	AuthCodeURL(state, nonce, codeVerifier string) string
+
+	// Exchange the authorization code for tokens, Verify the ID token and return the identity encoded in it
+	Exchange(ctx context.Context, code, nonce, codeVerifier string) (*Identity, error)
+}
+
+type provider struct {
+	name     string
+	config   oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+func (p *provider) Name() string {
+	return p.name
+}
+
+func (p *provider) AuthCodeURL(state, nonce, codeVerifier string) string {
+	return p.config.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier))
+}
+
+func (p *provider) Exchange(ctx context.Context, code, nonce, codeVerifier string) (*Identity, error) {
+	token, err := p.config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("id_token is missing in the token response")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if idToken.Nonce != nonce {
+		return nil, fmt.Errorf("invalid nonce in ID token")
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err = idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	return &Identity{
+		Provider:      p.name,
+		Subject:       idToken.Subject,
+		Email:         strings.ToLower(claims.Email),
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
+
+// Create a provider by discovering the endpoints from the given issuer
+func NewProvider(ctx context.Context, name, issuer, clientID, clientSecret, redirectURL string) (Provider, error) {
+	oidcProvider, err := gooidc.NewProvider(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &provider{
+		name: name,
+		config: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Endpoint:     oidcProvider.Endpoint(),
+			Scopes:       []string{gooidc.ScopeOpenID, "email", "profile"},
+		},
+		verifier: oidcProvider.Verifier(&gooidc.Config{ClientID: clientID}),
+	}, nil
+}
+
+var (
+	providers   = make(map[string]Provider)
+	providersMu sync.Mutex
+)
+
+// Return the provider configured with the given name
+//
+// Discovery is done on the first use and the provider is cached afterwards,
+// So that the service can start even if a provider is not reachable at the moment.
+func GetProvider(ctx context.Context, name string) (Provider, error) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	if p, exists := providers[name]; exists {
+		return p, nil
+	}
+
+	if !isEnabled(name) {
+		return nil, fmt.Errorf("unknown OIDC provider: %q", name)
+	}
+
+	prefix := "OIDC_" + strings.ToUpper(name) + "_"
+	p, err := NewProvider(
+		ctx,
+		name,
+		os.Getenv(prefix+"ISSUER"),
+		os.Getenv(prefix+"CLIENT_ID"),
+		os.Getenv(prefix+"CLIENT_SECRET"),
+		os.Getenv(prefix+"REDIRECT_URL"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	providers[name] = p
+	return p, nil
+}
+
+// Check weather or not the given provider is listed in OIDC_PROVIDERS
+func isEnabled(name string) bool {
+	for _, enabled := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		if strings.TrimSpace(enabled) == name {
+			return true
+		}
+	}
+	return false
+}
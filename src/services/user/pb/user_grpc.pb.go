@@ -19,14 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	UserService_UserDetail_FullMethodName = "/user.UserService/UserDetail"
+	UserService_UsersDetail_FullMethodName = "/user.UserService/UsersDetail"
 )
 
 // UserServiceClient is the client API for UserService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type UserServiceClient interface {
-	UserDetail(ctx context.Context, in *UserDetailRequest, opts ...grpc.CallOption) (*UserDetailResponse, error)
+	UsersDetail(ctx context.Context, in *UsersDetailRequest, opts ...grpc.CallOption) (*UsersDetailResponse, error)
 }
 
 type userServiceClient struct {
@@ -37,9 +37,9 @@ func NewUserServiceClient(cc grpc.ClientConnInterface) UserServiceClient {
 	return &userServiceClient{cc}
 }
 
-func (c *userServiceClient) UserDetail(ctx context.Context, in *UserDetailRequest, opts ...grpc.CallOption) (*UserDetailResponse, error) {
-	out := new(UserDetailResponse)
-	err := c.cc.Invoke(ctx, UserService_UserDetail_FullMethodName, in, out, opts...)
+func (c *userServiceClient) UsersDetail(ctx context.Context, in *UsersDetailRequest, opts ...grpc.CallOption) (*UsersDetailResponse, error) {
+	out := new(UsersDetailResponse)
+	err := c.cc.Invoke(ctx, UserService_UsersDetail_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -50,7 +50,7 @@ func (c *userServiceClient) UserDetail(ctx context.Context, in *UserDetailReques
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility
 type UserServiceServer interface {
-	UserDetail(context.Context, *UserDetailRequest) (*UserDetailResponse, error)
+	UsersDetail(context.Context, *UsersDetailRequest) (*UsersDetailResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -58,8 +58,8 @@ type UserServiceServer interface {
 type UnimplementedUserServiceServer struct {
 }
 
-func (UnimplementedUserServiceServer) UserDetail(context.Context, *UserDetailRequest) (*UserDetailResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UserDetail not implemented")
+func (UnimplementedUserServiceServer) UsersDetail(context.Context, *UsersDetailRequest) (*UsersDetailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UsersDetail not implemented")
 }
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 
@@ -74,20 +74,20 @@ func RegisterUserServiceServer(s grpc.ServiceRegistrar, srv UserServiceServer) {
 	s.RegisterService(&UserService_ServiceDesc, srv)
 }
 
-func _UserService_UserDetail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UserDetailRequest)
+func _UserService_UsersDetail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsersDetailRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UserServiceServer).UserDetail(ctx, in)
+		return srv.(UserServiceServer).UsersDetail(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UserService_UserDetail_FullMethodName,
+		FullMethod: UserService_UsersDetail_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServiceServer).UserDetail(ctx, req.(*UserDetailRequest))
+		return srv.(UserServiceServer).UsersDetail(ctx, req.(*UsersDetailRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -100,8 +100,8 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 	HandlerType: (*UserServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "UserDetail",
-			Handler:    _UserService_UserDetail_Handler,
+			MethodName: "UsersDetail",
+			Handler:    _UserService_UsersDetail_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -0,0 +1,20 @@
+-- name: CreateDataExport :one
+INSERT INTO data_exports (id, created_at, modified_at, user_id, status)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetDataExport :one
+SELECT * FROM data_exports WHERE id=$1 AND user_id=$2;
+
+-- name: UpdateDataExport :exec
+UPDATE data_exports SET status=$1, s3_key=$2, modified_at=$3
+WHERE id=$4;
+
+-- name: FailStaleDataExports :exec
+UPDATE data_exports SET status='F', modified_at=$1
+WHERE status='P' AND created_at < $2;
+
+-- name: ClaimStaleDataExports :many
+UPDATE data_exports SET modified_at=sqlc.arg('claimed_at')
+WHERE status='P' AND modified_at < sqlc.arg('stale_before')
+RETURNING *;
@@ -0,0 +1,10 @@
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (id, created_at, modified_at, user_id, provider, subject, email)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetUserIdentity :one
+SELECT * FROM user_identities WHERE provider=$1 AND subject=$2;
+
+-- name: GetUserIdentities :many
+SELECT * FROM user_identities WHERE user_id=$1 ORDER BY created_at;
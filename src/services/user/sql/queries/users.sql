@@ -19,4 +19,25 @@ UPDATE users SET password=$1, modified_at=$2
 WHERE id=$3;
 
 -- name: DeleteUser :exec
-DELETE FROM users WHERE id=$1;
\ No newline at end of file
+DELETE FROM users WHERE id=$1;
+
+-- name: SoftDeleteUser :exec
+UPDATE users SET deleted_at=$1, modified_at=$2
+WHERE id=$3;
+
+-- name: RestoreUser :one
+UPDATE users SET deleted_at=NULL, modified_at=$1
+WHERE id=$2
+RETURNING *;
+
+-- name: GetUsersDeletedBefore :many
+SELECT id FROM users WHERE deleted_at < $1 ORDER BY deleted_at LIMIT $2;
+
+
+-- name: UpdateUserAvatar :one
+UPDATE users SET avatar_key=$1, modified_at=$2
+WHERE id=$3
+RETURNING *;
+
+-- name: GetUsersByIds :many
+SELECT * FROM users WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL;
@@ -0,0 +1,22 @@
+-- +goose Up
+
+-- ('P', 'Pending')
+-- ('R', 'Ready')
+-- ('F', 'Failed')
+CREATE TYPE export_status AS ENUM ('P', 'R', 'F');
+
+CREATE TABLE data_exports (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    modified_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status export_status NOT NULL,
+    s3_key TEXT
+);
+
+CREATE INDEX data_exports_user_id_idx ON data_exports (user_id);
+
+-- +goose Down
+DROP TABLE data_exports;
+
+DROP TYPE export_status;
@@ -0,0 +1,6 @@
+-- +goose Up
+
+ALTER TABLE users ADD COLUMN avatar_key TEXT;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN avatar_key;
@@ -0,0 +1,7 @@
+-- +goose Up
+
+-- A user can have only one export which is being prepared
+CREATE UNIQUE INDEX data_exports_pending_user_id_idx ON data_exports (user_id) WHERE status='P';
+
+-- +goose Down
+DROP INDEX data_exports_pending_user_id_idx;
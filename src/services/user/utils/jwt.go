@@ -11,8 +11,15 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	AccessToken     = "access"
+	RefreshToken    = "refresh"
+	LoginStateToken = "login_state"
+)
+
 type Claims struct {
 	Data string `json:"data"`
+	Type string `json:"type"`
 	jwt.RegisteredClaims
 }
 
@@ -21,15 +28,6 @@ type Tokens struct {
 	Refresh string `json:"refresh"`
 }
 
-func getSecretKey() []byte {
-	secretKey := os.Getenv("SECRET_KEY")
-	if secretKey == "" {
-		secretKey = "random-secret-123"
-	}
-
-	return []byte(secretKey)
-}
-
 // Return the access token and refresh token expiry duration
 func getTokenExpiration() (time.Duration, time.Duration) {
 	accessTokenExp := os.Getenv("ACCESS_TOKEN_EXP")
@@ -48,33 +46,45 @@ func getTokenExpiration() (time.Duration, time.Duration) {
 	return time.Hour * 24 * time.Duration(accessTokenExpiration), time.Hour * 24 * time.Duration(refreshTokenExpiration)
 }
 
+// Sign the given claims with the active signing key
+//
+// KID of the signing key is added in the token header, So that the verifier can pick the right public key
+func signToken(claims jwt.Claims) (string, error) {
+	key, err := getSigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.PrivateKey)
+}
+
 // Generate the access and refresh tokens and encode the given userID string
 func GenerateTokens(userID string) (Tokens, error) {
 	accessTokenExp, refreshTokenExp := getTokenExpiration()
-	secretKey := getSecretKey()
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+	accessTokenString, err := signToken(&Claims{
 		Data: userID,
+		Type: AccessToken,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessTokenExp)),
 		},
 	})
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	refreshTokenString, err := signToken(&Claims{
 		Data: userID,
+		Type: RefreshToken,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(refreshTokenExp)),
 		},
 	})
 
-	accessTokenString, err := accessToken.SignedString(secretKey)
-
-	if err != nil {
-		return Tokens{}, err
-	}
-
-	refreshTokenString, err := refreshToken.SignedString(secretKey)
-
 	if err != nil {
 		return Tokens{}, err
 	}
@@ -87,11 +97,13 @@ func GenerateTokens(userID string) (Tokens, error) {
 
 // Verify the given token string is valid or not and return the respected token claim which contains the encoded data
 func VerifyToken(tokenString string) (*Claims, error) {
-	secretKey := getSecretKey()
-
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return secretKey, nil
-	})
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, fmt.Errorf("missing key ID in token header")
+		}
+		return getVerificationKey(kid)
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}))
 
 	if err != nil {
 		return nil, err
@@ -113,6 +125,10 @@ func ReIssueAccessToken(refreshToken string) (Tokens, error) {
 		return Tokens{}, err
 	}
 
+	if claims.Type != RefreshToken {
+		return Tokens{}, fmt.Errorf("invalid refresh token")
+	}
+
 	if time.Unix(claims.ExpiresAt.Unix(), 0).After(time.Now()) {
 		return GenerateTokens(claims.Data)
 	}
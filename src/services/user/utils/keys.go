@@ -0,0 +1,206 @@
+// Contain the signing key related functions used for issuing and verifying JWT tokens
+//
+// Keys are loaded from the directory given in JWT_KEYS_DIR, Each file is named as <KID>.pem
+// and contains either an Ed25519 private key (PKCS8) or a public key (PKIX).
+//
+// Private keys can be used for signing and verification, Public keys are kept only for
+// verifying the tokens which were signed by a retired key, So that keys can be rotated without
+// logging out the users.
+
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type signingKey struct {
+	ID         string
+	PrivateKey ed25519.PrivateKey
+}
+
+type keySet struct {
+	active     *signingKey
+	publicKeys map[string]ed25519.PublicKey
+}
+
+// JSON Web Key as defined in RFC 8037 for Ed25519 public keys
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	X   string `json:"x"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+var (
+	loadedKeySet *keySet
+	keySetErr    error
+	keySetOnce   sync.Once
+)
+
+// Parse the given PEM data into an Ed25519 public key and an optional private key
+func parseKey(data []byte) (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, nil, fmt.Errorf("invalid PEM data")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, nil, fmt.Errorf("private key is not an Ed25519 key")
+		}
+		return privateKey.Public().(ed25519.PublicKey), privateKey, nil
+
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, nil, fmt.Errorf("public key is not an Ed25519 key")
+		}
+		return publicKey, nil, nil
+	}
+
+	return nil, nil, fmt.Errorf("unsupported PEM block type: %s", block.Type)
+}
+
+// Load all the keys present in the keys directory
+//
+// Signing key is picked by JWT_ACTIVE_KEY_ID, If not given then the last private key
+// (sorted by KID) is used. So naming the keys by their creation date works out of the box.
+func loadKeySet() (*keySet, error) {
+	keysDir := os.Getenv("JWT_KEYS_DIR")
+	if keysDir == "" {
+		return nil, fmt.Errorf("JWT_KEYS_DIR is not configured")
+	}
+
+	files, err := filepath.Glob(filepath.Join(keysDir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	set := &keySet{publicKeys: make(map[string]ed25519.PublicKey)}
+	privateKeys := make(map[string]ed25519.PrivateKey)
+	var lastPrivateKID string
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		publicKey, privateKey, err := parseKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing key %s: %w", file, err)
+		}
+
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+		set.publicKeys[kid] = publicKey
+
+		if privateKey != nil {
+			privateKeys[kid] = privateKey
+			lastPrivateKID = kid
+		}
+	}
+
+	activeKID := os.Getenv("JWT_ACTIVE_KEY_ID")
+	if activeKID == "" {
+		activeKID = lastPrivateKID
+	}
+
+	privateKey, exists := privateKeys[activeKID]
+	if !exists {
+		return nil, fmt.Errorf("no private key found for the active key ID: %q", activeKID)
+	}
+
+	set.active = &signingKey{
+		ID:         activeKID,
+		PrivateKey: privateKey,
+	}
+
+	return set, nil
+}
+
+func getKeySet() (*keySet, error) {
+	keySetOnce.Do(func() {
+		loadedKeySet, keySetErr = loadKeySet()
+	})
+	return loadedKeySet, keySetErr
+}
+
+// Return the key which should be used for signing the new tokens
+func getSigningKey() (*signingKey, error) {
+	set, err := getKeySet()
+	if err != nil {
+		return nil, err
+	}
+	return set.active, nil
+}
+
+// Return the public key for the given KID
+func getVerificationKey(kid string) (crypto.PublicKey, error) {
+	set, err := getKeySet()
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, exists := set.publicKeys[kid]
+	if !exists {
+		return nil, fmt.Errorf("unknown key ID: %q", kid)
+	}
+	return publicKey, nil
+}
+
+// Return all the public keys as JWKS, So that other services can verify the tokens locally
+func GetJWKS() (JWKS, error) {
+	set, err := getKeySet()
+	if err != nil {
+		return JWKS{}, err
+	}
+
+	kids := make([]string, 0, len(set.publicKeys))
+	for kid := range set.publicKeys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	jwks := JWKS{Keys: make([]JWK, 0, len(kids))}
+	for _, kid := range kids {
+		jwks.Keys = append(jwks.Keys, JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			Use: "sig",
+			Alg: "EdDSA",
+			Kid: kid,
+			X:   base64.RawURLEncoding.EncodeToString(set.publicKeys[kid]),
+		})
+	}
+
+	return jwks, nil
+}
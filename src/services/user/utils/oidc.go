@@ -0,0 +1,93 @@
+// Contain the functions for keeping the OIDC login state between the login and callback requests
+//
+// State is signed with the JWT signing key and sent to the client as a cookie,
+// So that there is no need to store it on the server. It carries its own type & audience,
+// So it can't be used in place of the access or refresh tokens and vice versa.
+
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+const (
+	loginStateExp      = 10 * time.Minute
+	loginStateAudience = "oidc-callback"
+)
+
+type LoginState struct {
+	Type     string `json:"type"`
+	Provider string `json:"provider"`
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	jwt.RegisteredClaims
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Generate a new login state for the given provider and return it along with its signed value
+func GenerateLoginState(provider string) (*LoginState, string, error) {
+	state, err := randomString()
+	if err != nil {
+		return nil, "", err
+	}
+
+	nonce, err := randomString()
+	if err != nil {
+		return nil, "", err
+	}
+
+	loginState := &LoginState{
+		Type:     LoginStateToken,
+		Provider: provider,
+		State:    state,
+		Nonce:    nonce,
+		Verifier: oauth2.GenerateVerifier(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{loginStateAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(loginStateExp)),
+		},
+	}
+
+	signedState, err := signToken(loginState)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return loginState, signedState, nil
+}
+
+// Verify the signed login state and return the decoded state
+func VerifyLoginState(signedState string) (*LoginState, error) {
+	token, err := jwt.ParseWithClaims(signedState, &LoginState{}, func(token *jwt.Token) (interface{}, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, fmt.Errorf("missing key ID in token header")
+		}
+		return getVerificationKey(kid)
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}), jwt.WithExpirationRequired(), jwt.WithAudience(loginStateAudience))
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Tokens signed with the same key are rejected by their type
+	if loginState, ok := token.Claims.(*LoginState); ok && token.Valid && loginState.Type == LoginStateToken {
+		return loginState, nil
+	}
+
+	return nil, fmt.Errorf("invalid login state")
+}
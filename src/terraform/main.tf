@@ -54,6 +54,40 @@ resource "aws_s3_bucket_cors_configuration" "spotify_bucket_cors" {
   }
 }
 
+## S3 Configuration for user data exports ##
+
+# Exports contain personal data, So they are kept in a separate private bucket and shared via pre-signed URLs only
+resource "aws_s3_bucket" "spotify_exports_bucket" {
+  bucket        = "spotify-clone-exports-bucket"
+  force_destroy = true
+}
+
+resource "aws_s3_bucket_public_access_block" "spotify_exports_bucket_public_access" {
+  bucket = aws_s3_bucket.spotify_exports_bucket.id
+
+  block_public_acls       = true
+  block_public_policy     = true
+  ignore_public_acls      = true
+  restrict_public_buckets = true
+}
+
+resource "aws_s3_bucket_lifecycle_configuration" "spotify_exports_bucket_lifecycle" {
+  bucket = aws_s3_bucket.spotify_exports_bucket.id
+
+  rule {
+    id     = "expire-exports"
+    status = "Enabled"
+
+    filter {
+      prefix = "exports/"
+    }
+
+    expiration {
+      days = 7
+    }
+  }
+}
+
 ## CloudFront Configuration ##
 
 resource "aws_cloudfront_origin_access_identity" "spotify_oai" {